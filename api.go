@@ -0,0 +1,97 @@
+package smartapigo
+
+import (
+	"context"
+)
+
+// SmartAPI is the interface of the API methods of Client. Code depending on it
+// instead of *Client can be tested with the fake client of package
+// github.com/shammishailaj/smartapigo/fake.
+type SmartAPI interface {
+	CancelAllOrders(filter OrderFilter) ([]CancelResult, error)
+	CancelAllOrdersCtx(ctx context.Context, filter OrderFilter) ([]CancelResult, error)
+	CancelGTTOCO(oco GTTOCO) error
+	CancelGTTOCOCtx(ctx context.Context, oco GTTOCO) error
+	CancelGTTRule(cancelGTTParams CancelGTTParams) (GTTRuleResponse, error)
+	CancelGTTRuleCtx(ctx context.Context, cancelGTTParams CancelGTTParams) (GTTRuleResponse, error)
+	CancelOrder(variety string, orderid string) (OrderResponse, error)
+	CancelOrderCtx(ctx context.Context, variety string, orderid string) (OrderResponse, error)
+	ConvertPosition(convertPositionParams ConvertPositionParams) error
+	ConvertPositionCtx(ctx context.Context, convertPositionParams ConvertPositionParams) error
+	CreateGTTOCO(params GTTOCOParams) (GTTOCO, error)
+	CreateGTTOCOCtx(ctx context.Context, params GTTOCOParams) (GTTOCO, error)
+	CreateGTTRule(gttParams GTTParams) (GTTRuleResponse, error)
+	CreateGTTRuleCtx(ctx context.Context, gttParams GTTParams) (GTTRuleResponse, error)
+	GenerateSession(totp string) (UserSession, error)
+	GenerateSessionCtx(ctx context.Context, totp string) (UserSession, error)
+	GenerateTPIN(generateTPINParams GenerateTPINParams) (bool, error)
+	GenerateTPINCtx(ctx context.Context, generateTPINParams GenerateTPINParams) (bool, error)
+	GetAllGTTRules(statuses []GTTStatus, pageSize int) (GTTRules, error)
+	GetAllGTTRulesCtx(ctx context.Context, statuses []GTTStatus, pageSize int) (GTTRules, error)
+	GetAllHoldings() (AllHoldings, error)
+	GetAllHoldingsCtx(ctx context.Context) (AllHoldings, error)
+	GetBasketMargin(basket []OrderParams) (BasketMargin, error)
+	GetBasketMarginCtx(ctx context.Context, basket []OrderParams) (BasketMargin, error)
+	GetCandleData(params *HistoryParams) ([]HistoryDatum, error)
+	GetCandleDataCtx(ctx context.Context, params *HistoryParams) ([]HistoryDatum, error)
+	GetDISStatus(reqID string) (DISStatus, error)
+	GetDISStatusCtx(ctx context.Context, reqID string) (DISStatus, error)
+	GetGTTRuleDetails(ruleID GTTRuleID) (GTTRule, error)
+	GetGTTRuleDetailsCtx(ctx context.Context, ruleID GTTRuleID) (GTTRule, error)
+	GetGTTRuleList(statuses []GTTStatus, page int, count int) (GTTRules, error)
+	GetGTTRuleListCtx(ctx context.Context, statuses []GTTStatus, page int, count int) (GTTRules, error)
+	GetHoldings() (Holdings, error)
+	GetHoldingsCtx(ctx context.Context) (Holdings, error)
+	GetLTP(ltpParams LTPParams) (LTPResponse, error)
+	GetLTPCtx(ctx context.Context, ltpParams LTPParams) (LTPResponse, error)
+	GetMargin(positions []MarginPosition) (MarginResponse, error)
+	GetMarginCtx(ctx context.Context, positions []MarginPosition) (MarginResponse, error)
+	GetMarketData(mode QuoteMode, exchangeTokens map[Exchange][]string) (MarketData, error)
+	GetMarketDataCtx(ctx context.Context, mode QuoteMode, exchangeTokens map[Exchange][]string) (MarketData, error)
+	GetOIBuildup(expiryType ExpiryType, dataType OIBuildupType) (OIBuildups, error)
+	GetOIBuildupCtx(ctx context.Context, expiryType ExpiryType, dataType OIBuildupType) (OIBuildups, error)
+	GetOrderBook() (Orders, error)
+	GetOrderBookCtx(ctx context.Context) (Orders, error)
+	GetOrdersByTag(tag string) (Orders, error)
+	GetOrdersByTagCtx(ctx context.Context, tag string) (Orders, error)
+	GetPositions() (Positions, error)
+	GetPositionsCtx(ctx context.Context) (Positions, error)
+	GetRMS() (RMS, error)
+	GetRMSCtx(ctx context.Context) (RMS, error)
+	GetSlicedOrder(sliced SlicedOrder) (SlicedOrder, error)
+	GetSlicedOrderCtx(ctx context.Context, sliced SlicedOrder) (SlicedOrder, error)
+	GetTradeBook() (Trades, error)
+	GetTradeBookCtx(ctx context.Context) (Trades, error)
+	GetTradesByOrderID(orderID string) (Fills, error)
+	GetTradesByOrderIDCtx(ctx context.Context, orderID string) (Fills, error)
+	GetUserProfile() (UserProfile, error)
+	GetUserProfileCtx(ctx context.Context) (UserProfile, error)
+	Logout() (bool, error)
+	LogoutCtx(ctx context.Context) (bool, error)
+	ModifyGTTOCO(oco GTTOCO, params GTTOCOParams) error
+	ModifyGTTOCOCtx(ctx context.Context, oco GTTOCO, params GTTOCOParams) error
+	ModifyGTTRule(modifyGTTParams ModifyGTTParams) (GTTRuleResponse, error)
+	ModifyGTTRuleCtx(ctx context.Context, modifyGTTParams ModifyGTTParams) (GTTRuleResponse, error)
+	ModifyOrder(modifyOrderParams ModifyOrderParams) (OrderResponse, error)
+	ModifyOrderCtx(ctx context.Context, modifyOrderParams ModifyOrderParams) (OrderResponse, error)
+	ModifyOrderPartial(orderID string, changes OrderChanges) (OrderResponse, error)
+	ModifyOrderPartialCtx(ctx context.Context, orderID string, changes OrderChanges) (OrderResponse, error)
+	PlaceOrder(orderParams OrderParams) (OrderResponse, error)
+	PlaceOrderCtx(ctx context.Context, orderParams OrderParams) (OrderResponse, error)
+	PlaceOrders(orders []OrderParams) []PlaceResult
+	PlaceOrdersCtx(ctx context.Context, orders []OrderParams) []PlaceResult
+	PlaceSlicedOrder(orderParams OrderParams, opts SliceOptions) (SlicedOrder, error)
+	PlaceSlicedOrderCtx(ctx context.Context, orderParams OrderParams, opts SliceOptions) (SlicedOrder, error)
+	PreviewOrder(orderParams OrderParams) (OrderPreview, error)
+	PreviewOrderCtx(ctx context.Context, orderParams OrderParams) (OrderPreview, error)
+	RenewAccessToken(refreshToken string) (UserSessionTokens, error)
+	RenewAccessTokenCtx(ctx context.Context, refreshToken string) (UserSessionTokens, error)
+	SearchScrip(exchange Exchange, searchText string) (ScripResults, error)
+	SearchScripCtx(ctx context.Context, exchange Exchange, searchText string) (ScripResults, error)
+	SyncGTTOCO(oco GTTOCO) (GTTRuleID, error)
+	SyncGTTOCOCtx(ctx context.Context, oco GTTOCO) (GTTRuleID, error)
+	VerifyDIS(verifyDISParams VerifyDISParams) (VerifyDISResponse, error)
+	VerifyDISCtx(ctx context.Context, verifyDISParams VerifyDISParams) (VerifyDISResponse, error)
+}
+
+var _ SmartAPI = (*Client)(nil)
@@ -0,0 +1,193 @@
+package smartapigo
+
+import (
+	"strconv"
+
+	"github.com/shammishailaj/smartapigo/instruments"
+)
+
+// OrderBuilder builds the OrderParams of an order fluently, e.g.
+//
+//	orderParams, err := client.NewOrder().Buy(NSE, "SBIN-EQ").Qty(10).Limit(455.2).Intraday().Tag("strat-1").Build()
+//
+// Orders are NORMAL DAY DELIVERY market orders unless set otherwise.
+type OrderBuilder struct {
+	client      *Client
+	orderParams OrderParams
+}
+
+// NewOrder returns a builder of an order resolving its symbol token from the
+// instrument master of the client.
+func (c *Client) NewOrder() *OrderBuilder {
+	return &OrderBuilder{
+		client: c,
+		orderParams: OrderParams{
+			Variety:     VarietyNormal,
+			OrderType:   OrderTypeMarket,
+			ProductType: ProductDelivery,
+			Duration:    DurationDay,
+			Price:       "0",
+			SquareOff:   "0",
+			StopLoss:    "0",
+		},
+	}
+}
+
+// Buy sets the order to buy the trading symbol on the exchange.
+func (b *OrderBuilder) Buy(exchange Exchange, tradingSymbol string) *OrderBuilder {
+	return b.instrument(TransactionBuy, exchange, tradingSymbol)
+}
+
+// Sell sets the order to sell the trading symbol on the exchange.
+func (b *OrderBuilder) Sell(exchange Exchange, tradingSymbol string) *OrderBuilder {
+	return b.instrument(TransactionSell, exchange, tradingSymbol)
+}
+
+func (b *OrderBuilder) instrument(transactionType TransactionType, exchange Exchange, tradingSymbol string) *OrderBuilder {
+	b.orderParams.TransactionType = transactionType
+	b.orderParams.Exchange = exchange
+	b.orderParams.TradingSymbol = tradingSymbol
+	return b
+}
+
+// Token sets the symbol token of the order, skipping its resolution.
+func (b *OrderBuilder) Token(symbolToken string) *OrderBuilder {
+	b.orderParams.SymbolToken = symbolToken
+	return b
+}
+
+// Qty sets the quantity of the order.
+func (b *OrderBuilder) Qty(quantity int) *OrderBuilder {
+	b.orderParams.Quantity = strconv.Itoa(quantity)
+	return b
+}
+
+// Market sets the order to a market order.
+func (b *OrderBuilder) Market() *OrderBuilder {
+	b.orderParams.OrderType = OrderTypeMarket
+	b.orderParams.Price = "0"
+	return b
+}
+
+// Limit sets the order to a limit order at the price.
+func (b *OrderBuilder) Limit(price float64) *OrderBuilder {
+	b.orderParams.OrderType = OrderTypeLimit
+	b.orderParams.Price = formatPrice(price)
+	return b
+}
+
+// StopLossLimit sets the order to a stop loss limit order at the price,
+// triggered at the trigger price.
+func (b *OrderBuilder) StopLossLimit(price, triggerPrice float64) *OrderBuilder {
+	b.orderParams.Variety = VarietyStopLoss
+	b.orderParams.OrderType = OrderTypeSLL
+	b.orderParams.Price = formatPrice(price)
+	b.orderParams.TriggerPrice = formatPrice(triggerPrice)
+	return b
+}
+
+// StopLossMarket sets the order to a stop loss market order triggered at the
+// trigger price.
+func (b *OrderBuilder) StopLossMarket(triggerPrice float64) *OrderBuilder {
+	b.orderParams.Variety = VarietyStopLoss
+	b.orderParams.OrderType = OrderTypeSLM
+	b.orderParams.Price = "0"
+	b.orderParams.TriggerPrice = formatPrice(triggerPrice)
+	return b
+}
+
+// Intraday sets the product of the order to INTRADAY.
+func (b *OrderBuilder) Intraday() *OrderBuilder {
+	b.orderParams.ProductType = ProductIntraday
+	return b
+}
+
+// Delivery sets the product of the order to DELIVERY.
+func (b *OrderBuilder) Delivery() *OrderBuilder {
+	b.orderParams.ProductType = ProductDelivery
+	return b
+}
+
+// CarryForward sets the product of the order to CARRYFORWARD.
+func (b *OrderBuilder) CarryForward() *OrderBuilder {
+	b.orderParams.ProductType = ProductCarryForward
+	return b
+}
+
+// Margin sets the product of the order to MARGIN.
+func (b *OrderBuilder) Margin() *OrderBuilder {
+	b.orderParams.ProductType = ProductMargin
+	return b
+}
+
+// Bracket sets the order to a ROBO (bracket) order exiting at the square off and
+// stop loss points from the price, trailing the stop loss by the trailing stop
+// loss points unless zero.
+func (b *OrderBuilder) Bracket(squareOff, stopLoss, trailingStopLoss float64) *OrderBuilder {
+	b.orderParams.Variety = VarietyRobo
+	b.orderParams.ProductType = ProductBracket
+	b.orderParams.SquareOff = formatPrice(squareOff)
+	b.orderParams.StopLoss = formatPrice(stopLoss)
+	b.orderParams.TrailingStopLoss = ""
+	if trailingStopLoss != 0 {
+		b.orderParams.TrailingStopLoss = formatPrice(trailingStopLoss)
+	}
+	return b
+}
+
+// AMO sets the order to an after market order.
+func (b *OrderBuilder) AMO() *OrderBuilder {
+	b.orderParams.Variety = VarietyAMO
+	return b
+}
+
+// IOC sets the validity of the order to immediate or cancel.
+func (b *OrderBuilder) IOC() *OrderBuilder {
+	b.orderParams.Duration = DurationIOC
+	return b
+}
+
+// Tag sets the order tag of the order.
+func (b *OrderBuilder) Tag(orderTag string) *OrderBuilder {
+	b.orderParams.OrderTag = orderTag
+	return b
+}
+
+// Build resolves the symbol token of the order when it is not set, validates the
+// order against its instrument and returns its params. An order with its token
+// set is validated against the instrument of the token if the master has it,
+// which also fills in a missing trading symbol.
+func (b *OrderBuilder) Build() (OrderParams, error) {
+	orderParams := b.orderParams
+
+	var instrument instruments.Instrument
+	if idx := b.instrumentIndex(); idx != nil && orderParams.SymbolToken != "" {
+		instrument, _ = idx.ByToken(string(orderParams.Exchange), orderParams.SymbolToken)
+		if orderParams.TradingSymbol == "" {
+			orderParams.TradingSymbol = instrument.Symbol
+		}
+	} else if idx != nil {
+		var err error
+		if instrument, err = b.client.ResolveSymbol(string(orderParams.Exchange), orderParams.TradingSymbol); err != nil {
+			return OrderParams{}, err
+		}
+		orderParams.SymbolToken = instrument.Token
+	}
+
+	if err := orderParams.Validate(instrument); err != nil {
+		return OrderParams{}, err
+	}
+	return orderParams, nil
+}
+
+// instrumentIndex returns the instrument master of the client, if any.
+func (b *OrderBuilder) instrumentIndex() *instruments.Index {
+	if b.client == nil {
+		return nil
+	}
+	return b.client.instruments.Load()
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
@@ -0,0 +1,89 @@
+package smartapigo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrderBuilder(t *testing.T) {
+	client := New("test", "test", "test")
+	client.SetInstruments(newInstruments(t))
+
+	orderParams, err := client.NewOrder().Buy(NSE, "SBIN-EQ").Qty(10).Limit(455.2).Intraday().Tag("strat-1").Build()
+	if err != nil {
+		t.Fatalf("Error while building order. %v", err)
+	}
+	expected := OrderParams{
+		Variety:         VarietyNormal,
+		TradingSymbol:   "SBIN-EQ",
+		SymbolToken:     "3045",
+		TransactionType: TransactionBuy,
+		Exchange:        NSE,
+		OrderType:       OrderTypeLimit,
+		ProductType:     ProductIntraday,
+		Duration:        DurationDay,
+		Price:           "455.2",
+		SquareOff:       "0",
+		StopLoss:        "0",
+		Quantity:        "10",
+		OrderTag:        "strat-1",
+	}
+	if orderParams != expected {
+		t.Errorf("Unexpected order params %+v", orderParams)
+	}
+
+	orderParams, err = client.NewOrder().Sell(NFO, "NIFTY25JAN24FUT").Qty(50).StopLossMarket(21490).CarryForward().Build()
+	if err != nil || orderParams.Variety != VarietyStopLoss || orderParams.OrderType != OrderTypeSLM || orderParams.TriggerPrice != "21490" || orderParams.SymbolToken != "35001" {
+		t.Errorf("Unexpected order params %+v. %v", orderParams, err)
+	}
+
+	orderParams, err = client.NewOrder().Buy(NSE, "SBIN-EQ").Qty(1).Limit(455.2).Bracket(5, 2.5, 1).Build()
+	if err != nil || orderParams.Variety != VarietyRobo || orderParams.ProductType != ProductBracket ||
+		orderParams.SquareOff != "5" || orderParams.StopLoss != "2.5" || orderParams.TrailingStopLoss != "1" {
+		t.Errorf("Unexpected order params %+v. %v", orderParams, err)
+	}
+}
+
+func TestOrderBuilderErrors(t *testing.T) {
+	client := New("test", "test", "test")
+	client.SetInstruments(newInstruments(t))
+
+	if _, err := client.NewOrder().Buy(NSE, "UNKNOWN-EQ").Qty(1).Build(); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected an unknown symbol error, got %v", err)
+	}
+
+	_, err := client.NewOrder().Buy(NFO, "NIFTY25JAN24FUT").Qty(75).Limit(21500.02).Build()
+	if got := problems(err); len(got) != 2 {
+		t.Errorf("Expected lot and tick size problems, got %q", got)
+	}
+
+	// Without an instrument master the token must be set.
+	client = New("test", "test", "test")
+	if _, err := client.NewOrder().Buy(NSE, "SBIN-EQ").Qty(1).Build(); err == nil || !strings.Contains(err.Error(), "symboltoken is required") {
+		t.Errorf("Expected the symbol token to be required, got %v", err)
+	}
+	if _, err := client.NewOrder().Buy(NSE, "SBIN-EQ").Token("3045").Qty(1).Build(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestOrderBuilderToken(t *testing.T) {
+	client := New("test", "test", "test")
+	client.SetInstruments(newInstruments(t))
+
+	orderParams, err := client.NewOrder().Buy(NSE, "").Token("3045").Qty(1).Limit(455.2).Build()
+	if err != nil || orderParams.TradingSymbol != "SBIN-EQ" || orderParams.SymbolToken != "3045" {
+		t.Errorf("Unexpected order params %+v. %v", orderParams, err)
+	}
+
+	// An instrument missing from the master is not resolved.
+	orderParams, err = client.NewOrder().Buy(NSE, "NEWLISTING-EQ").Token("99999").Qty(1).Build()
+	if err != nil || orderParams.TradingSymbol != "NEWLISTING-EQ" || orderParams.SymbolToken != "99999" {
+		t.Errorf("Unexpected order params %+v. %v", orderParams, err)
+	}
+
+	_, err = client.NewOrder().Buy(NFO, "").Token("35001").Qty(75).Build()
+	if got := problems(err); len(got) != 1 {
+		t.Errorf("Expected a lot size problem for the instrument of the token, got %q", got)
+	}
+}
@@ -1,21 +1,44 @@
 package smartapigo
 
 import (
+	"context"
 	"crypto/tls"
-	_ "fmt"
+	"fmt"
+	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/shammishailaj/smartapigo/instruments"
 )
 
 // Client represents interface for Kite Connect client.
 type Client struct {
-	clientCode  string
-	password    string
-	accessToken string
-	debug       bool
-	baseURI     string
-	apiKey      string
-	httpClient  HTTPClient
+	clientCode   string
+	password     string
+	totpSecret   string
+	accessToken  string
+	refreshToken string
+	feedToken    string
+	sessionMu     sync.RWMutex
+	onRefreshed   func(UserSessionTokens)
+	onInvalidated func()
+	stopRefresher context.CancelFunc
+	paper         *paperBook
+	instruments  atomic.Pointer[instruments.Index]
+	debug        bool
+	baseURI      string
+	env          Environment
+	apiKey       string
+	httpClient   HTTPClient
+	rateLimiters *rateLimiters
+	retryPolicy  RetryPolicy
+	metrics      Metrics
+	tracer       Tracer
+	clientInfoMu sync.Mutex
+	info         *ClientInfo
+	infoRetry    time.Time
 }
 
 const (
@@ -31,24 +54,51 @@ func New(clientCode string,password string,apiKey string) *Client {
 		password: password,
 		apiKey: apiKey,
 		baseURI: baseURI,
+		env: Production,
+		metrics: NoopMetrics{},
+		tracer: NoopTracer{},
+		rateLimiters: newRateLimiters(),
 	}
 
 	// Create a default http handler with default timeout.
-	client.SetHTTPClient(&http.Client{
-		Timeout: requestTimeout,
-		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify : true}},
-	})
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify : true}
+	client.SetHTTPClient(&http.Client{Transport: transport})
+	_ = client.SetTransportOptions(DefaultTransportOptions)
+
+	return client
+}
 
+// NewWithHTTPClient creates a new Smart API client sending its requests, including
+// the public ip lookup, with a caller supplied http client.
+func NewWithHTTPClient(clientCode string, password string, apiKey string, h *http.Client) *Client {
+	client := New(clientCode, password, apiKey)
+	client.SetHTTPClient(h)
 	return client
 }
 
 // SetHTTPClient overrides default http handler with a custom one.
 // This can be used to set custom timeouts and transport.
 func (c *Client) SetHTTPClient(h *http.Client) {
-	c.httpClient = NewHTTPClient(h, nil, c.debug)
+	var (
+		hLog        *log.Logger
+		middlewares []Middleware
+	)
+	if c.httpClient != nil {
+		hLog = c.httpClient.GetClient().hLog
+		middlewares = c.httpClient.GetClient().middlewares
+	}
+	c.httpClient = NewHTTPClient(h, hLog, c.debug)
+	c.httpClient.GetClient().middlewares = middlewares
 }
 
-// SetDebug sets debug mode to enable HTTP logs.
+// SetLogger overrides the default logger of HTTP logs, which writes to stdout.
+func (c *Client) SetLogger(hLog *log.Logger) {
+	c.httpClient.GetClient().hLog = hLog
+}
+
+// SetDebug sets debug mode to enable HTTP logs. Every request is logged with its
+// method, url, latency, status and headers and bodies with secrets redacted.
 func (c *Client) SetDebug(debug bool) {
 	c.debug = debug
 	c.httpClient.GetClient().debug = debug
@@ -59,6 +109,47 @@ func (c *Client) SetBaseURI(baseURI string) {
 	c.baseURI = baseURI
 }
 
+// SetTransport overrides the transport of the http client with a custom one.
+// This can be used to add proxies, connection pooling limits and instrumentation.
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.GetClient().client.Transport = transport
+}
+
+// SetClientInfo sets the local and public ip and the mac address sent with every
+// request instead of looking them up, e.g. in networks where the public ip lookup
+// service is unreachable.
+func (c *Client) SetClientInfo(info ClientInfo) {
+	c.clientInfoMu.Lock()
+	defer c.clientInfoMu.Unlock()
+	c.info = &info
+	c.infoRetry = time.Time{}
+}
+
+// SetTransportOptions tunes the connection pooling and timeouts of the http
+// client. Zero options are left unchanged. The transport must be an
+// *http.Transport for options other than the request timeout.
+func (c *Client) SetTransportOptions(opts TransportOptions) error {
+	hClient := c.httpClient.GetClient().client
+	if opts.RequestTimeout != 0 {
+		hClient.Timeout = opts.RequestTimeout
+	}
+	if opts.MaxIdleConnsPerHost == 0 && opts.IdleConnTimeout == 0 && opts.TLSHandshakeTimeout == 0 {
+		return nil
+	}
+
+	transport, ok := hClient.Transport.(*http.Transport)
+	if hClient.Transport == nil {
+		transport, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok {
+		return fmt.Errorf("transport options need an *http.Transport, got %T", hClient.Transport)
+	}
+	transport = transport.Clone()
+	opts.apply(transport)
+	hClient.Transport = transport
+	return nil
+}
+
 // SetTimeout sets request timeout for default http client.
 func (c *Client) SetTimeout(timeout time.Duration) {
 	hClient := c.httpClient.GetClient().client
@@ -67,10 +158,71 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 
 // SetAccessToken sets the access token to the Kite Connect instance.
 func (c *Client) SetAccessToken(accessToken string) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
 	c.accessToken = accessToken
 }
 
-func (c *Client) doEnvelope(method, uri string, params map[string]interface{}, headers http.Header, v interface{}, authorization ...bool) error {
+// AccessToken returns the access token of the current session.
+func (c *Client) AccessToken() string {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+	return c.accessToken
+}
+
+// ClientCode returns the client code the client was created with.
+func (c *Client) ClientCode() string {
+	return c.clientCode
+}
+
+// FeedToken returns the feed token of the current session.
+func (c *Client) FeedToken() string {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+	return c.feedToken
+}
+
+// RefreshToken returns the refresh token of the current session.
+func (c *Client) RefreshToken() string {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+	return c.refreshToken
+}
+
+// GetSession returns a snapshot of the tokens of the current session, taken
+// together so they belong to the same session even while it is renewed.
+func (c *Client) GetSession() UserSessionTokens {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+	return UserSessionTokens{AccessToken: c.accessToken, RefreshToken: c.refreshToken, FeedToken: c.feedToken}
+}
+
+// setSessionTokens stores the tokens of a session retrieved successfully
+// and returns the tokens of the session.
+func (c *Client) setSessionTokens(tokens UserSessionTokens) UserSessionTokens {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.accessToken = tokens.AccessToken
+	if tokens.RefreshToken != "" {
+		c.refreshToken = tokens.RefreshToken
+	}
+	if tokens.FeedToken != "" {
+		c.feedToken = tokens.FeedToken
+	}
+	return UserSessionTokens{AccessToken: c.accessToken, RefreshToken: c.refreshToken, FeedToken: c.feedToken}
+}
+
+func (c *Client) doEnvelope(ctx context.Context, method, uri string, params map[string]interface{}, headers http.Header, v interface{}, authorization ...bool) error {
+	id := requestID(ctx)
+	var status int
+	ctx, span := c.tracer.StartRequest(ctx, method, uri)
+	err := c.doEnvelopeRequest(withResponseStatus(ctx, &status), id, method, uri, params, headers, v, authorization...)
+	span.End(RequestOutcome{RequestID: id, HTTPStatus: status, Err: err})
+	return err
+}
+
+// doEnvelopeRequest sends the request within the rate limits, retrying transient failures.
+func (c *Client) doEnvelopeRequest(ctx context.Context, id, method, uri string, params map[string]interface{}, headers http.Header, v interface{}, authorization ...bool) error {
 	if params == nil {
 		params = map[string]interface{}{}
 	}
@@ -80,7 +232,7 @@ func (c *Client) doEnvelope(method, uri string, params map[string]interface{}, h
 		headers = map[string][]string{}
 	}
 
-	localIp,publicIp,mac,err := getIpAndMac()
+	info, err := c.clientInfo(ctx)
 
 	if err != nil {
 		return err
@@ -88,18 +240,59 @@ func (c *Client) doEnvelope(method, uri string, params map[string]interface{}, h
 
 	// Add Kite Connect version to header
 	headers.Add("Content-Type", "application/json")
-	headers.Add("X-ClientLocalIP", localIp)
-	headers.Add("X-ClientPublicIP", publicIp)
-	headers.Add("X-MACAddress", mac)
+	headers.Add("X-ClientLocalIP", info.LocalIP)
+	headers.Add("X-ClientPublicIP", info.PublicIP)
+	headers.Add("X-MACAddress", info.MACAddress)
 	headers.Add("Accept", "application/json")
 	headers.Add("X-UserType", "USER")
 	headers.Add("X-SourceID", "WEB")
 	headers.Add("X-PrivateKey",c.apiKey)
 	if authorization != nil && authorization[0]{
-		headers.Add("Authorization","Bearer "+c.accessToken)
+		headers.Add("Authorization","Bearer "+c.AccessToken())
+	}
+	if id != "" {
+		headers.Set(RequestIDHeader, id)
 	}
 
-	return c.httpClient.DoEnvelope(method, c.baseURI+uri, params, headers, v)
+	for attempt := 1; ; attempt++ {
+		// Every attempt, retries included, is sent within the rate limits.
+		waitStart := time.Now()
+		if err := c.rateLimiters.wait(ctx, uri); err != nil {
+			return err
+		}
+		if group, ok := rateLimitGroups[uri]; ok {
+			c.metrics.RateLimitWait(group, time.Since(waitStart))
+		}
+
+		var rateLimit RateLimitInfo
+		start := time.Now()
+		err = c.httpClient.DoEnvelopeContext(withRateLimitInfo(ctx, &rateLimit), method, c.baseURI+uri, params, headers, v)
+		c.metrics.RequestDone(uri, errorCode(err), time.Since(start))
+		// Hold back the requests to the endpoint group as long as the API asks to.
+		if wait := rateLimit.Wait(); wait > 0 {
+			c.rateLimiters.pause(uri, time.Now().Add(wait))
+		}
+		if err == nil || attempt >= c.retryPolicy.MaxAttempts || !transient(err) || !retryable(uri, params) {
+			return withRequest(err, uri, id)
+		}
+		delay := c.retryPolicy.backoff(attempt)
+		if wait := retryAfter(err); wait > delay {
+			delay = wait
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// withRequest sets the endpoint and the request id of an API error.
+func withRequest(err error, uri, requestID string) error {
+	if apiErr, ok := err.(Error); ok {
+		apiErr.Endpoint = uri
+		apiErr.RequestID = requestID
+		return apiErr
+	}
+	return err
 }
 
 
@@ -1,7 +1,10 @@
 package smartapigo
 
 import (
+	"bytes"
+	"errors"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
 	"path"
@@ -113,6 +116,7 @@ var MockResponders = [][]string{
 	[]string{http.MethodGet, URIUserProfile, "profile.json"},
 	[]string{http.MethodGet, URIGetPositions, "positions.json"},
 	[]string{http.MethodGet, URIGetHoldings, "holdings.json"},
+	[]string{http.MethodGet, URIGetAllHoldings, "all_holdings.json"},
 	[]string{http.MethodGet, URIRMS, "rms.json"},
 	[]string{http.MethodGet, URIGetTradeBook, "trades.json"},
 	[]string{http.MethodGet, URIGetOrderBook, "orders.json"},
@@ -127,6 +131,18 @@ var MockResponders = [][]string{
 	[]string{http.MethodPost, URIUserProfile, "profile.json"},
 	[]string{http.MethodPost, URILogout, "logout.json"},
 	[]string{http.MethodPost, URIConvertPosition, "position_conversion.json"},
+	[]string{http.MethodPost, URICreateGTTRule, "gtt_rule_response.json"},
+	[]string{http.MethodPost, URIModifyGTTRule, "gtt_rule_response.json"},
+	[]string{http.MethodPost, URICancelGTTRule, "gtt_rule_cancel_response.json"},
+	[]string{http.MethodPost, URIGTTRuleDetails, "gtt_rule_details.json"},
+	[]string{http.MethodPost, URIGTTRuleList, "gtt_rule_list.json"},
+	[]string{http.MethodPost, URIMargin, "margin.json"},
+	[]string{http.MethodPost, URIOIBuildup, "oi_buildup.json"},
+	[]string{http.MethodPost, URISearchScrip, "search_scrip.json"},
+	[]string{http.MethodPost, URIMarketData, "market_data.json"},
+	[]string{http.MethodPost, URIVerifyDIS, "verify_dis.json"},
+	[]string{http.MethodPost, URIGenerateTPIN, "generate_tpin.json"},
+	[]string{http.MethodPost, URIGetDISStatus, "dis_status.json"},
 
 }
 
@@ -134,6 +150,14 @@ var MockResponders = [][]string{
 const suiteTestMethodPrefix = "Test"
 
 // TestSuite is an interface where you define suite and test case preparation and tear down logic.
+// newMockClient returns a client sending its requests to a mock transport
+// which answers the public ip lookup.
+func newMockClient() (*Client, *httpmock.MockTransport) {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewStringResponder(200, "127.0.0.1"))
+	return NewWithHTTPClient("test", "test", "test", &http.Client{Transport: transport}), transport
+}
+
 type TestSuite struct {
 	TestConnect *Client
 }
@@ -146,6 +170,7 @@ func (ts *TestSuite) SetupAPITestSuit() {
 	apiKey := "test_key"
 	ts.TestConnect = New(clientcode,password,apiKey)
 	httpmock.ActivateNonDefault(ts.TestConnect.httpClient.GetClient().client)
+	httpmock.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewStringResponder(200, "127.0.0.1"))
 
 	for _, v := range MockResponders {
 		httpMethod := v[0]
@@ -215,3 +240,139 @@ func TestAPIMethods(t *testing.T) {
 	RunAPITests(t, s)
 }
 
+
+func TestNewWithHTTPClient(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIUserProfile, httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"clientcode":"test"}}`))
+
+	profile, err := client.GetUserProfile()
+	if err != nil || profile.ClientCode != "test" {
+		t.Errorf("Unexpected profile %+v. %v", profile, err)
+	}
+	if transport.GetTotalCallCount() != 2 {
+		t.Errorf("Expected the profile and public ip requests to use the transport, got %d calls", transport.GetTotalCallCount())
+	}
+
+	other := httpmock.NewMockTransport()
+	client.SetTransport(other)
+	if _, err := client.GetUserProfile(); err == nil || transport.GetTotalCallCount() != 2 {
+		t.Errorf("Expected the request to use the new transport. %v", err)
+	}
+}
+
+func TestSetTransportOptions(t *testing.T) {
+	client := New("test", "test", "test")
+	transport := client.httpClient.GetClient().client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != DefaultTransportOptions.MaxIdleConnsPerHost || transport.IdleConnTimeout != DefaultTransportOptions.IdleConnTimeout {
+		t.Errorf("Default transport options are not set properly.")
+	}
+
+	err := client.SetTransportOptions(TransportOptions{MaxIdleConnsPerHost: 200, TLSHandshakeTimeout: time.Second, RequestTimeout: 3 * time.Second})
+	transport = client.httpClient.GetClient().client.Transport.(*http.Transport)
+	if err != nil || transport.MaxIdleConnsPerHost != 200 || transport.MaxIdleConns < 200 || transport.TLSHandshakeTimeout != time.Second ||
+		transport.IdleConnTimeout != DefaultTransportOptions.IdleConnTimeout || client.httpClient.GetClient().client.Timeout != 3*time.Second {
+		t.Errorf("Transport options are not set properly. %v", err)
+	}
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("Transport settings are not kept.")
+	}
+
+	client.SetTransport(httpmock.NewMockTransport())
+	if err := client.SetTransportOptions(TransportOptions{MaxIdleConnsPerHost: 20}); err == nil {
+		t.Errorf("Expected an error for a custom transport.")
+	}
+	if err := client.SetTransportOptions(TransportOptions{RequestTimeout: time.Second}); err != nil || client.httpClient.GetClient().client.Timeout != time.Second {
+		t.Errorf("Request timeout is not set properly. %v", err)
+	}
+}
+
+func TestDebugLogging(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URILogin, httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"jwtToken":"secret-jwt","refreshToken":"secret-refresh","feedToken":"secret-feed"}}`))
+
+	var buf bytes.Buffer
+	client.SetLogger(log.New(&buf, "", 0))
+	client.SetDebug(true)
+	if _, err := client.GenerateSession("123456"); err != nil {
+		t.Fatalf("Error while generating session. %v", err)
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, "POST "+client.baseURI+URILogin+" -- 200 in ") || !strings.Contains(logs, "request id: ") {
+		t.Errorf("Expected the request to be logged, got %s", logs)
+	}
+	for _, secret := range []string{"123456", "secret-jwt", "secret-refresh", "secret-feed", `"password":"test"`} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("Secret %s not redacted in %s", secret, logs)
+		}
+	}
+	if !strings.Contains(logs, "X-Privatekey:["+redacted+"]") {
+		t.Errorf("Expected the private key header to be redacted, got %s", logs)
+	}
+	if !strings.Contains(logs, `"clientcode":"test"`) {
+		t.Errorf("Expected the request body to be logged, got %s", logs)
+	}
+}
+
+func TestClientInfo(t *testing.T) {
+	client, transport := newMockClient()
+	var headers http.Header
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIUserProfile, func(req *http.Request) (*http.Response, error) {
+		headers = req.Header
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"clientcode":"test"}}`), nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetUserProfile(); err != nil {
+			t.Fatalf("Error while fetching profile. %v", err)
+		}
+	}
+	if ipCalls := transport.GetCallCountInfo()["GET https://myexternalip.com/raw"]; ipCalls != 1 {
+		t.Errorf("Expected the public ip to be looked up once, got %d lookups", ipCalls)
+	}
+	if headers.Get("X-ClientPublicIP") != "127.0.0.1" {
+		t.Errorf("Unexpected public ip header %q", headers.Get("X-ClientPublicIP"))
+	}
+
+	client.SetClientInfo(ClientInfo{LocalIP: "10.0.0.2", PublicIP: "203.0.113.7", MACAddress: "02:00:00:00:00:01"})
+	if _, err := client.GetUserProfile(); err != nil {
+		t.Fatalf("Error while fetching profile. %v", err)
+	}
+	if headers.Get("X-ClientLocalIP") != "10.0.0.2" || headers.Get("X-ClientPublicIP") != "203.0.113.7" || headers.Get("X-MACAddress") != "02:00:00:00:00:01" {
+		t.Errorf("Unexpected client info headers %v", headers)
+	}
+}
+
+func TestClientInfoPublicIPFallback(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewErrorResponder(errors.New("unreachable")))
+	var headers http.Header
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIUserProfile, func(req *http.Request) (*http.Response, error) {
+		headers = req.Header
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"clientcode":"test"}}`), nil
+	})
+
+	if _, err := client.GetUserProfile(); err != nil {
+		t.Fatalf("Expected the request to succeed without the public ip. %v", err)
+	}
+	if headers.Get("X-ClientPublicIP") == "" || headers.Get("X-ClientPublicIP") != headers.Get("X-ClientLocalIP") {
+		t.Errorf("Expected the public ip to fall back to the local ip, got %v", headers)
+	}
+
+	// The fallback is not kept once the lookup is retried.
+	transport.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewStringResponder(200, "203.0.113.7"))
+	client.clientInfoMu.Lock()
+	client.infoRetry = time.Now().Add(-time.Second)
+	client.clientInfoMu.Unlock()
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetUserProfile(); err != nil {
+			t.Fatalf("Error while fetching profile. %v", err)
+		}
+	}
+	if headers.Get("X-ClientPublicIP") != "203.0.113.7" {
+		t.Errorf("Expected the public ip to be looked up again, got %q", headers.Get("X-ClientPublicIP"))
+	}
+	if ipCalls := transport.GetCallCountInfo()["GET https://myexternalip.com/raw"]; ipCalls != 1 {
+		t.Errorf("Expected the resolved public ip to be cached, got %d lookups", ipCalls)
+	}
+}
@@ -0,0 +1,163 @@
+package smartapigo
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// paperBook is the book of the orders simulated in dry run mode.
+type paperBook struct {
+	mu     sync.Mutex
+	source LTPSource
+	orders Orders
+	nextID int
+}
+
+// SetDryRun enables or disables the dry run mode. In dry run mode PlaceOrder,
+// ModifyOrder and CancelOrder are simulated without requests and acknowledged
+// with order ids prefixed DRYRUN-, while the other methods still make requests.
+// The simulated orders are returned by DryRunOrders. Orders are filled when
+// placed or modified at the last traded price of the source, when set: market
+// orders at that price and limit orders when it is at or better than their
+// price. Other orders stay open. Disabling the mode discards the orders.
+func (c *Client) SetDryRun(enabled bool, source LTPSource) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.paper = nil
+	if enabled {
+		c.paper = &paperBook{source: source}
+	}
+}
+
+// DryRunOrders returns the orders simulated in dry run mode.
+func (c *Client) DryRunOrders() Orders {
+	book := c.paperBook()
+	if book == nil {
+		return nil
+	}
+	book.mu.Lock()
+	defer book.mu.Unlock()
+	return append(Orders(nil), book.orders...)
+}
+
+// paperBook returns the book of the dry run mode, nil when disabled.
+func (c *Client) paperBook() *paperBook {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+	return c.paper
+}
+
+func (book *paperBook) place(orderParams OrderParams) (OrderResponse, error) {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	book.nextID++
+	order := Order{
+		Variety:          string(orderParams.Variety),
+		OrderType:        string(orderParams.OrderType),
+		ProductType:      string(orderParams.ProductType),
+		Duration:         string(orderParams.Duration),
+		Price:            orderParams.Price,
+		TriggerPrice:     orderParams.TriggerPrice,
+		Quantity:         orderParams.Quantity,
+		SquareOff:        orderParams.SquareOff,
+		StopLoss:         orderParams.StopLoss,
+		TrailingStopLoss: orderParams.TrailingStopLoss,
+		TradingSymbol:    orderParams.TradingSymbol,
+		TransactionType:  string(orderParams.TransactionType),
+		Exchange:         string(orderParams.Exchange),
+		SymbolToken:      orderParams.SymbolToken,
+		OrderID:          "DRYRUN-" + strconv.Itoa(book.nextID),
+		OrderTag:         orderParams.OrderTag,
+	}
+	book.update(&order, "open")
+	book.orders = append(book.orders, order)
+	return OrderResponse{Script: order.TradingSymbol, OrderID: order.OrderID}, nil
+}
+
+func (book *paperBook) modify(modifyOrderParams ModifyOrderParams) (OrderResponse, error) {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	order, err := book.pending(modifyOrderParams.OrderID)
+	if err != nil {
+		return OrderResponse{}, err
+	}
+	if modifyOrderParams.OrderType != "" {
+		order.OrderType = string(modifyOrderParams.OrderType)
+	}
+	if modifyOrderParams.Price != "" {
+		order.Price = modifyOrderParams.Price
+	}
+	if modifyOrderParams.Quantity != "" {
+		order.Quantity = modifyOrderParams.Quantity
+	}
+	if modifyOrderParams.TriggerPrice != "" {
+		order.TriggerPrice = modifyOrderParams.TriggerPrice
+	}
+	book.update(order, "open")
+	return OrderResponse{Script: order.TradingSymbol, OrderID: order.OrderID}, nil
+}
+
+func (book *paperBook) cancel(orderID string) (OrderResponse, error) {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	order, err := book.pending(orderID)
+	if err != nil {
+		return OrderResponse{}, err
+	}
+	book.update(order, "cancelled")
+	return OrderResponse{Script: order.TradingSymbol, OrderID: order.OrderID}, nil
+}
+
+// pending returns the pending order with the id.
+func (book *paperBook) pending(orderID string) (*Order, error) {
+	for i := range book.orders {
+		if book.orders[i].OrderID == orderID {
+			if !book.orders[i].Pending() {
+				return nil, NewError(InputError, "dry run order "+orderID+" is "+book.orders[i].OrderStatus, nil)
+			}
+			return &book.orders[i], nil
+		}
+	}
+	return nil, NewError(InputError, "dry run order "+orderID+" not found", nil)
+}
+
+// update sets the status of the order, filling it at the last traded price of
+// the source when it is open and marketable.
+func (book *paperBook) update(order *Order, status string) {
+	order.FilledShares, order.UnfilledShares, order.AveragePrice = "0", order.Quantity, "0"
+	if status == "open" {
+		if price, ok := book.fillPrice(*order); ok {
+			status = "complete"
+			order.FilledShares, order.UnfilledShares = order.Quantity, "0"
+			order.AveragePrice = strconv.FormatFloat(price, 'f', -1, 64)
+		}
+	}
+	order.Status, order.OrderStatus = status, status
+}
+
+// fillPrice returns the price the order is filled at, if it is.
+func (book *paperBook) fillPrice(order Order) (float64, bool) {
+	if book.source == nil {
+		return 0, false
+	}
+	ltp, ok := book.source(order.Exchange, order.SymbolToken)
+	if !ok || ltp <= 0 {
+		return 0, false
+	}
+
+	switch OrderType(order.OrderType) {
+	case OrderTypeMarket:
+		return ltp, true
+	case OrderTypeLimit:
+		price := number(order.Price)
+		buy := strings.EqualFold(order.TransactionType, string(TransactionBuy))
+		if (buy && ltp <= price) || (!buy && ltp >= price) {
+			return ltp, true
+		}
+	}
+	return 0, false
+}
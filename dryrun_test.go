@@ -0,0 +1,83 @@
+package smartapigo
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestDryRun(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetOrderBook, httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":[]}`))
+
+	ltp := 455.0
+	client.SetDryRun(true, func(exchange, symbolToken string) (float64, bool) {
+		return ltp, exchange == "NSE" && symbolToken == "3045"
+	})
+
+	limit := OrderParams{
+		Variety:         VarietyNormal,
+		TradingSymbol:   "SBIN-EQ",
+		SymbolToken:     "3045",
+		TransactionType: TransactionBuy,
+		Exchange:        NSE,
+		OrderType:       OrderTypeLimit,
+		ProductType:     ProductIntraday,
+		Duration:        DurationDay,
+		Price:           "450",
+		Quantity:        "10",
+	}
+	placed, err := client.PlaceOrder(limit)
+	if err != nil || placed.OrderID != "DRYRUN-1" || placed.Script != "SBIN-EQ" {
+		t.Fatalf("Unexpected order response %+v. %v", placed, err)
+	}
+	if orders := client.DryRunOrders(); len(orders) != 1 || orders[0].OrderStatus != "open" || orders[0].UnfilledShares != "10" {
+		t.Fatalf("Expected the limit order to be open, got %+v", orders)
+	}
+
+	// Modifying the price to cross the last traded price fills the order.
+	if _, err = client.ModifyOrder(ModifyOrderParams{OrderID: placed.OrderID, OrderType: OrderTypeLimit, Price: "460", Quantity: "10"}); err != nil {
+		t.Fatalf("Error while modifying order. %v", err)
+	}
+	order := client.DryRunOrders()[0]
+	if order.OrderStatus != "complete" || order.FilledShares != "10" || order.AveragePrice != "455" {
+		t.Errorf("Expected the order to be filled at 455, got %+v", order)
+	}
+	if _, err = client.CancelOrder(string(VarietyNormal), placed.OrderID); err == nil {
+		t.Errorf("Expected an error cancelling a complete order")
+	}
+
+	market := limit
+	market.OrderType, market.Price, market.SymbolToken = OrderTypeMarket, "0", "1594"
+	placed, err = client.PlaceOrder(market)
+	if err != nil {
+		t.Fatalf("Error while placing order. %v", err)
+	}
+	if order = client.DryRunOrders()[1]; order.OrderStatus != "open" {
+		t.Errorf("Expected the order without a price to stay open, got %+v", order)
+	}
+	if _, err = client.CancelOrder(string(VarietyNormal), placed.OrderID); err != nil {
+		t.Fatalf("Error while cancelling order. %v", err)
+	}
+	if order = client.DryRunOrders()[1]; order.OrderStatus != "cancelled" {
+		t.Errorf("Expected the order to be cancelled, got %+v", order)
+	}
+	if _, err = client.CancelOrder(string(VarietyNormal), "unknown"); err == nil {
+		t.Errorf("Expected an error cancelling an unknown order")
+	}
+
+	// Read only endpoints still make requests.
+	if _, err = client.GetOrderBook(); err != nil {
+		t.Errorf("Error while fetching order book. %v", err)
+	}
+	calls := transport.GetCallCountInfo()
+	if calls["GET "+client.baseURI+URIGetOrderBook] != 1 || transport.GetTotalCallCount() != calls["GET "+client.baseURI+URIGetOrderBook]+calls["GET https://myexternalip.com/raw"] {
+		t.Errorf("Unexpected requests %v", calls)
+	}
+
+	client.SetDryRun(false, nil)
+	if orders := client.DryRunOrders(); orders != nil {
+		t.Errorf("Expected no dry run orders, got %+v", orders)
+	}
+}
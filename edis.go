@@ -0,0 +1,82 @@
+package smartapigo
+
+import (
+	"context"
+	"net/http"
+)
+
+// VerifyDISParams represents parameters for verifying a DIS (delivery instruction slip).
+type VerifyDISParams struct {
+	ISIN     string `json:"isin"`
+	Quantity string `json:"quantity"`
+}
+
+// VerifyDISResponse represents Verify DIS API response. Its fields are posted
+// to the CDSL eDIS page at ReturnURL to authorise the sale of the holdings.
+type VerifyDISResponse struct {
+	ReqID        string `json:"ReqId"`
+	ReturnURL    string `json:"ReturnURL"`
+	DPID         string `json:"DPId"`
+	BOID         string `json:"BOID"`
+	TransDetails string `json:"TransDtls"`
+	Version      string `json:"version"`
+}
+
+// GenerateTPINParams represents parameters for generating a CDSL TPIN.
+type GenerateTPINParams struct {
+	DPID  string `json:"dpId"`
+	ReqID string `json:"ReqId"`
+	BOID  string `json:"boid"`
+	PAN   string `json:"pan"`
+}
+
+// DISStatus represents Transaction Status API response.
+type DISStatus struct {
+	ReqID   string `json:"ReqId"`
+	ReqType string `json:"ReqType"`
+	Status  string `json:"Status"`
+}
+
+// VerifyDIS verifies the holdings to be sold from demat and returns the details
+// required to authorise them with CDSL.
+func (c *Client) VerifyDIS(verifyDISParams VerifyDISParams) (VerifyDISResponse, error) {
+	return c.VerifyDISCtx(context.Background(), verifyDISParams)
+}
+
+// VerifyDISCtx is VerifyDIS with a context for the request.
+func (c *Client) VerifyDISCtx(ctx context.Context, verifyDISParams VerifyDISParams) (VerifyDISResponse, error) {
+	var dis VerifyDISResponse
+	params := structToMap(verifyDISParams, "json")
+	err := c.doEnvelope(ctx, http.MethodPost, URIVerifyDIS, params, nil, &dis, true)
+	return dis, err
+}
+
+// GenerateTPIN requests CDSL to send a TPIN to the registered mobile number and email.
+func (c *Client) GenerateTPIN(generateTPINParams GenerateTPINParams) (bool, error) {
+	return c.GenerateTPINCtx(context.Background(), generateTPINParams)
+}
+
+// GenerateTPINCtx is GenerateTPIN with a context for the request.
+func (c *Client) GenerateTPINCtx(ctx context.Context, generateTPINParams GenerateTPINParams) (bool, error) {
+	var status bool
+	params := structToMap(generateTPINParams, "json")
+	err := c.doEnvelope(ctx, http.MethodPost, URIGenerateTPIN, params, nil, nil, true)
+	if err == nil {
+		status = true
+	}
+	return status, err
+}
+
+// GetDISStatus gets the status of the authorisation requested with VerifyDIS.
+func (c *Client) GetDISStatus(reqID string) (DISStatus, error) {
+	return c.GetDISStatusCtx(context.Background(), reqID)
+}
+
+// GetDISStatusCtx is GetDISStatus with a context for the request.
+func (c *Client) GetDISStatusCtx(ctx context.Context, reqID string) (DISStatus, error) {
+	var status DISStatus
+	params := map[string]interface{}{}
+	params["ReqId"] = reqID
+	err := c.doEnvelope(ctx, http.MethodPost, URIGetDISStatus, params, nil, &status, true)
+	return status, err
+}
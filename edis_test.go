@@ -0,0 +1,38 @@
+package smartapigo
+
+import (
+	"testing"
+)
+
+func (ts *TestSuite) TestVerifyDIS(t *testing.T) {
+	t.Parallel()
+	dis, err := ts.TestConnect.VerifyDIS(VerifyDISParams{ISIN: "INE242A01010", Quantity: "1"})
+	if err != nil {
+		t.Errorf("Error while verifying DIS. %v", err)
+	}
+
+	if dis.ReqID == "" || dis.DPID != "33200" {
+		t.Errorf("Unexpected DIS response %+v", dis)
+	}
+}
+
+func (ts *TestSuite) TestGenerateTPIN(t *testing.T) {
+	t.Parallel()
+	params := GenerateTPINParams{DPID: "33200", ReqID: "2351614738654050", BOID: "1203320018563571", PAN: "AAAAA0000A"}
+	status, err := ts.TestConnect.GenerateTPIN(params)
+	if err != nil || !status {
+		t.Errorf("Error while generating TPIN. %v", err)
+	}
+}
+
+func (ts *TestSuite) TestGetDISStatus(t *testing.T) {
+	t.Parallel()
+	status, err := ts.TestConnect.GetDISStatus("2351614738654050")
+	if err != nil {
+		t.Errorf("Error while fetching DIS status. %v", err)
+	}
+
+	if status.Status != "Completed" {
+		t.Errorf("Unexpected DIS status %+v", status)
+	}
+}
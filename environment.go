@@ -0,0 +1,81 @@
+package smartapigo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Environment is the set of urls the client and the streams created from it
+// connect to.
+type Environment struct {
+	Name string
+	// BaseURI is the base url of the REST API.
+	BaseURI string
+	// FeedURL is the url of the market data stream.
+	FeedURL url.URL
+	// OrderStreamURL is the url of the order status stream.
+	OrderStreamURL url.URL
+}
+
+// Production is the environment of the SmartAPI servers.
+var Production = Environment{
+	Name:           "production",
+	BaseURI:        baseURI,
+	FeedURL:        url.URL{Scheme: "wss", Host: "wsfeeds.angelbroking.com", Path: "/NestHtml5Mobile/socket/stream"},
+	OrderStreamURL: url.URL{Scheme: "wss", Host: "tns.angelone.in", Path: "/smart-order-update"},
+}
+
+// Sandbox returns the environment of a simulator or gateway serving the REST
+// API and the streams at the paths of production under the url, e.g.
+// "http://localhost:8080". The streams use ws, or wss for https urls.
+func Sandbox(rawURL string) (Environment, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Environment{}, err
+	}
+	scheme := "ws"
+	switch u.Scheme {
+	case "https":
+		scheme = "wss"
+	case "http":
+	default:
+		return Environment{}, fmt.Errorf("invalid sandbox url %q", rawURL)
+	}
+
+	root := strings.TrimSuffix(u.Path, "/")
+	return Environment{
+		Name:           "sandbox",
+		BaseURI:        u.Scheme + "://" + u.Host + root + "/",
+		FeedURL:        url.URL{Scheme: scheme, Host: u.Host, Path: root + Production.FeedURL.Path},
+		OrderStreamURL: url.URL{Scheme: scheme, Host: u.Host, Path: root + Production.OrderStreamURL.Path},
+	}, nil
+}
+
+// SetEnvironment points the REST API and the streams created from the client
+// with NewOrderStream and websocket.NewFromClient at the environment. Empty
+// urls are taken from Production.
+func (c *Client) SetEnvironment(env Environment) {
+	if env.BaseURI == "" {
+		env.BaseURI = Production.BaseURI
+	}
+	if !strings.HasSuffix(env.BaseURI, "/") {
+		env.BaseURI += "/"
+	}
+	if env.FeedURL.Host == "" {
+		env.FeedURL = Production.FeedURL
+	}
+	if env.OrderStreamURL.Host == "" {
+		env.OrderStreamURL = Production.OrderStreamURL
+	}
+	c.env = env
+	c.baseURI = env.BaseURI
+}
+
+// Environment returns the environment of the client, with the base url set with
+// SetBaseURI if any.
+func (c *Client) Environment() Environment {
+	env := c.env
+	env.BaseURI = c.baseURI
+	return env
+}
@@ -0,0 +1,78 @@
+package smartapigo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestSandbox(t *testing.T) {
+	env, err := Sandbox("https://gateway.example.com/smartapi/")
+	if err != nil {
+		t.Fatalf("Error while creating sandbox. %v", err)
+	}
+	if env.BaseURI != "https://gateway.example.com/smartapi/" ||
+		env.FeedURL.String() != "wss://gateway.example.com/smartapi/NestHtml5Mobile/socket/stream" ||
+		env.OrderStreamURL.String() != "wss://gateway.example.com/smartapi/smart-order-update" {
+		t.Errorf("Unexpected environment %+v", env)
+	}
+	if _, err := Sandbox("ftp://gateway.example.com"); err == nil {
+		t.Errorf("Expected an error for an invalid scheme")
+	}
+
+	client := New("test", "test", "test")
+	if client.Environment().Name != "production" {
+		t.Errorf("Expected the production environment, got %+v", client.Environment())
+	}
+	client.SetEnvironment(Environment{Name: "gateway", BaseURI: "https://gateway.example.com"})
+	if env := client.Environment(); env.BaseURI != "https://gateway.example.com/" || env.FeedURL != Production.FeedURL || env.OrderStreamURL != Production.OrderStreamURL {
+		t.Errorf("Unexpected environment %+v", env)
+	}
+}
+
+func TestSetEnvironment(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+URIUserProfile, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":true,"message":"SUCCESS","errorcode":"","data":{"clientcode":"sandbox"}}`))
+	})
+	mux.HandleFunc(Production.OrderStreamURL.Path, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	env, err := Sandbox(server.URL)
+	if err != nil {
+		t.Fatalf("Error while creating sandbox. %v", err)
+	}
+	client := New("test", "test", "test")
+	client.SetClientInfo(ClientInfo{LocalIP: "127.0.0.1", PublicIP: "127.0.0.1", MACAddress: "00:00:00:00:00:00"})
+	client.SetAccessToken("jwt")
+	client.SetEnvironment(env)
+
+	profile, err := client.GetUserProfile()
+	if err != nil || profile.ClientCode != "sandbox" {
+		t.Errorf("Unexpected profile %+v. %v", profile, err)
+	}
+
+	stream := client.NewOrderStream()
+	stream.SetAutoReconnect(false)
+	connected := make(chan struct{})
+	stream.SetOnConnect(func() { close(connected) })
+	go stream.Connect()
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected the order stream to connect to the sandbox.")
+	}
+	stream.Close()
+}
@@ -1,10 +1,58 @@
 package smartapigo
 
+import (
+	"errors"
+	"net/http"
+)
+
+// InputError is the code of errors returned for invalid parameters before a request is made.
+const InputError = "InputException"
+
 // Error is the error type used for all API errors.
 type Error struct {
-	Code      string
-	Message   string
-	Data      interface{}
+	Code       string
+	Message    string
+	Data       interface{}
+	// HTTPStatus is the status code of the response for errors returned with one.
+	HTTPStatus int
+	// Endpoint is the URI of the request for errors returned by the API.
+	Endpoint string
+	// RequestID is the X-Request-ID of the request for errors returned by the API.
+	RequestID string
+	// RateLimit is the rate limit hints sent with the response, e.g. Retry-After.
+	RateLimit RateLimitInfo
+}
+
+var (
+	// ErrTokenExpired matches errors for a missing, invalid or expired session.
+	ErrTokenExpired = errors.New("session token expired")
+	// ErrInvalidCredentials matches errors for an invalid client code or password.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrRateLimited matches errors for requests rejected for exceeding the rate limit.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrOrderRejected matches errors for orders rejected by the API.
+	ErrOrderRejected = errors.New("order rejected")
+)
+
+// errorCodes maps the error codes of the API to the sentinel errors they match.
+var errorCodes = map[string]error{
+	"AG8001": ErrTokenExpired,       // Invalid Token
+	"AG8002": ErrTokenExpired,       // Token Expired
+	"AG8003": ErrTokenExpired,       // Token missing
+	"AB1010": ErrTokenExpired,       // AMX Session Expired
+	"AB1011": ErrTokenExpired,       // Client not login
+	"AB8050": ErrTokenExpired,       // Invalid Refresh Token
+	"AB8051": ErrTokenExpired,       // Refresh Token Expired
+	"AB1000": ErrInvalidCredentials, // Invalid Email Or Password
+	"AB1001": ErrInvalidCredentials, // Invalid Email
+	"AB1002": ErrInvalidCredentials, // Invalid Password Length
+	"AB1004": ErrRateLimited,        // Something Went Wrong, Please Try After Sometime
+	"AB1006": ErrOrderRejected,      // Client is block for trading
+	"AB1008": ErrOrderRejected,      // Invalid Order Variety
+	"AB1009": ErrOrderRejected,      // Symbol Not Found
+	"AB1012": ErrOrderRejected,      // Invalid Product Type
+	"AB2002": ErrOrderRejected,      // ROBO order is block
+	"AB4008": ErrOrderRejected,      // ordertag length should be less than 20 characters
 }
 
 // This makes Error a valid Go error type.
@@ -12,6 +60,21 @@ func (e Error) Error() string {
 	return e.Message
 }
 
+// Is reports whether the error matches one of the sentinel errors ErrTokenExpired,
+// ErrInvalidCredentials, ErrRateLimited and ErrOrderRejected. Responses with
+// status 429, and with status 403 and rate limit hints, match ErrRateLimited.
+func (e Error) Is(target error) bool {
+	if target == ErrRateLimited && (e.HTTPStatus == http.StatusTooManyRequests ||
+		e.HTTPStatus == http.StatusForbidden && e.RateLimit.Wait() > 0) {
+		return true
+	}
+	if target == ErrTokenExpired && e.HTTPStatus == http.StatusUnauthorized {
+		return true
+	}
+	sentinel, ok := errorCodes[e.Code]
+	return ok && sentinel == target
+}
+
 // NewError creates and returns a new instace of Error
 // with custom error metadata.
 func NewError(etype string, message string, data interface{}) error {
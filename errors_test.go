@@ -0,0 +1,46 @@
+package smartapigo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestErrorIs(t *testing.T) {
+	cases := []struct {
+		err  Error
+		want error
+	}{
+		{Error{Code: "AG8002", Message: "Token Expired"}, ErrTokenExpired},
+		{Error{Code: "", Message: "Unauthorized", HTTPStatus: http.StatusUnauthorized}, ErrTokenExpired},
+		{Error{Code: "AB1000", Message: "Invalid Email Or Password"}, ErrInvalidCredentials},
+		{Error{Code: "AB1004", Message: "Something Went Wrong, Please Try After Sometime"}, ErrRateLimited},
+		{Error{Code: "", Message: "Too Many Requests", HTTPStatus: http.StatusTooManyRequests}, ErrRateLimited},
+		{Error{Code: "", Message: "Forbidden", HTTPStatus: http.StatusForbidden, RateLimit: RateLimitInfo{RetryAfter: time.Second}}, ErrRateLimited},
+		{Error{Code: "AB1009", Message: "Symbol Not Found"}, ErrOrderRejected},
+	}
+	for _, c := range cases {
+		if !errors.Is(c.err, c.want) {
+			t.Errorf("Expected %+v to match %v", c.err, c.want)
+		}
+		if !errors.Is(fmt.Errorf("wrapped: %w", c.err), c.want) {
+			t.Errorf("Expected wrapped %+v to match %v", c.err, c.want)
+		}
+	}
+
+	if errors.Is(Error{Code: "AB1009"}, ErrTokenExpired) {
+		t.Errorf("Unexpected match of an order rejection with ErrTokenExpired")
+	}
+}
+
+func TestErrorEndpoint(t *testing.T) {
+	client, _ := newRetryClient(URIModifyOrder, http.StatusServiceUnavailable, 1)
+	_, err := client.ModifyOrder(ModifyOrderParams{OrderID: "201020000000080"})
+
+	var apiErr Error
+	if !errors.As(err, &apiErr) || apiErr.Endpoint != URIModifyOrder || apiErr.HTTPStatus != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected error %+v", err)
+	}
+}
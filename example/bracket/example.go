@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	SmartApi "github.com/shammishailaj/smartapigo"
+)
+
+func main() {
+
+	// Create New Angel Broking Client
+	ABClient := SmartApi.New("Your Client Code", "Your Password", "Your api key")
+
+	// User Login and Generate User Session
+	_, err := ABClient.GenerateSession("your totp here")
+
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	//Place Bracket Order buying at 455.2 with a target 5 points above, a stop loss
+	//2.5 points below and the stop loss trailing the price by 1 point
+	order, err := ABClient.PlaceOrder(SmartApi.OrderParams{
+		Variety:          SmartApi.VarietyRobo,
+		TradingSymbol:    "SBIN-EQ",
+		SymbolToken:      "3045",
+		TransactionType:  SmartApi.TransactionBuy,
+		Exchange:         SmartApi.NSE,
+		OrderType:        SmartApi.OrderTypeLimit,
+		ProductType:      SmartApi.ProductBracket,
+		Duration:         SmartApi.DurationDay,
+		Price:            "455.2",
+		SquareOff:        "5",
+		StopLoss:         "2.5",
+		TrailingStopLoss: "1",
+		Quantity:         "1",
+	})
+
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("Placed Bracket Order ID :- ", order)
+
+	//The same order built and validated with the order builder
+	orderParams, err := ABClient.NewOrder().Buy(SmartApi.NSE, "SBIN-EQ").Token("3045").Qty(1).Limit(455.2).Bracket(5, 2.5, 1).Build()
+
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("Bracket Order Params :- ", orderParams)
+
+	//Exit Bracket Order, cancelling it when pending or squaring off its
+	//target and stop loss legs when executed
+	exitedOrder, err := ABClient.CancelOrder(string(SmartApi.VarietyRobo), order.OrderID)
+
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("Exited Bracket Order ID :- ", exitedOrder)
+}
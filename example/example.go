@@ -42,7 +42,7 @@ func main() {
 	fmt.Println("User Session Object :- ", session)
 
 	//Place Order
-	order, err := ABClient.PlaceOrder(SmartApi.OrderParams{Variety: "NORMAL", TradingSymbol: "SBIN-EQ", SymbolToken: "3045", TransactionType: "BUY", Exchange: "NSE", OrderType: "LIMIT", ProductType: "INTRADAY", Duration: "DAY", Price: "19500", SquareOff: "0", StopLoss: "0", Quantity: "1"})
+	order, err := ABClient.PlaceOrder(SmartApi.OrderParams{Variety: SmartApi.VarietyNormal, TradingSymbol: "SBIN-EQ", SymbolToken: "3045", TransactionType: SmartApi.TransactionBuy, Exchange: SmartApi.NSE, OrderType: SmartApi.OrderTypeLimit, ProductType: SmartApi.ProductIntraday, Duration: SmartApi.DurationDay, Price: "19500", SquareOff: "0", StopLoss: "0", Quantity: "1"})
 
 	if err != nil {
 		fmt.Println(err.Error())
@@ -52,7 +52,7 @@ func main() {
 	fmt.Println("Placed Order ID and Script :- ", order)
 
 	//Modify Order
-	modifiedOrder, err := ABClient.ModifyOrder(SmartApi.ModifyOrderParams{Variety: "NORMAL", OrderID: order.OrderID, OrderType: "LIMIT", ProductType: "INTRADAY", Duration: "DAY", Price: "19400", Quantity: "1", TradingSymbol: "SBI-EQ", SymbolToken: "3045", Exchange: "NSE"})
+	modifiedOrder, err := ABClient.ModifyOrder(SmartApi.ModifyOrderParams{Variety: SmartApi.VarietyNormal, OrderID: order.OrderID, OrderType: SmartApi.OrderTypeLimit, ProductType: SmartApi.ProductIntraday, Duration: SmartApi.DurationDay, Price: "19400", Quantity: "1", TradingSymbol: "SBI-EQ", SymbolToken: "3045", Exchange: SmartApi.NSE})
 
 	if err != nil {
 		fmt.Println(err.Error())
@@ -122,7 +122,7 @@ func main() {
 	fmt.Println("Risk Managemanet System :- ", rms)
 
 	//Position Conversion
-	err = ABClient.ConvertPosition(SmartApi.ConvertPositionParams{"NSE", "SBIN-EQ", "INTRADAY", "MARGIN", "BUY", 1, "DAY"})
+	err = ABClient.ConvertPosition(SmartApi.ConvertPositionParams{Exchange: "NSE", TradingSymbol: "SBIN-EQ", OldProductType: "INTRADAY", NewProductType: "MARGIN", TransactionType: "BUY", Quantity: 1, Type: "DAY"})
 	if err != nil {
 		fmt.Println(err.Error())
 		return
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	SmartApi "github.com/shammishailaj/smartapigo"
+	"github.com/shammishailaj/smartapigo/orderstream"
+	"time"
+)
+
+// Triggered when any error is raised
+func onError(err error) {
+	fmt.Println("Error: ", err)
+}
+
+// Triggered when websocket connection is closed
+func onClose(code int, reason string) {
+	fmt.Println("Close: ", code, reason)
+}
+
+// Triggered when connection is established
+func onConnect() {
+	fmt.Println("Connected")
+}
+
+// Triggered when an order update is received
+func onOrderUpdate(update orderstream.OrderUpdate) {
+	fmt.Printf("Order Update :- %s %s %s -> %s filled %d\n", update.OrderStatus, update.OrderData.OrderID, update.PreviousOrderStatus, update.OrderData.OrderStatus, update.OrderData.FilledQuantity())
+}
+
+// Triggered when reconnection is attempted which is enabled by default
+func onReconnect(attempt int, delay time.Duration) {
+	fmt.Printf("Reconnect attempt %d in %fs\n", attempt, delay.Seconds())
+}
+
+// Triggered when maximum number of reconnect attempt is made and the program is terminated
+func onNoReconnect(attempt int) {
+	fmt.Printf("Maximum no of reconnect attempt reached: %d\n", attempt)
+}
+
+func main() {
+
+	// Create New Angel Broking Client
+	ABClient := SmartApi.New("Your Client Code", "Your Password", "Your api key")
+
+	// User Login and Generate User Session
+	_, err := ABClient.GenerateSession("your totp here")
+
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	// New Order Stream Client authenticated with the session of the client
+	orderStream := ABClient.NewOrderStream()
+
+	// Assign callbacks
+	orderStream.SetOnError(onError)
+	orderStream.SetOnClose(onClose)
+	orderStream.SetOnConnect(onConnect)
+	orderStream.SetOnOrderUpdate(onOrderUpdate)
+	orderStream.SetOnReconnect(onReconnect)
+	orderStream.SetOnNoReconnect(onNoReconnect)
+
+	// Start Consuming Order Updates
+	orderStream.Connect()
+
+}
@@ -0,0 +1,610 @@
+// Package fake implements smartapigo.SmartAPI with programmable responses and
+// captured calls, to unit test code using the API without a server.
+//
+//	client := fake.New()
+//	client.Respond("PlaceOrder", smartapi.OrderResponse{OrderID: "1"}, nil)
+//	strategy := NewStrategy(client)
+//	...
+//	calls := client.Calls("PlaceOrder")
+package fake
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	smartapi "github.com/shammishailaj/smartapigo"
+)
+
+// Call is a call of a method of the fake client.
+type Call struct {
+	// Method is the name of the method without its Ctx suffix.
+	Method string
+	// Args are the arguments of the call but its context.
+	Args []interface{}
+}
+
+// Client is a fake smartapigo.SmartAPI. Methods without a response set return
+// the zero value of their result and no error. Methods and their Ctx variant
+// share their response and calls, and calls with a done context return its
+// error without being recorded.
+type Client struct {
+	mu        sync.Mutex
+	responses map[string]func(args ...interface{}) (interface{}, error)
+	calls     []Call
+}
+
+var _ smartapi.SmartAPI = (*Client)(nil)
+
+// New creates a fake client.
+func New() *Client {
+	return &Client{responses: make(map[string]func(args ...interface{}) (interface{}, error))}
+}
+
+// Respond sets the result and error returned by every call of the method, named
+// without its Ctx suffix. The result must be of the result type of the method.
+func (f *Client) Respond(method string, result interface{}, err error) {
+	f.RespondFunc(method, func(args ...interface{}) (interface{}, error) {
+		return result, err
+	})
+}
+
+// RespondFunc sets the function returning the result and error of every call of
+// the method, named without its Ctx suffix, from the arguments of the call.
+func (f *Client) RespondFunc(method string, respond func(args ...interface{}) (interface{}, error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[method] = respond
+}
+
+// Calls returns the calls of the method in order, or of all methods when method is empty.
+func (f *Client) Calls(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []Call
+	for _, call := range f.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset clears the responses and calls.
+func (f *Client) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = make(map[string]func(args ...interface{}) (interface{}, error))
+	f.calls = nil
+}
+
+// call records the call of the method and stores its response in result.
+func (f *Client) call(ctx context.Context, method string, result interface{}, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+	respond := f.responses[method]
+	f.mu.Unlock()
+	if respond == nil {
+		return nil
+	}
+
+	value, err := respond(args...)
+	if value != nil && result != nil {
+		target := reflect.ValueOf(result).Elem()
+		v := reflect.ValueOf(value)
+		if !v.Type().AssignableTo(target.Type()) {
+			panic(fmt.Sprintf("fake: response of %s is %T, want %s", method, value, target.Type()))
+		}
+		target.Set(v)
+	}
+	return err
+}
+
+// CancelAllOrders records the call and returns the response set for CancelAllOrders.
+func (f *Client) CancelAllOrders(filter smartapi.OrderFilter) ([]smartapi.CancelResult, error) {
+	return f.CancelAllOrdersCtx(context.Background(), filter)
+}
+
+// CancelAllOrdersCtx is CancelAllOrders with a context for the request.
+func (f *Client) CancelAllOrdersCtx(ctx context.Context, filter smartapi.OrderFilter) ([]smartapi.CancelResult, error) {
+	var result []smartapi.CancelResult
+	err := f.call(ctx, "CancelAllOrders", &result, filter)
+	return result, err
+}
+
+// CancelGTTOCO records the call and returns the response set for CancelGTTOCO.
+func (f *Client) CancelGTTOCO(oco smartapi.GTTOCO) error {
+	return f.CancelGTTOCOCtx(context.Background(), oco)
+}
+
+// CancelGTTOCOCtx is CancelGTTOCO with a context for the request.
+func (f *Client) CancelGTTOCOCtx(ctx context.Context, oco smartapi.GTTOCO) error {
+	return f.call(ctx, "CancelGTTOCO", nil, oco)
+}
+
+// CancelGTTRule records the call and returns the response set for CancelGTTRule.
+func (f *Client) CancelGTTRule(cancelGTTParams smartapi.CancelGTTParams) (smartapi.GTTRuleResponse, error) {
+	return f.CancelGTTRuleCtx(context.Background(), cancelGTTParams)
+}
+
+// CancelGTTRuleCtx is CancelGTTRule with a context for the request.
+func (f *Client) CancelGTTRuleCtx(ctx context.Context, cancelGTTParams smartapi.CancelGTTParams) (smartapi.GTTRuleResponse, error) {
+	var result smartapi.GTTRuleResponse
+	err := f.call(ctx, "CancelGTTRule", &result, cancelGTTParams)
+	return result, err
+}
+
+// CancelOrder records the call and returns the response set for CancelOrder.
+func (f *Client) CancelOrder(variety string, orderid string) (smartapi.OrderResponse, error) {
+	return f.CancelOrderCtx(context.Background(), variety, orderid)
+}
+
+// CancelOrderCtx is CancelOrder with a context for the request.
+func (f *Client) CancelOrderCtx(ctx context.Context, variety string, orderid string) (smartapi.OrderResponse, error) {
+	var result smartapi.OrderResponse
+	err := f.call(ctx, "CancelOrder", &result, variety, orderid)
+	return result, err
+}
+
+// ConvertPosition records the call and returns the response set for ConvertPosition.
+func (f *Client) ConvertPosition(convertPositionParams smartapi.ConvertPositionParams) error {
+	return f.ConvertPositionCtx(context.Background(), convertPositionParams)
+}
+
+// ConvertPositionCtx is ConvertPosition with a context for the request.
+func (f *Client) ConvertPositionCtx(ctx context.Context, convertPositionParams smartapi.ConvertPositionParams) error {
+	return f.call(ctx, "ConvertPosition", nil, convertPositionParams)
+}
+
+// CreateGTTOCO records the call and returns the response set for CreateGTTOCO.
+func (f *Client) CreateGTTOCO(params smartapi.GTTOCOParams) (smartapi.GTTOCO, error) {
+	return f.CreateGTTOCOCtx(context.Background(), params)
+}
+
+// CreateGTTOCOCtx is CreateGTTOCO with a context for the request.
+func (f *Client) CreateGTTOCOCtx(ctx context.Context, params smartapi.GTTOCOParams) (smartapi.GTTOCO, error) {
+	var result smartapi.GTTOCO
+	err := f.call(ctx, "CreateGTTOCO", &result, params)
+	return result, err
+}
+
+// CreateGTTRule records the call and returns the response set for CreateGTTRule.
+func (f *Client) CreateGTTRule(gttParams smartapi.GTTParams) (smartapi.GTTRuleResponse, error) {
+	return f.CreateGTTRuleCtx(context.Background(), gttParams)
+}
+
+// CreateGTTRuleCtx is CreateGTTRule with a context for the request.
+func (f *Client) CreateGTTRuleCtx(ctx context.Context, gttParams smartapi.GTTParams) (smartapi.GTTRuleResponse, error) {
+	var result smartapi.GTTRuleResponse
+	err := f.call(ctx, "CreateGTTRule", &result, gttParams)
+	return result, err
+}
+
+// GenerateSession records the call and returns the response set for GenerateSession.
+func (f *Client) GenerateSession(totp string) (smartapi.UserSession, error) {
+	return f.GenerateSessionCtx(context.Background(), totp)
+}
+
+// GenerateSessionCtx is GenerateSession with a context for the request.
+func (f *Client) GenerateSessionCtx(ctx context.Context, totp string) (smartapi.UserSession, error) {
+	var result smartapi.UserSession
+	err := f.call(ctx, "GenerateSession", &result, totp)
+	return result, err
+}
+
+// GenerateTPIN records the call and returns the response set for GenerateTPIN.
+func (f *Client) GenerateTPIN(generateTPINParams smartapi.GenerateTPINParams) (bool, error) {
+	return f.GenerateTPINCtx(context.Background(), generateTPINParams)
+}
+
+// GenerateTPINCtx is GenerateTPIN with a context for the request.
+func (f *Client) GenerateTPINCtx(ctx context.Context, generateTPINParams smartapi.GenerateTPINParams) (bool, error) {
+	var result bool
+	err := f.call(ctx, "GenerateTPIN", &result, generateTPINParams)
+	return result, err
+}
+
+// GetAllGTTRules records the call and returns the response set for GetAllGTTRules.
+func (f *Client) GetAllGTTRules(statuses []smartapi.GTTStatus, pageSize int) (smartapi.GTTRules, error) {
+	return f.GetAllGTTRulesCtx(context.Background(), statuses, pageSize)
+}
+
+// GetAllGTTRulesCtx is GetAllGTTRules with a context for the request.
+func (f *Client) GetAllGTTRulesCtx(ctx context.Context, statuses []smartapi.GTTStatus, pageSize int) (smartapi.GTTRules, error) {
+	var result smartapi.GTTRules
+	err := f.call(ctx, "GetAllGTTRules", &result, statuses, pageSize)
+	return result, err
+}
+
+// GetAllHoldings records the call and returns the response set for GetAllHoldings.
+func (f *Client) GetAllHoldings() (smartapi.AllHoldings, error) {
+	return f.GetAllHoldingsCtx(context.Background())
+}
+
+// GetAllHoldingsCtx is GetAllHoldings with a context for the request.
+func (f *Client) GetAllHoldingsCtx(ctx context.Context) (smartapi.AllHoldings, error) {
+	var result smartapi.AllHoldings
+	err := f.call(ctx, "GetAllHoldings", &result)
+	return result, err
+}
+
+// GetBasketMargin records the call and returns the response set for GetBasketMargin.
+func (f *Client) GetBasketMargin(basket []smartapi.OrderParams) (smartapi.BasketMargin, error) {
+	return f.GetBasketMarginCtx(context.Background(), basket)
+}
+
+// GetBasketMarginCtx is GetBasketMargin with a context for the request.
+func (f *Client) GetBasketMarginCtx(ctx context.Context, basket []smartapi.OrderParams) (smartapi.BasketMargin, error) {
+	var result smartapi.BasketMargin
+	err := f.call(ctx, "GetBasketMargin", &result, basket)
+	return result, err
+}
+
+// GetCandleData records the call and returns the response set for GetCandleData.
+func (f *Client) GetCandleData(params *smartapi.HistoryParams) ([]smartapi.HistoryDatum, error) {
+	return f.GetCandleDataCtx(context.Background(), params)
+}
+
+// GetCandleDataCtx is GetCandleData with a context for the request.
+func (f *Client) GetCandleDataCtx(ctx context.Context, params *smartapi.HistoryParams) ([]smartapi.HistoryDatum, error) {
+	var result []smartapi.HistoryDatum
+	err := f.call(ctx, "GetCandleData", &result, params)
+	return result, err
+}
+
+// GetDISStatus records the call and returns the response set for GetDISStatus.
+func (f *Client) GetDISStatus(reqID string) (smartapi.DISStatus, error) {
+	return f.GetDISStatusCtx(context.Background(), reqID)
+}
+
+// GetDISStatusCtx is GetDISStatus with a context for the request.
+func (f *Client) GetDISStatusCtx(ctx context.Context, reqID string) (smartapi.DISStatus, error) {
+	var result smartapi.DISStatus
+	err := f.call(ctx, "GetDISStatus", &result, reqID)
+	return result, err
+}
+
+// GetGTTRuleDetails records the call and returns the response set for GetGTTRuleDetails.
+func (f *Client) GetGTTRuleDetails(ruleID smartapi.GTTRuleID) (smartapi.GTTRule, error) {
+	return f.GetGTTRuleDetailsCtx(context.Background(), ruleID)
+}
+
+// GetGTTRuleDetailsCtx is GetGTTRuleDetails with a context for the request.
+func (f *Client) GetGTTRuleDetailsCtx(ctx context.Context, ruleID smartapi.GTTRuleID) (smartapi.GTTRule, error) {
+	var result smartapi.GTTRule
+	err := f.call(ctx, "GetGTTRuleDetails", &result, ruleID)
+	return result, err
+}
+
+// GetGTTRuleList records the call and returns the response set for GetGTTRuleList.
+func (f *Client) GetGTTRuleList(statuses []smartapi.GTTStatus, page int, count int) (smartapi.GTTRules, error) {
+	return f.GetGTTRuleListCtx(context.Background(), statuses, page, count)
+}
+
+// GetGTTRuleListCtx is GetGTTRuleList with a context for the request.
+func (f *Client) GetGTTRuleListCtx(ctx context.Context, statuses []smartapi.GTTStatus, page int, count int) (smartapi.GTTRules, error) {
+	var result smartapi.GTTRules
+	err := f.call(ctx, "GetGTTRuleList", &result, statuses, page, count)
+	return result, err
+}
+
+// GetHoldings records the call and returns the response set for GetHoldings.
+func (f *Client) GetHoldings() (smartapi.Holdings, error) {
+	return f.GetHoldingsCtx(context.Background())
+}
+
+// GetHoldingsCtx is GetHoldings with a context for the request.
+func (f *Client) GetHoldingsCtx(ctx context.Context) (smartapi.Holdings, error) {
+	var result smartapi.Holdings
+	err := f.call(ctx, "GetHoldings", &result)
+	return result, err
+}
+
+// GetLTP records the call and returns the response set for GetLTP.
+func (f *Client) GetLTP(ltpParams smartapi.LTPParams) (smartapi.LTPResponse, error) {
+	return f.GetLTPCtx(context.Background(), ltpParams)
+}
+
+// GetLTPCtx is GetLTP with a context for the request.
+func (f *Client) GetLTPCtx(ctx context.Context, ltpParams smartapi.LTPParams) (smartapi.LTPResponse, error) {
+	var result smartapi.LTPResponse
+	err := f.call(ctx, "GetLTP", &result, ltpParams)
+	return result, err
+}
+
+// GetMargin records the call and returns the response set for GetMargin.
+func (f *Client) GetMargin(positions []smartapi.MarginPosition) (smartapi.MarginResponse, error) {
+	return f.GetMarginCtx(context.Background(), positions)
+}
+
+// GetMarginCtx is GetMargin with a context for the request.
+func (f *Client) GetMarginCtx(ctx context.Context, positions []smartapi.MarginPosition) (smartapi.MarginResponse, error) {
+	var result smartapi.MarginResponse
+	err := f.call(ctx, "GetMargin", &result, positions)
+	return result, err
+}
+
+// GetMarketData records the call and returns the response set for GetMarketData.
+func (f *Client) GetMarketData(mode smartapi.QuoteMode, exchangeTokens map[smartapi.Exchange][]string) (smartapi.MarketData, error) {
+	return f.GetMarketDataCtx(context.Background(), mode, exchangeTokens)
+}
+
+// GetMarketDataCtx is GetMarketData with a context for the request.
+func (f *Client) GetMarketDataCtx(ctx context.Context, mode smartapi.QuoteMode, exchangeTokens map[smartapi.Exchange][]string) (smartapi.MarketData, error) {
+	var result smartapi.MarketData
+	err := f.call(ctx, "GetMarketData", &result, mode, exchangeTokens)
+	return result, err
+}
+
+// GetOIBuildup records the call and returns the response set for GetOIBuildup.
+func (f *Client) GetOIBuildup(expiryType smartapi.ExpiryType, dataType smartapi.OIBuildupType) (smartapi.OIBuildups, error) {
+	return f.GetOIBuildupCtx(context.Background(), expiryType, dataType)
+}
+
+// GetOIBuildupCtx is GetOIBuildup with a context for the request.
+func (f *Client) GetOIBuildupCtx(ctx context.Context, expiryType smartapi.ExpiryType, dataType smartapi.OIBuildupType) (smartapi.OIBuildups, error) {
+	var result smartapi.OIBuildups
+	err := f.call(ctx, "GetOIBuildup", &result, expiryType, dataType)
+	return result, err
+}
+
+// GetOrderBook records the call and returns the response set for GetOrderBook.
+func (f *Client) GetOrderBook() (smartapi.Orders, error) {
+	return f.GetOrderBookCtx(context.Background())
+}
+
+// GetOrderBookCtx is GetOrderBook with a context for the request.
+func (f *Client) GetOrderBookCtx(ctx context.Context) (smartapi.Orders, error) {
+	var result smartapi.Orders
+	err := f.call(ctx, "GetOrderBook", &result)
+	return result, err
+}
+
+// GetOrdersByTag records the call and returns the response set for GetOrdersByTag.
+func (f *Client) GetOrdersByTag(tag string) (smartapi.Orders, error) {
+	return f.GetOrdersByTagCtx(context.Background(), tag)
+}
+
+// GetOrdersByTagCtx is GetOrdersByTag with a context for the request.
+func (f *Client) GetOrdersByTagCtx(ctx context.Context, tag string) (smartapi.Orders, error) {
+	var result smartapi.Orders
+	err := f.call(ctx, "GetOrdersByTag", &result, tag)
+	return result, err
+}
+
+// GetPositions records the call and returns the response set for GetPositions.
+func (f *Client) GetPositions() (smartapi.Positions, error) {
+	return f.GetPositionsCtx(context.Background())
+}
+
+// GetPositionsCtx is GetPositions with a context for the request.
+func (f *Client) GetPositionsCtx(ctx context.Context) (smartapi.Positions, error) {
+	var result smartapi.Positions
+	err := f.call(ctx, "GetPositions", &result)
+	return result, err
+}
+
+// GetRMS records the call and returns the response set for GetRMS.
+func (f *Client) GetRMS() (smartapi.RMS, error) {
+	return f.GetRMSCtx(context.Background())
+}
+
+// GetRMSCtx is GetRMS with a context for the request.
+func (f *Client) GetRMSCtx(ctx context.Context) (smartapi.RMS, error) {
+	var result smartapi.RMS
+	err := f.call(ctx, "GetRMS", &result)
+	return result, err
+}
+
+// GetSlicedOrder records the call and returns the response set for GetSlicedOrder.
+func (f *Client) GetSlicedOrder(sliced smartapi.SlicedOrder) (smartapi.SlicedOrder, error) {
+	return f.GetSlicedOrderCtx(context.Background(), sliced)
+}
+
+// GetSlicedOrderCtx is GetSlicedOrder with a context for the request.
+func (f *Client) GetSlicedOrderCtx(ctx context.Context, sliced smartapi.SlicedOrder) (smartapi.SlicedOrder, error) {
+	var result smartapi.SlicedOrder
+	err := f.call(ctx, "GetSlicedOrder", &result, sliced)
+	return result, err
+}
+
+// GetTradeBook records the call and returns the response set for GetTradeBook.
+func (f *Client) GetTradeBook() (smartapi.Trades, error) {
+	return f.GetTradeBookCtx(context.Background())
+}
+
+// GetTradeBookCtx is GetTradeBook with a context for the request.
+func (f *Client) GetTradeBookCtx(ctx context.Context) (smartapi.Trades, error) {
+	var result smartapi.Trades
+	err := f.call(ctx, "GetTradeBook", &result)
+	return result, err
+}
+
+// GetTradesByOrderID records the call and returns the response set for GetTradesByOrderID.
+func (f *Client) GetTradesByOrderID(orderID string) (smartapi.Fills, error) {
+	return f.GetTradesByOrderIDCtx(context.Background(), orderID)
+}
+
+// GetTradesByOrderIDCtx is GetTradesByOrderID with a context for the request.
+func (f *Client) GetTradesByOrderIDCtx(ctx context.Context, orderID string) (smartapi.Fills, error) {
+	var result smartapi.Fills
+	err := f.call(ctx, "GetTradesByOrderID", &result, orderID)
+	return result, err
+}
+
+// GetUserProfile records the call and returns the response set for GetUserProfile.
+func (f *Client) GetUserProfile() (smartapi.UserProfile, error) {
+	return f.GetUserProfileCtx(context.Background())
+}
+
+// GetUserProfileCtx is GetUserProfile with a context for the request.
+func (f *Client) GetUserProfileCtx(ctx context.Context) (smartapi.UserProfile, error) {
+	var result smartapi.UserProfile
+	err := f.call(ctx, "GetUserProfile", &result)
+	return result, err
+}
+
+// Logout records the call and returns the response set for Logout.
+func (f *Client) Logout() (bool, error) {
+	return f.LogoutCtx(context.Background())
+}
+
+// LogoutCtx is Logout with a context for the request.
+func (f *Client) LogoutCtx(ctx context.Context) (bool, error) {
+	var result bool
+	err := f.call(ctx, "Logout", &result)
+	return result, err
+}
+
+// ModifyGTTOCO records the call and returns the response set for ModifyGTTOCO.
+func (f *Client) ModifyGTTOCO(oco smartapi.GTTOCO, params smartapi.GTTOCOParams) error {
+	return f.ModifyGTTOCOCtx(context.Background(), oco, params)
+}
+
+// ModifyGTTOCOCtx is ModifyGTTOCO with a context for the request.
+func (f *Client) ModifyGTTOCOCtx(ctx context.Context, oco smartapi.GTTOCO, params smartapi.GTTOCOParams) error {
+	return f.call(ctx, "ModifyGTTOCO", nil, oco, params)
+}
+
+// ModifyGTTRule records the call and returns the response set for ModifyGTTRule.
+func (f *Client) ModifyGTTRule(modifyGTTParams smartapi.ModifyGTTParams) (smartapi.GTTRuleResponse, error) {
+	return f.ModifyGTTRuleCtx(context.Background(), modifyGTTParams)
+}
+
+// ModifyGTTRuleCtx is ModifyGTTRule with a context for the request.
+func (f *Client) ModifyGTTRuleCtx(ctx context.Context, modifyGTTParams smartapi.ModifyGTTParams) (smartapi.GTTRuleResponse, error) {
+	var result smartapi.GTTRuleResponse
+	err := f.call(ctx, "ModifyGTTRule", &result, modifyGTTParams)
+	return result, err
+}
+
+// ModifyOrder records the call and returns the response set for ModifyOrder.
+func (f *Client) ModifyOrder(modifyOrderParams smartapi.ModifyOrderParams) (smartapi.OrderResponse, error) {
+	return f.ModifyOrderCtx(context.Background(), modifyOrderParams)
+}
+
+// ModifyOrderCtx is ModifyOrder with a context for the request.
+func (f *Client) ModifyOrderCtx(ctx context.Context, modifyOrderParams smartapi.ModifyOrderParams) (smartapi.OrderResponse, error) {
+	var result smartapi.OrderResponse
+	err := f.call(ctx, "ModifyOrder", &result, modifyOrderParams)
+	return result, err
+}
+
+// ModifyOrderPartial records the call and returns the response set for ModifyOrderPartial.
+func (f *Client) ModifyOrderPartial(orderID string, changes smartapi.OrderChanges) (smartapi.OrderResponse, error) {
+	return f.ModifyOrderPartialCtx(context.Background(), orderID, changes)
+}
+
+// ModifyOrderPartialCtx is ModifyOrderPartial with a context for the request.
+func (f *Client) ModifyOrderPartialCtx(ctx context.Context, orderID string, changes smartapi.OrderChanges) (smartapi.OrderResponse, error) {
+	var result smartapi.OrderResponse
+	err := f.call(ctx, "ModifyOrderPartial", &result, orderID, changes)
+	return result, err
+}
+
+// PlaceOrder records the call and returns the response set for PlaceOrder.
+func (f *Client) PlaceOrder(orderParams smartapi.OrderParams) (smartapi.OrderResponse, error) {
+	return f.PlaceOrderCtx(context.Background(), orderParams)
+}
+
+// PlaceOrderCtx is PlaceOrder with a context for the request.
+func (f *Client) PlaceOrderCtx(ctx context.Context, orderParams smartapi.OrderParams) (smartapi.OrderResponse, error) {
+	var result smartapi.OrderResponse
+	err := f.call(ctx, "PlaceOrder", &result, orderParams)
+	return result, err
+}
+
+// PlaceOrders records the call and returns the response set for PlaceOrders.
+func (f *Client) PlaceOrders(orders []smartapi.OrderParams) []smartapi.PlaceResult {
+	return f.PlaceOrdersCtx(context.Background(), orders)
+}
+
+// PlaceOrdersCtx is PlaceOrders with a context for the request. Its error, if
+// any, is set as the error of every order.
+func (f *Client) PlaceOrdersCtx(ctx context.Context, orders []smartapi.OrderParams) []smartapi.PlaceResult {
+	var result []smartapi.PlaceResult
+	if err := f.call(ctx, "PlaceOrders", &result, orders); err != nil {
+		result = make([]smartapi.PlaceResult, len(orders))
+		for i := range result {
+			result[i].Err = err
+		}
+	}
+	return result
+}
+
+// PlaceSlicedOrder records the call and returns the response set for PlaceSlicedOrder.
+func (f *Client) PlaceSlicedOrder(orderParams smartapi.OrderParams, opts smartapi.SliceOptions) (smartapi.SlicedOrder, error) {
+	return f.PlaceSlicedOrderCtx(context.Background(), orderParams, opts)
+}
+
+// PlaceSlicedOrderCtx is PlaceSlicedOrder with a context for the request.
+func (f *Client) PlaceSlicedOrderCtx(ctx context.Context, orderParams smartapi.OrderParams, opts smartapi.SliceOptions) (smartapi.SlicedOrder, error) {
+	var result smartapi.SlicedOrder
+	err := f.call(ctx, "PlaceSlicedOrder", &result, orderParams, opts)
+	return result, err
+}
+
+// PreviewOrder records the call and returns the response set for PreviewOrder.
+func (f *Client) PreviewOrder(orderParams smartapi.OrderParams) (smartapi.OrderPreview, error) {
+	return f.PreviewOrderCtx(context.Background(), orderParams)
+}
+
+// PreviewOrderCtx is PreviewOrder with a context for the request.
+func (f *Client) PreviewOrderCtx(ctx context.Context, orderParams smartapi.OrderParams) (smartapi.OrderPreview, error) {
+	var result smartapi.OrderPreview
+	err := f.call(ctx, "PreviewOrder", &result, orderParams)
+	return result, err
+}
+
+// RenewAccessToken records the call and returns the response set for RenewAccessToken.
+func (f *Client) RenewAccessToken(refreshToken string) (smartapi.UserSessionTokens, error) {
+	return f.RenewAccessTokenCtx(context.Background(), refreshToken)
+}
+
+// RenewAccessTokenCtx is RenewAccessToken with a context for the request.
+func (f *Client) RenewAccessTokenCtx(ctx context.Context, refreshToken string) (smartapi.UserSessionTokens, error) {
+	var result smartapi.UserSessionTokens
+	err := f.call(ctx, "RenewAccessToken", &result, refreshToken)
+	return result, err
+}
+
+// SearchScrip records the call and returns the response set for SearchScrip.
+func (f *Client) SearchScrip(exchange smartapi.Exchange, searchText string) (smartapi.ScripResults, error) {
+	return f.SearchScripCtx(context.Background(), exchange, searchText)
+}
+
+// SearchScripCtx is SearchScrip with a context for the request.
+func (f *Client) SearchScripCtx(ctx context.Context, exchange smartapi.Exchange, searchText string) (smartapi.ScripResults, error) {
+	var result smartapi.ScripResults
+	err := f.call(ctx, "SearchScrip", &result, exchange, searchText)
+	return result, err
+}
+
+// SyncGTTOCO records the call and returns the response set for SyncGTTOCO.
+func (f *Client) SyncGTTOCO(oco smartapi.GTTOCO) (smartapi.GTTRuleID, error) {
+	return f.SyncGTTOCOCtx(context.Background(), oco)
+}
+
+// SyncGTTOCOCtx is SyncGTTOCO with a context for the request.
+func (f *Client) SyncGTTOCOCtx(ctx context.Context, oco smartapi.GTTOCO) (smartapi.GTTRuleID, error) {
+	var result smartapi.GTTRuleID
+	err := f.call(ctx, "SyncGTTOCO", &result, oco)
+	return result, err
+}
+
+// VerifyDIS records the call and returns the response set for VerifyDIS.
+func (f *Client) VerifyDIS(verifyDISParams smartapi.VerifyDISParams) (smartapi.VerifyDISResponse, error) {
+	return f.VerifyDISCtx(context.Background(), verifyDISParams)
+}
+
+// VerifyDISCtx is VerifyDIS with a context for the request.
+func (f *Client) VerifyDISCtx(ctx context.Context, verifyDISParams smartapi.VerifyDISParams) (smartapi.VerifyDISResponse, error) {
+	var result smartapi.VerifyDISResponse
+	err := f.call(ctx, "VerifyDIS", &result, verifyDISParams)
+	return result, err
+}
@@ -0,0 +1,86 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	smartapi "github.com/shammishailaj/smartapigo"
+)
+
+// placeOrders places the orders with the client, as code under test would.
+func placeOrders(client smartapi.SmartAPI, orders ...smartapi.OrderParams) ([]string, error) {
+	var ids []string
+	for _, order := range orders {
+		response, err := client.PlaceOrder(order)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, response.OrderID)
+	}
+	return ids, nil
+}
+
+func TestClient(t *testing.T) {
+	client := New()
+	client.Respond("PlaceOrder", smartapi.OrderResponse{OrderID: "1"}, nil)
+
+	buy := smartapi.OrderParams{TradingSymbol: "SBIN-EQ", TransactionType: smartapi.TransactionBuy}
+	sell := smartapi.OrderParams{TradingSymbol: "SBIN-EQ", TransactionType: smartapi.TransactionSell}
+	ids, err := placeOrders(client, buy, sell)
+	if err != nil || !reflect.DeepEqual(ids, []string{"1", "1"}) {
+		t.Errorf("Unexpected order ids %v. %v", ids, err)
+	}
+
+	calls := client.Calls("PlaceOrder")
+	if len(calls) != 2 || calls[0].Args[0] != buy || calls[1].Args[0] != sell {
+		t.Errorf("Unexpected calls %+v", calls)
+	}
+
+	rejected := errors.New("rejected")
+	client.RespondFunc("PlaceOrder", func(args ...interface{}) (interface{}, error) {
+		if args[0].(smartapi.OrderParams).TransactionType == smartapi.TransactionSell {
+			return nil, rejected
+		}
+		return smartapi.OrderResponse{OrderID: "2"}, nil
+	})
+	if ids, err := placeOrders(client, buy, sell); !errors.Is(err, rejected) || !reflect.DeepEqual(ids, []string{"2"}) {
+		t.Errorf("Unexpected order ids %v. %v", ids, err)
+	}
+}
+
+func TestClientDefaults(t *testing.T) {
+	client := New()
+	if orders, err := client.GetOrderBook(); orders != nil || err != nil {
+		t.Errorf("Expected no orders, got %v. %v", orders, err)
+	}
+	if err := client.ConvertPosition(smartapi.ConvertPositionParams{}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetRMSCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancelled context error, got %v", err)
+	}
+	if calls := client.Calls(""); len(calls) != 2 || calls[0].Method != "GetOrderBook" || calls[1].Method != "ConvertPosition" {
+		t.Errorf("Unexpected calls %+v", calls)
+	}
+
+	client.Reset()
+	if calls := client.Calls(""); len(calls) != 0 {
+		t.Errorf("Expected no calls after reset, got %+v", calls)
+	}
+}
+
+func TestClientResponseType(t *testing.T) {
+	client := New()
+	client.Respond("GetRMS", smartapi.Orders{}, nil)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a response of the wrong type")
+		}
+	}()
+	_, _ = client.GetRMS()
+}
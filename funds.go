@@ -1,28 +1,91 @@
 package smartapigo
 
-import "net/http"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
 
 // RMS represents API response.
 type RMS struct {
-	Net string `json:"net"`
-	AvailableCash string `json:"availablecash"`
-	AvailableIntraDayPayIn string `json:"availableintradaypayin"`
-	AvailableLimitMargin string `json:"availablelimitmargin"`
-	Collateral string `json:"collateral"`
-	M2MUnrealized string `json:"m2munrealized"`
-	M2MRealized string `json:"m2mrealized"`
-	UtilisedDebits string `json:"utiliseddebits"`
-	UtilisedSpan string `json:"utilisedspan"`
-	UtilisedOptionPremium string `json:"utilisedoptionpremium"`
-	UtilisedHoldingSales string `json:"utilisedholdingsales"`
-	UtilisedExposure string `json:"utilisedexposure"`
-	UtilisedTurnover string `json:"utilisedturnover"`
-	UtilisedPayout string `json:"utilisedpayout"`
+	Net                    float64 `json:"net"`
+	AvailableCash          float64 `json:"availablecash"`
+	AvailableIntraDayPayIn float64 `json:"availableintradaypayin"`
+	AvailableLimitMargin   float64 `json:"availablelimitmargin"`
+	Collateral             float64 `json:"collateral"`
+	M2MUnrealized          float64 `json:"m2munrealized"`
+	M2MRealized            float64 `json:"m2mrealized"`
+	UtilisedDebits         float64 `json:"utiliseddebits"`
+	UtilisedSpan           float64 `json:"utilisedspan"`
+	UtilisedOptionPremium  float64 `json:"utilisedoptionpremium"`
+	UtilisedHoldingSales   float64 `json:"utilisedholdingsales"`
+	UtilisedExposure       float64 `json:"utilisedexposure"`
+	UtilisedTurnover       float64 `json:"utilisedturnover"`
+	UtilisedPayout         float64 `json:"utilisedpayout"`
+
+	// Raw holds the values of the response as returned, keyed by their names.
+	Raw map[string]string `json:"-"`
+}
+
+// UnmarshalJSON parses the balances of the response, returned as strings or
+// numbers, keeping the values as returned in Raw. Empty and null balances are zero.
+func (rms *RMS) UnmarshalJSON(data []byte) error {
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	rms.Raw = make(map[string]string, len(values))
+	for name, value := range values {
+		switch value := value.(type) {
+		case string:
+			rms.Raw[name] = value
+		case float64:
+			rms.Raw[name] = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+
+	balances := map[string]*float64{
+		"net":                    &rms.Net,
+		"availablecash":          &rms.AvailableCash,
+		"availableintradaypayin": &rms.AvailableIntraDayPayIn,
+		"availablelimitmargin":   &rms.AvailableLimitMargin,
+		"collateral":             &rms.Collateral,
+		"m2munrealized":          &rms.M2MUnrealized,
+		"m2mrealized":            &rms.M2MRealized,
+		"utiliseddebits":         &rms.UtilisedDebits,
+		"utilisedspan":           &rms.UtilisedSpan,
+		"utilisedoptionpremium":  &rms.UtilisedOptionPremium,
+		"utilisedholdingsales":   &rms.UtilisedHoldingSales,
+		"utilisedexposure":       &rms.UtilisedExposure,
+		"utilisedturnover":       &rms.UtilisedTurnover,
+		"utilisedpayout":         &rms.UtilisedPayout,
+	}
+	for name, balance := range balances {
+		*balance = 0
+		value := rms.Raw[name]
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid RMS %s %q: %w", name, value, err)
+		}
+		*balance = parsed
+	}
+	return nil
 }
 
 // GetRMS gets Risk Management System.
 func (c *Client) GetRMS() (RMS, error) {
+	return c.GetRMSCtx(context.Background())
+}
+
+// GetRMSCtx is GetRMS with a context for the request.
+func (c *Client) GetRMSCtx(ctx context.Context) (RMS, error) {
 	var rms RMS
-	err := c.doEnvelope(http.MethodGet, URIRMS, nil, nil, &rms, true)
+	err := c.doEnvelope(ctx, http.MethodGet, URIRMS, nil, nil, &rms, true)
 	return rms, err
 }
@@ -1,6 +1,7 @@
 package smartapigo
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -11,8 +12,26 @@ func (ts *TestSuite) TestGetRMS(t *testing.T) {
 		t.Errorf("Error while fetching RMS. %v", err)
 	}
 
-	if rms.Net == "" {
+	if rms.Net != 9999999999999 || rms.Raw["net"] != "9999999999999" {
 		t.Errorf("Error while fetching Net from RMS. %v", err)
 	}
 
 }
+
+func TestRMSUnmarshal(t *testing.T) {
+	var rms RMS
+	err := json.Unmarshal([]byte(`{"net":"1523.75","availablecash":2000.5,"collateral":null,"utiliseddebits":"","m2mrealized":"-12.5"}`), &rms)
+	if err != nil {
+		t.Fatalf("Error while parsing RMS. %v", err)
+	}
+	if rms.Net != 1523.75 || rms.AvailableCash != 2000.5 || rms.Collateral != 0 || rms.UtilisedDebits != 0 || rms.M2MRealized != -12.5 {
+		t.Errorf("Unexpected RMS %+v", rms)
+	}
+	if rms.Raw["net"] != "1523.75" || rms.Raw["availablecash"] != "2000.5" {
+		t.Errorf("Unexpected raw values %v", rms.Raw)
+	}
+
+	if err := json.Unmarshal([]byte(`{"net":"n/a"}`), &rms); err == nil {
+		t.Errorf("Expected an error for an invalid balance")
+	}
+}
@@ -0,0 +1,267 @@
+package smartapigo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrGTTRuleTriggered is returned when modifying or cancelling a GTT rule that has already triggered.
+	ErrGTTRuleTriggered = errors.New("gtt rule already triggered")
+	// ErrGTTRuleExpired is returned when modifying or cancelling a GTT rule that has expired.
+	ErrGTTRuleExpired = errors.New("gtt rule expired")
+)
+
+// GTTStatus is the status of a GTT rule.
+type GTTStatus string
+
+const (
+	GTTStatusNew            GTTStatus = "NEW"
+	GTTStatusCancelled      GTTStatus = "CANCELLED"
+	GTTStatusActive         GTTStatus = "ACTIVE"
+	GTTStatusSentToExchange GTTStatus = "SENTTOEXCHANGE"
+	GTTStatusForAll         GTTStatus = "FORALL"
+)
+
+// GTTParams represents parameters for creating a GTT rule.
+type GTTParams struct {
+	TradingSymbol   string  `json:"tradingsymbol"`
+	SymbolToken     string  `json:"symboltoken"`
+	Exchange        string  `json:"exchange"`
+	TransactionType string  `json:"transactiontype"`
+	ProductType     string  `json:"producttype"`
+	Price           float64 `json:"price"`
+	Qty             int     `json:"qty"`
+	TriggerPrice    float64 `json:"triggerprice"`
+	DisclosedQty    int     `json:"disclosedqty"`
+	// TimePeriod is the validity of the rule in days. The rule is created with
+	// the default validity of the API when it is 0.
+	TimePeriod int `json:"timeperiod,omitempty"`
+}
+
+// ModifyGTTParams represents parameters for modifying a GTT rule.
+// It only holds the fields of a rule that can be modified.
+type ModifyGTTParams struct {
+	ID           GTTRuleID `json:"id"`
+	SymbolToken  string    `json:"symboltoken"`
+	Exchange     string    `json:"exchange"`
+	Price        float64   `json:"price"`
+	Qty          int       `json:"qty"`
+	TriggerPrice float64   `json:"triggerprice"`
+	DisclosedQty int       `json:"disclosedqty"`
+	// TimePeriod is the validity of the rule in days, left out of the request
+	// when it is 0.
+	TimePeriod int `json:"timeperiod,omitempty"`
+}
+
+// CancelGTTParams represents parameters for cancelling a GTT rule.
+type CancelGTTParams struct {
+	ID          GTTRuleID `json:"id"`
+	SymbolToken string    `json:"symboltoken"`
+	Exchange    string    `json:"exchange"`
+}
+
+// GTTRuleID is the id of a GTT rule. The API returns it either as a number or a string.
+type GTTRuleID string
+
+// UnmarshalJSON accepts both numeric and string rule ids.
+func (id *GTTRuleID) UnmarshalJSON(data []byte) error {
+	*id = GTTRuleID(bytes.Trim(data, `"`))
+	if *id == "null" {
+		*id = ""
+	}
+	return nil
+}
+
+// GTTRuleResponse represents the response of a GTT rule operation.
+type GTTRuleResponse struct {
+	ID GTTRuleID `json:"id"`
+}
+
+// GTTRule represents an individual GTT rule.
+type GTTRule struct {
+	ID              GTTRuleID `json:"id"`
+	Status          GTTStatus `json:"status"`
+	CreatedDate     Time      `json:"createddate"`
+	UpdatedDate     Time      `json:"updateddate"`
+	ExpiryDate      Time      `json:"expirydate"`
+	ClientID        string    `json:"clientid"`
+	TradingSymbol   string    `json:"tradingsymbol"`
+	SymbolToken     string    `json:"symboltoken"`
+	Exchange        string    `json:"exchange"`
+	ProductType     string    `json:"producttype"`
+	TransactionType string    `json:"transactiontype"`
+	Price           float64   `json:"price"`
+	Qty             int       `json:"qty"`
+	TriggerPrice    float64   `json:"triggerprice"`
+	DisclosedQty    int       `json:"disclosedqty"`
+}
+
+// GTTRules is a list of GTT rules.
+type GTTRules []GTTRule
+
+// CreateGTTRule creates a GTT rule.
+func (c *Client) CreateGTTRule(gttParams GTTParams) (GTTRuleResponse, error) {
+	return c.CreateGTTRuleCtx(context.Background(), gttParams)
+}
+
+// CreateGTTRuleCtx is CreateGTTRule with a context for the request.
+func (c *Client) CreateGTTRuleCtx(ctx context.Context, gttParams GTTParams) (GTTRuleResponse, error) {
+	var (
+		gttResponse GTTRuleResponse
+		params      map[string]interface{}
+		err         error
+	)
+
+	params = structToMap(gttParams, "json")
+
+	err = c.doEnvelope(ctx, http.MethodPost, URICreateGTTRule, params, nil, &gttResponse, true)
+	return gttResponse, err
+}
+
+// ModifyGTTRule modifies the price, quantity, trigger price, disclosed quantity
+// and time period of a GTT rule.
+func (c *Client) ModifyGTTRule(modifyGTTParams ModifyGTTParams) (GTTRuleResponse, error) {
+	return c.ModifyGTTRuleCtx(context.Background(), modifyGTTParams)
+}
+
+// ModifyGTTRuleCtx is ModifyGTTRule with a context for the request.
+func (c *Client) ModifyGTTRuleCtx(ctx context.Context, modifyGTTParams ModifyGTTParams) (GTTRuleResponse, error) {
+	var (
+		gttResponse GTTRuleResponse
+		params      map[string]interface{}
+		err         error
+	)
+
+	if err = validateGTTRule(modifyGTTParams.ID, modifyGTTParams.SymbolToken, modifyGTTParams.Exchange); err != nil {
+		return gttResponse, err
+	}
+	if modifyGTTParams.Qty <= 0 || modifyGTTParams.TriggerPrice <= 0 {
+		return gttResponse, NewError(InputError, "qty and trigger price of a gtt rule must be positive", nil)
+	}
+
+	params = structToMap(modifyGTTParams, "json")
+
+	err = c.doEnvelope(ctx, http.MethodPost, URIModifyGTTRule, params, nil, &gttResponse, true)
+	return gttResponse, gttError(err)
+}
+
+// CancelGTTRule cancels a GTT rule.
+func (c *Client) CancelGTTRule(cancelGTTParams CancelGTTParams) (GTTRuleResponse, error) {
+	return c.CancelGTTRuleCtx(context.Background(), cancelGTTParams)
+}
+
+// CancelGTTRuleCtx is CancelGTTRule with a context for the request.
+func (c *Client) CancelGTTRuleCtx(ctx context.Context, cancelGTTParams CancelGTTParams) (GTTRuleResponse, error) {
+	var (
+		gttResponse GTTRuleResponse
+		params      map[string]interface{}
+		err         error
+	)
+
+	if err = validateGTTRule(cancelGTTParams.ID, cancelGTTParams.SymbolToken, cancelGTTParams.Exchange); err != nil {
+		return gttResponse, err
+	}
+
+	params = structToMap(cancelGTTParams, "json")
+
+	err = c.doEnvelope(ctx, http.MethodPost, URICancelGTTRule, params, nil, &gttResponse, true)
+	return gttResponse, gttError(err)
+}
+
+// validateGTTRule checks the fields identifying an existing GTT rule.
+func validateGTTRule(id GTTRuleID, symbolToken, exchange string) error {
+	if id == "" || symbolToken == "" || exchange == "" {
+		return NewError(InputError, "id, symbol token and exchange of a gtt rule are required", nil)
+	}
+	return nil
+}
+
+// gttInactiveMessages maps the messages of API errors for rules that are no longer
+// active to the sentinel errors they match. The API documents no error codes for them,
+// so messages are matched exactly rather than by words such as "trigger" which
+// also appear in errors for invalid parameters.
+var gttInactiveMessages = map[string]error{
+	"gtt rule already triggered": ErrGTTRuleTriggered,
+	"rule already triggered":     ErrGTTRuleTriggered,
+	"gtt rule has expired":       ErrGTTRuleExpired,
+	"rule has expired":           ErrGTTRuleExpired,
+}
+
+// gttError maps API errors for rules that are no longer active to ErrGTTRuleTriggered
+// and ErrGTTRuleExpired. The API error stays available through errors.As.
+func gttError(err error) error {
+	var apiErr Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	message := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(apiErr.Message)), ".")
+	if sentinel, ok := gttInactiveMessages[message]; ok {
+		return fmt.Errorf("%w: %w", sentinel, err)
+	}
+	return err
+}
+
+// GetGTTRuleDetails gets the details of a GTT rule.
+func (c *Client) GetGTTRuleDetails(ruleID GTTRuleID) (GTTRule, error) {
+	return c.GetGTTRuleDetailsCtx(context.Background(), ruleID)
+}
+
+// GetGTTRuleDetailsCtx is GetGTTRuleDetails with a context for the request.
+func (c *Client) GetGTTRuleDetailsCtx(ctx context.Context, ruleID GTTRuleID) (GTTRule, error) {
+	var gttRule GTTRule
+	params := map[string]interface{}{}
+	params["id"] = ruleID
+	err := c.doEnvelope(ctx, http.MethodPost, URIGTTRuleDetails, params, nil, &gttRule, true)
+	if gttRule.ID == "" {
+		gttRule.ID = ruleID
+	}
+	return gttRule, err
+}
+
+// GetGTTRuleList gets a page of the GTT rules in any of the statuses.
+// Pages start at 1 and hold up to count rules.
+func (c *Client) GetGTTRuleList(statuses []GTTStatus, page int, count int) (GTTRules, error) {
+	return c.GetGTTRuleListCtx(context.Background(), statuses, page, count)
+}
+
+// GetGTTRuleListCtx is GetGTTRuleList with a context for the request.
+func (c *Client) GetGTTRuleListCtx(ctx context.Context, statuses []GTTStatus, page int, count int) (GTTRules, error) {
+	var gttRules GTTRules
+	if page < 1 || count < 1 {
+		return gttRules, NewError(InputError, "page and count of a gtt rule list must be positive", nil)
+	}
+
+	params := map[string]interface{}{}
+	params["status"] = statuses
+	params["page"] = page
+	params["count"] = count
+	err := c.doEnvelope(ctx, http.MethodPost, URIGTTRuleList, params, nil, &gttRules, true)
+	return gttRules, err
+}
+
+// GetAllGTTRules gets the GTT rules in any of the statuses by fetching pages of
+// pageSize rules until a page is not full.
+func (c *Client) GetAllGTTRules(statuses []GTTStatus, pageSize int) (GTTRules, error) {
+	return c.GetAllGTTRulesCtx(context.Background(), statuses, pageSize)
+}
+
+// GetAllGTTRulesCtx is GetAllGTTRules with a context for the request.
+func (c *Client) GetAllGTTRulesCtx(ctx context.Context, statuses []GTTStatus, pageSize int) (GTTRules, error) {
+	var all GTTRules
+	for page := 1; ; page++ {
+		gttRules, err := c.GetGTTRuleListCtx(ctx, statuses, page, pageSize)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, gttRules...)
+		if len(gttRules) < pageSize {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package smartapigo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GTTOCOParams represents parameters for creating an OCO (one-cancels-other)
+// pair of GTT rules, exiting a position at a target or at a stop loss,
+// whichever triggers first.
+type GTTOCOParams struct {
+	TradingSymbol        string
+	SymbolToken          string
+	Exchange             string
+	TransactionType      string
+	ProductType          string
+	Qty                  int
+	TargetPrice          float64
+	TargetTriggerPrice   float64
+	StopLossPrice        float64
+	StopLossTriggerPrice float64
+	TimePeriod           int
+}
+
+// GTTOCO is an OCO pair of GTT rules.
+type GTTOCO struct {
+	TargetID    GTTRuleID
+	StopLossID  GTTRuleID
+	SymbolToken string
+	Exchange    string
+}
+
+// validate checks the params, with the target above the stop loss when selling
+// to exit and below it when buying to exit.
+func (params GTTOCOParams) validate() error {
+	if params.TradingSymbol == "" || params.SymbolToken == "" || params.Exchange == "" {
+		return NewError(InputError, "trading symbol, symbol token and exchange of a gtt oco are required", nil)
+	}
+	if params.Qty <= 0 || params.TargetTriggerPrice <= 0 || params.StopLossTriggerPrice <= 0 {
+		return NewError(InputError, "qty and trigger prices of a gtt oco must be positive", nil)
+	}
+	switch TransactionType(strings.ToUpper(params.TransactionType)) {
+	case TransactionSell:
+		if params.TargetTriggerPrice <= params.StopLossTriggerPrice {
+			return NewError(InputError, "target of a sell gtt oco must trigger above its stop loss", nil)
+		}
+	case TransactionBuy:
+		if params.TargetTriggerPrice >= params.StopLossTriggerPrice {
+			return NewError(InputError, "target of a buy gtt oco must trigger below its stop loss", nil)
+		}
+	default:
+		return NewError(InputError, "invalid transaction type "+strconv.Quote(params.TransactionType), nil)
+	}
+	return nil
+}
+
+// legs returns the params of the target and the stop loss rules.
+func (params GTTOCOParams) legs() (GTTParams, GTTParams) {
+	target := GTTParams{
+		TradingSymbol:   params.TradingSymbol,
+		SymbolToken:     params.SymbolToken,
+		Exchange:        params.Exchange,
+		TransactionType: params.TransactionType,
+		ProductType:     params.ProductType,
+		Price:           params.TargetPrice,
+		Qty:             params.Qty,
+		TriggerPrice:    params.TargetTriggerPrice,
+		TimePeriod:      params.TimePeriod,
+	}
+	stopLoss := target
+	stopLoss.Price = params.StopLossPrice
+	stopLoss.TriggerPrice = params.StopLossTriggerPrice
+	return target, stopLoss
+}
+
+// modifyParams returns the params modifying the rule to the leg.
+func (leg GTTParams) modifyParams(id GTTRuleID) ModifyGTTParams {
+	return ModifyGTTParams{
+		ID:           id,
+		SymbolToken:  leg.SymbolToken,
+		Exchange:     leg.Exchange,
+		Price:        leg.Price,
+		Qty:          leg.Qty,
+		TriggerPrice: leg.TriggerPrice,
+		DisclosedQty: leg.DisclosedQty,
+		TimePeriod:   leg.TimePeriod,
+	}
+}
+
+// NewGTTOCOParams returns the params of a GTT OCO exiting the position of an
+// executed entry order at the target and stop loss points from its average
+// price. The rules are limit orders at their trigger prices.
+func NewGTTOCOParams(entry Order, targetPoints, stopLossPoints float64, timePeriod int) (GTTOCOParams, error) {
+	if !strings.EqualFold(entry.OrderStatus, "complete") {
+		return GTTOCOParams{}, NewError(InputError, "entry order "+entry.OrderID+" is not executed", nil)
+	}
+	averagePrice := number(entry.AveragePrice)
+	qty, _ := strconv.Atoi(entry.FilledShares)
+	if qty == 0 {
+		qty, _ = strconv.Atoi(entry.Quantity)
+	}
+	if averagePrice <= 0 || qty <= 0 || targetPoints <= 0 || stopLossPoints <= 0 {
+		return GTTOCOParams{}, NewError(InputError, "average price and quantity of the entry order and the target and stop loss points must be positive", nil)
+	}
+
+	params := GTTOCOParams{
+		TradingSymbol: entry.TradingSymbol,
+		SymbolToken:   entry.SymbolToken,
+		Exchange:      entry.Exchange,
+		ProductType:   entry.ProductType,
+		Qty:           qty,
+		TimePeriod:    timePeriod,
+	}
+	switch TransactionType(strings.ToUpper(entry.TransactionType)) {
+	case TransactionBuy:
+		params.TransactionType = string(TransactionSell)
+		params.TargetTriggerPrice = roundPrice(averagePrice + targetPoints)
+		params.StopLossTriggerPrice = roundPrice(averagePrice - stopLossPoints)
+	case TransactionSell:
+		params.TransactionType = string(TransactionBuy)
+		params.TargetTriggerPrice = roundPrice(averagePrice - targetPoints)
+		params.StopLossTriggerPrice = roundPrice(averagePrice + stopLossPoints)
+	default:
+		return GTTOCOParams{}, NewError(InputError, "invalid transaction type "+strconv.Quote(entry.TransactionType), nil)
+	}
+	params.TargetPrice, params.StopLossPrice = params.TargetTriggerPrice, params.StopLossTriggerPrice
+	return params, params.validate()
+}
+
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
+
+// CreateGTTOCO creates the target and the stop loss rules of a GTT OCO. When the
+// stop loss rule cannot be created the target rule is cancelled.
+func (c *Client) CreateGTTOCO(params GTTOCOParams) (GTTOCO, error) {
+	return c.CreateGTTOCOCtx(context.Background(), params)
+}
+
+// CreateGTTOCOCtx is CreateGTTOCO with a context for the requests.
+func (c *Client) CreateGTTOCOCtx(ctx context.Context, params GTTOCOParams) (GTTOCO, error) {
+	oco := GTTOCO{SymbolToken: params.SymbolToken, Exchange: params.Exchange}
+	if err := params.validate(); err != nil {
+		return oco, err
+	}
+
+	target, stopLoss := params.legs()
+	response, err := c.CreateGTTRuleCtx(ctx, target)
+	if err != nil {
+		return oco, err
+	}
+	oco.TargetID = response.ID
+
+	if response, err = c.CreateGTTRuleCtx(ctx, stopLoss); err != nil {
+		_, cancelErr := c.CancelGTTRuleCtx(ctx, CancelGTTParams{ID: oco.TargetID, SymbolToken: oco.SymbolToken, Exchange: oco.Exchange})
+		return GTTOCO{SymbolToken: oco.SymbolToken, Exchange: oco.Exchange}, errors.Join(err, cancelErr)
+	}
+	oco.StopLossID = response.ID
+	return oco, nil
+}
+
+// ModifyGTTOCO modifies the price, quantity, trigger price and time period of the
+// target and the stop loss rules of a GTT OCO.
+func (c *Client) ModifyGTTOCO(oco GTTOCO, params GTTOCOParams) error {
+	return c.ModifyGTTOCOCtx(context.Background(), oco, params)
+}
+
+// ModifyGTTOCOCtx is ModifyGTTOCO with a context for the requests.
+func (c *Client) ModifyGTTOCOCtx(ctx context.Context, oco GTTOCO, params GTTOCOParams) error {
+	params.SymbolToken, params.Exchange = oco.SymbolToken, oco.Exchange
+	if err := params.validate(); err != nil {
+		return err
+	}
+
+	target, stopLoss := params.legs()
+	if _, err := c.ModifyGTTRuleCtx(ctx, target.modifyParams(oco.TargetID)); err != nil {
+		return err
+	}
+	_, err := c.ModifyGTTRuleCtx(ctx, stopLoss.modifyParams(oco.StopLossID))
+	return err
+}
+
+// CancelGTTOCO cancels the target and the stop loss rules of a GTT OCO, and
+// returns the errors of both cancellations.
+func (c *Client) CancelGTTOCO(oco GTTOCO) error {
+	return c.CancelGTTOCOCtx(context.Background(), oco)
+}
+
+// CancelGTTOCOCtx is CancelGTTOCO with a context for the requests.
+func (c *Client) CancelGTTOCOCtx(ctx context.Context, oco GTTOCO) error {
+	_, targetErr := c.CancelGTTRuleCtx(ctx, CancelGTTParams{ID: oco.TargetID, SymbolToken: oco.SymbolToken, Exchange: oco.Exchange})
+	_, stopLossErr := c.CancelGTTRuleCtx(ctx, CancelGTTParams{ID: oco.StopLossID, SymbolToken: oco.SymbolToken, Exchange: oco.Exchange})
+	return errors.Join(targetErr, stopLossErr)
+}
+
+// SyncGTTOCO cancels the other rule of a GTT OCO once one of its rules has
+// triggered, and returns the id of the triggered rule, empty while neither has.
+// It is meant to be called periodically or on order updates.
+func (c *Client) SyncGTTOCO(oco GTTOCO) (GTTRuleID, error) {
+	return c.SyncGTTOCOCtx(context.Background(), oco)
+}
+
+// SyncGTTOCOCtx is SyncGTTOCO with a context for the requests.
+func (c *Client) SyncGTTOCOCtx(ctx context.Context, oco GTTOCO) (GTTRuleID, error) {
+	target, err := c.GetGTTRuleDetailsCtx(ctx, oco.TargetID)
+	if err != nil {
+		return "", err
+	}
+	stopLoss, err := c.GetGTTRuleDetailsCtx(ctx, oco.StopLossID)
+	if err != nil {
+		return "", err
+	}
+
+	triggered, other := target, stopLoss
+	switch {
+	case target.Status == GTTStatusSentToExchange:
+	case stopLoss.Status == GTTStatusSentToExchange:
+		triggered, other = stopLoss, target
+	default:
+		return "", nil
+	}
+	if other.Status == GTTStatusNew || other.Status == GTTStatusActive {
+		_, err = c.CancelGTTRuleCtx(ctx, CancelGTTParams{ID: other.ID, SymbolToken: oco.SymbolToken, Exchange: oco.Exchange})
+	}
+	return triggered.ID, err
+}
@@ -0,0 +1,94 @@
+package smartapigo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// newGTTClient returns a client creating rules with ids 1, 2, ... failing for
+// the trigger price fail, answering rule details with the statuses and
+// recording the cancelled rules.
+func newGTTClient(fail float64, statuses map[string]GTTStatus) (*Client, *[]string) {
+	client, transport := newMockClient()
+	created, cancelled := 0, []string{}
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URICreateGTTRule, func(req *http.Request) (*http.Response, error) {
+		var params GTTParams
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		if params.TriggerPrice == fail {
+			return httpmock.NewStringResponse(200, `{"status":false,"message":"Invalid trigger price","errorcode":"AB9001","data":null}`), nil
+		}
+		created++
+		return httpmock.NewStringResponse(200, fmt.Sprintf(`{"status":true,"message":"SUCCESS","errorcode":"","data":{"id":%d}}`, created)), nil
+	})
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIGTTRuleDetails, func(req *http.Request) (*http.Response, error) {
+		var params map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"id":"`+params["id"]+`","status":"`+string(statuses[params["id"]])+`"}}`), nil
+	})
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URICancelGTTRule, func(req *http.Request) (*http.Response, error) {
+		var params map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, params["id"])
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"id":"`+params["id"]+`"}}`), nil
+	})
+	return client, &cancelled
+}
+
+func TestNewGTTOCOParams(t *testing.T) {
+	entry := Order{TradingSymbol: "SBIN-EQ", SymbolToken: "3045", Exchange: "NSE", ProductType: "DELIVERY", TransactionType: "BUY",
+		OrderStatus: "complete", Quantity: "10", FilledShares: "10", AveragePrice: "500.1", OrderID: "1"}
+	params, err := NewGTTOCOParams(entry, 10, 5, 365)
+	expected := GTTOCOParams{TradingSymbol: "SBIN-EQ", SymbolToken: "3045", Exchange: "NSE", TransactionType: "SELL", ProductType: "DELIVERY", Qty: 10,
+		TargetPrice: 510.1, TargetTriggerPrice: 510.1, StopLossPrice: 495.1, StopLossTriggerPrice: 495.1, TimePeriod: 365}
+	if err != nil || params != expected {
+		t.Errorf("Unexpected params %+v. %v", params, err)
+	}
+
+	entry.OrderStatus = "open"
+	if _, err := NewGTTOCOParams(entry, 10, 5, 365); err == nil {
+		t.Errorf("Expected an error for an entry order not executed")
+	}
+}
+
+func TestGTTOCO(t *testing.T) {
+	client, cancelled := newGTTClient(0, map[string]GTTStatus{"1": GTTStatusNew, "2": GTTStatusSentToExchange})
+	params := GTTOCOParams{TradingSymbol: "SBIN-EQ", SymbolToken: "3045", Exchange: "NSE", TransactionType: "SELL", ProductType: "DELIVERY", Qty: 10,
+		TargetPrice: 510, TargetTriggerPrice: 510, StopLossPrice: 495, StopLossTriggerPrice: 495, TimePeriod: 365}
+
+	invalid := params
+	invalid.TargetTriggerPrice = 490
+	if _, err := client.CreateGTTOCO(invalid); err == nil {
+		t.Errorf("Expected an error for a target below the stop loss")
+	}
+
+	oco, err := client.CreateGTTOCO(params)
+	if err != nil || oco != (GTTOCO{TargetID: "1", StopLossID: "2", SymbolToken: "3045", Exchange: "NSE"}) {
+		t.Fatalf("Unexpected oco %+v. %v", oco, err)
+	}
+
+	triggered, err := client.SyncGTTOCO(oco)
+	if err != nil || triggered != "2" || len(*cancelled) != 1 || (*cancelled)[0] != "1" {
+		t.Errorf("Expected the target to be cancelled once the stop loss triggered, got %q %v. %v", triggered, *cancelled, err)
+	}
+}
+
+func TestCreateGTTOCORollback(t *testing.T) {
+	client, cancelled := newGTTClient(495, nil)
+	params := GTTOCOParams{TradingSymbol: "SBIN-EQ", SymbolToken: "3045", Exchange: "NSE", TransactionType: "SELL", ProductType: "DELIVERY", Qty: 10,
+		TargetPrice: 510, TargetTriggerPrice: 510, StopLossPrice: 495, StopLossTriggerPrice: 495, TimePeriod: 365}
+
+	oco, err := client.CreateGTTOCO(params)
+	if err == nil || oco.TargetID != "" || len(*cancelled) != 1 || (*cancelled)[0] != "1" {
+		t.Errorf("Expected the target to be cancelled when the stop loss fails, got %+v %v. %v", oco, *cancelled, err)
+	}
+}
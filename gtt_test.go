@@ -0,0 +1,160 @@
+package smartapigo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func (ts *TestSuite) TestCreateGTTRule(t *testing.T) {
+	t.Parallel()
+	params := GTTParams{
+		TradingSymbol:   "SBIN-EQ",
+		SymbolToken:     "3045",
+		Exchange:        "NSE",
+		TransactionType: "BUY",
+		ProductType:     "DELIVERY",
+		Price:           195,
+		Qty:             1,
+		TriggerPrice:    196,
+		DisclosedQty:    1,
+		TimePeriod:      365,
+	}
+	gttResponse, err := ts.TestConnect.CreateGTTRule(params)
+	if err != nil {
+		t.Errorf("Error while creating GTT rule. %v", err)
+	}
+	if gttResponse.ID != "1000014" {
+		t.Errorf("Unexpected GTT rule id %q", gttResponse.ID)
+	}
+}
+
+func TestGTTRuleID(t *testing.T) {
+	var response GTTRuleResponse
+	for _, data := range []string{`{"id":1000014}`, `{"id":"1000014"}`} {
+		if err := json.Unmarshal([]byte(data), &response); err != nil || response.ID != "1000014" {
+			t.Errorf("Unexpected rule id %q for %s. %v", response.ID, data, err)
+		}
+	}
+}
+
+func (ts *TestSuite) TestModifyGTTRule(t *testing.T) {
+	t.Parallel()
+	params := ModifyGTTParams{
+		ID:           "1000014",
+		SymbolToken:  "3045",
+		Exchange:     "NSE",
+		Price:        195,
+		Qty:          1,
+		TriggerPrice: 196,
+		DisclosedQty: 1,
+		TimePeriod:   365,
+	}
+	gttResponse, err := ts.TestConnect.ModifyGTTRule(params)
+	if err != nil {
+		t.Errorf("Error while modifying GTT rule. %v", err)
+	}
+	if gttResponse.ID != "1000014" {
+		t.Errorf("Unexpected GTT rule id %q", gttResponse.ID)
+	}
+
+	if _, err := ts.TestConnect.ModifyGTTRule(ModifyGTTParams{SymbolToken: "3045", Exchange: "NSE"}); err == nil {
+		t.Errorf("Expected an error modifying a GTT rule without id")
+	}
+}
+
+func (ts *TestSuite) TestCancelGTTRule(t *testing.T) {
+	t.Parallel()
+	params := CancelGTTParams{
+		ID:          "1000014",
+		SymbolToken: "3045",
+		Exchange:    "NSE",
+	}
+	gttResponse, err := ts.TestConnect.CancelGTTRule(params)
+	if err != nil {
+		t.Errorf("Error while cancelling GTT rule. %v", err)
+	}
+	if gttResponse.ID != "1000014" {
+		t.Errorf("Unexpected GTT rule id %q", gttResponse.ID)
+	}
+}
+
+func TestGTTError(t *testing.T) {
+	cases := []struct {
+		message string
+		want    error
+	}{
+		{"GTT rule already triggered", ErrGTTRuleTriggered},
+		{"Rule has expired.", ErrGTTRuleExpired},
+	}
+	for _, c := range cases {
+		err := gttError(NewError("AB9999", c.message, nil))
+		if !errors.Is(err, c.want) {
+			t.Errorf("Expected %v for %q, got %v", c.want, c.message, err)
+		}
+		var apiErr Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "AB9999" {
+			t.Errorf("Expected the API error to be kept for %q, got %v", c.message, err)
+		}
+	}
+
+	for _, message := range []string{"Invalid symbol", "Invalid trigger price", "Trigger price should be less than LTP", "Invalid expiry date"} {
+		other := NewError("AB1000", message, nil)
+		if err := gttError(other); err != other {
+			t.Errorf("Unexpected error mapping of %q to %v", message, err)
+		}
+	}
+}
+
+func (ts *TestSuite) TestGetGTTRuleDetails(t *testing.T) {
+	t.Parallel()
+	gttRule, err := ts.TestConnect.GetGTTRuleDetails("1000014")
+	if err != nil {
+		t.Errorf("Error while fetching GTT rule details. %v", err)
+	}
+	if gttRule.ID != "1000014" || gttRule.Status != GTTStatusNew {
+		t.Errorf("Unexpected GTT rule %+v", gttRule)
+	}
+	if !gttRule.CreatedDate.Equal(time.Date(2020, 11, 16, 14, 19, 51, 0, time.UTC)) {
+		t.Errorf("Unexpected GTT rule created date %v", gttRule.CreatedDate)
+	}
+}
+
+func (ts *TestSuite) TestGetGTTRuleList(t *testing.T) {
+	t.Parallel()
+	statuses := []GTTStatus{GTTStatusNew, GTTStatusCancelled}
+	gttRules, err := ts.TestConnect.GetGTTRuleList(statuses, 1, 10)
+	if err != nil {
+		t.Errorf("Error while fetching GTT rule list. %v", err)
+	}
+	if len(gttRules) != 2 || gttRules[1].ID != "1000015" || gttRules[1].Status != GTTStatusCancelled {
+		t.Errorf("Unexpected GTT rules %+v", gttRules)
+	}
+
+	if _, err := ts.TestConnect.GetGTTRuleList(statuses, 0, 10); err == nil {
+		t.Errorf("Expected an error fetching page 0")
+	}
+
+	all, err := ts.TestConnect.GetAllGTTRules(statuses, 10)
+	if err != nil || len(all) != 2 {
+		t.Errorf("Unexpected GTT rules %+v. %v", all, err)
+	}
+}
+
+func TestTimeUnmarshal(t *testing.T) {
+	for _, data := range []string{`"2020-11-16T14:19:51Z"`, `"2020-11-16 14:19:51"`, `"16-Nov-2020 14:19:51"`} {
+		var ts Time
+		if err := json.Unmarshal([]byte(data), &ts); err != nil {
+			t.Errorf("Error parsing %s. %v", data, err)
+		}
+		if !ts.Equal(time.Date(2020, 11, 16, 14, 19, 51, 0, time.UTC)) {
+			t.Errorf("Unexpected time %v for %s", ts, data)
+		}
+	}
+
+	var ts Time
+	if err := json.Unmarshal([]byte(`""`), &ts); err != nil || !ts.IsZero() {
+		t.Errorf("Expected zero time for an empty timestamp, got %v. %v", ts, err)
+	}
+}
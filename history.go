@@ -1,6 +1,7 @@
 package smartapigo
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -123,6 +124,11 @@ func (h HistoryResponse) Parse() []HistoryDatum {
 
 // GetCandleData gets history of the specified symbol between a defined time-range
 func (c *Client) GetCandleData(params *HistoryParams) ([]HistoryDatum, error) {
+	return c.GetCandleDataCtx(context.Background(), params)
+}
+
+// GetCandleDataCtx is GetCandleData with a context for the request.
+func (c *Client) GetCandleDataCtx(ctx context.Context, params *HistoryParams) ([]HistoryDatum, error) {
 	var candleData HistoryResponse
 	if !params.ValidDates() {
 		return []HistoryDatum{}, fmt.Errorf("history.GetCandleData: fromdate can not be greater than todate")
@@ -132,7 +138,7 @@ func (c *Client) GetCandleData(params *HistoryParams) ([]HistoryDatum, error) {
 		return []HistoryDatum{}, fmt.Errorf("history.GetCandleData: interval days can not be %d when interval is %s. Please see %s for details", params.IntervalDays(), params.Interval, URLHistoryDocumentation)
 	}
 
-	err := c.doEnvelope(http.MethodPost, URIGetCandleData, params.GetParams(), nil, &candleData, true)
+	err := c.doEnvelope(ctx, http.MethodPost, URIGetCandleData, params.GetParams(), nil, &candleData, true)
 
 	return candleData.Parse(), err
 }
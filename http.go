@@ -2,27 +2,69 @@ package smartapigo
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
 // HTTPClient represents an HTTP client.
 type HTTPClient interface {
 	Do(method, rURL string, params map[string]interface{}, headers http.Header) (HTTPResponse, error)
+	DoContext(ctx context.Context, method, rURL string, params map[string]interface{}, headers http.Header) (HTTPResponse, error)
 	DoEnvelope(method, url string, params map[string]interface{}, headers http.Header, obj interface{}) error
+	DoEnvelopeContext(ctx context.Context, method, url string, params map[string]interface{}, headers http.Header, obj interface{}) error
 	GetClient() *httpClient
 }
 
 // httpClient is the default implementation of HTTPClient.
 type httpClient struct {
-	client *http.Client
-	hLog   *log.Logger
-	debug  bool
+	client      *http.Client
+	hLog        *log.Logger
+	debug       bool
+	middlewares []Middleware
+}
+
+// TransportOptions tunes the connection pooling and timeouts of the http client.
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept open to the API.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open.
+	IdleConnTimeout time.Duration
+	// TLSHandshakeTimeout is the timeout of the TLS handshake of a connection.
+	TLSHandshakeTimeout time.Duration
+	// RequestTimeout is the timeout of a request including reading its response.
+	RequestTimeout time.Duration
+}
+
+// DefaultTransportOptions are the transport options of the default http client,
+// keeping enough idle connections open to poll the API at high frequency.
+var DefaultTransportOptions = TransportOptions{
+	MaxIdleConnsPerHost: 10,
+	IdleConnTimeout:     90 * time.Second,
+	TLSHandshakeTimeout: 10 * time.Second,
+	RequestTimeout:      requestTimeout,
+}
+
+// apply sets the non zero connection options on the transport.
+func (opts TransportOptions) apply(transport *http.Transport) {
+	if opts.MaxIdleConnsPerHost != 0 {
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+		if transport.MaxIdleConns != 0 && transport.MaxIdleConns < opts.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = opts.MaxIdleConnsPerHost
+		}
+	}
+	if opts.IdleConnTimeout != 0 {
+		transport.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.TLSHandshakeTimeout != 0 {
+		transport.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+	}
 }
 
 // HTTPResponse encompasses byte body  + the response of an HTTP request.
@@ -65,14 +107,20 @@ func NewHTTPClient(h *http.Client, hLog *log.Logger, debug bool) HTTPClient {
 
 // Do executes an HTTP request and returns the response.
 func (h *httpClient) Do(method, rURL string, params map[string]interface{}, headers http.Header) (HTTPResponse, error) {
+	return h.DoContext(context.Background(), method, rURL, params, headers)
+}
+
+// DoContext executes an HTTP request bound to ctx and returns the response.
+func (h *httpClient) DoContext(ctx context.Context, method, rURL string, params map[string]interface{}, headers http.Header) (HTTPResponse, error) {
 	var (
 		resp       = HTTPResponse{}
 		postParams io.Reader
+		jsonParams []byte
 		err        error
 	)
 
 	if method == http.MethodPost && params != nil {
-		jsonParams, err := json.Marshal(params)
+		jsonParams, err = json.Marshal(params)
 
 		if err != nil {
 			return resp, err
@@ -81,7 +129,7 @@ func (h *httpClient) Do(method, rURL string, params map[string]interface{}, head
 		postParams = bytes.NewBuffer(jsonParams)
 	}
 
-	req, err := http.NewRequest(method, rURL, postParams)
+	req, err := http.NewRequestWithContext(ctx, method, rURL, postParams)
 
 	if err != nil {
 		h.hLog.Printf("Request preparation failed: %v", err)
@@ -104,9 +152,14 @@ func (h *httpClient) Do(method, rURL string, params map[string]interface{}, head
 	//	req.URL.RawQuery = params.Encode()
 	//}
 
-	r, err := h.client.Do(req)
+	requestID := req.Header.Get(RequestIDHeader)
+	start := time.Now()
+	r, err := h.doer().Do(req)
 	if err != nil {
-		h.hLog.Printf("Request failed: %v", err)
+		h.hLog.Printf("Request %s failed: %v", requestID, err)
+		if h.debug {
+			h.hLog.Printf("%s %s -- failed in %v\n  request id: %s\n  headers: %v\n  request: %s", method, req.URL.String(), time.Since(start), requestID, redactHeaders(req.Header), redactBody(jsonParams))
+		}
 		return resp, err
 	}
 
@@ -120,8 +173,10 @@ func (h *httpClient) Do(method, rURL string, params map[string]interface{}, head
 
 	resp.Response = r
 	resp.Body = body
+	storeResponseStatus(ctx, r.StatusCode)
 	if h.debug {
-		h.hLog.Printf("%s %s -- %d %v", method, req.URL.RequestURI(), resp.Response.StatusCode, req.Header)
+		h.hLog.Printf("%s %s -- %d in %v\n  request id: %s\n  headers: %v\n  request: %s\n  response: %s", method, req.URL.String(), resp.Response.StatusCode,
+			time.Since(start), requestID, redactHeaders(req.Header), redactBody(jsonParams), redactBody(body))
 	}
 
 	return resp, nil
@@ -129,20 +184,28 @@ func (h *httpClient) Do(method, rURL string, params map[string]interface{}, head
 
 // DoEnvelope makes an HTTP request and parses the JSON response (fastglue envelop structure)
 func (h *httpClient) DoEnvelope(method, url string, params map[string]interface{}, headers http.Header, obj interface{}) error {
-	resp, err := h.Do(method, url, params, headers)
+	return h.DoEnvelopeContext(context.Background(), method, url, params, headers, obj)
+}
+
+// DoEnvelopeContext makes an HTTP request bound to ctx and parses the JSON response (fastglue envelop structure)
+func (h *httpClient) DoEnvelopeContext(ctx context.Context, method, url string, params map[string]interface{}, headers http.Header, obj interface{}) error {
+	resp, err := h.DoContext(ctx, method, url, params, headers)
 	if err != nil {
 		return err
 	}
+	storeRawResponse(ctx, resp.Body)
+	rateLimit := parseRateLimitInfo(resp.Response.Header, time.Now())
+	storeRateLimitInfo(ctx, rateLimit)
 
 	// Successful request, but error envelope.
 	if resp.Response.StatusCode >= http.StatusBadRequest {
 		var e envelope
 		if err := json.Unmarshal(resp.Body, &e); err != nil {
 			h.hLog.Printf("Error parsing JSON response: %s| %s\n", resp.Body, err.Error())
-			return err
+			e.Message = http.StatusText(resp.Response.StatusCode)
 		}
 
-		return NewError(e.ErrorCode, e.Message, e.Data)
+		return Error{Code: e.ErrorCode, Message: e.Message, Data: e.Data, HTTPStatus: resp.Response.StatusCode, RateLimit: rateLimit}
 	}
 
 	// We now unmarshal the body.
@@ -152,16 +215,73 @@ func (h *httpClient) DoEnvelope(method, url string, params map[string]interface{
 	jsonUnmarshalErr := json.Unmarshal(resp.Body, &envl)
 	if jsonUnmarshalErr != nil {
 		h.hLog.Printf("Error parsing JSON response: %s | %s\n", resp.Body, jsonUnmarshalErr.Error())
-		return err
+		return jsonUnmarshalErr
 	}
 
 	if !envl.Status {
-		return NewError(envl.ErrorCode, envl.Message, envl.Data)
+		return Error{Code: envl.ErrorCode, Message: envl.Message, Data: envl.Data, HTTPStatus: resp.Response.StatusCode, RateLimit: rateLimit}
 	}
 
 	return nil
 }
 
+// redactedKeys are the request and response fields holding secrets.
+var redactedKeys = map[string]bool{
+	"password":      true,
+	"totp":          true,
+	"jwttoken":      true,
+	"refreshtoken":  true,
+	"feedtoken":     true,
+	"pan":           true,
+	"authorization": true,
+	"x-privatekey":  true,
+}
+
+const redacted = "[REDACTED]"
+
+// redactHeaders returns a copy of headers with the secrets redacted.
+func redactHeaders(headers http.Header) http.Header {
+	out := headers.Clone()
+	for key := range out {
+		if redactedKeys[strings.ToLower(key)] {
+			out[key] = []string{redacted}
+		}
+	}
+	return out
+}
+
+// redactBody returns a JSON body with the secrets redacted.
+// Bodies which are not JSON are returned as is.
+func redactBody(body []byte) []byte {
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return body
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if redactedKeys[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
 // GetClient return's the underlying net/http client.
 func (h *httpClient) GetClient() *httpClient {
 	return h
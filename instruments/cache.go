@@ -0,0 +1,140 @@
+package instruments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultRefreshAt is the time of day, in IST, after which the instrument master of the previous day is stale.
+const defaultRefreshAt = 8*time.Hour + 30*time.Minute
+
+// exchangeLocation is the time zone of the Indian exchanges.
+var exchangeLocation = time.FixedZone("IST", 5*60*60+30*60)
+
+// Cache keeps the instrument master in a file and downloads it again only when stale.
+type Cache struct {
+	// Path is the file the instrument master is kept in.
+	Path string
+	// URL is the url of the instrument master, ScripMasterURL by default.
+	URL string
+	// Client downloads the instrument master, http.DefaultClient when nil.
+	Client *http.Client
+	// RefreshAt is the time of day in IST after which a master downloaded before
+	// it is stale, 08:30 by default. It is ignored when TTL is set.
+	RefreshAt time.Duration
+	// TTL is how long a downloaded master is fresh.
+	TTL time.Duration
+
+	now func() time.Time
+}
+
+// NewCache returns a cache of the instrument master in the file at path.
+func NewCache(path string) *Cache {
+	return &Cache{Path: path, URL: ScripMasterURL, RefreshAt: defaultRefreshAt, now: time.Now}
+}
+
+// Load returns the index of the cached instrument master, downloading the master
+// first when the cache is missing or stale. The download is conditional on the
+// master having changed since it was cached, and is streamed to the file, which
+// is then parsed, so that the master is not held in memory twice.
+// When the download of a stale master fails, the stale master is used.
+func (c *Cache) Load(ctx context.Context) (*Index, error) {
+	info, statErr := os.Stat(c.Path)
+	if statErr == nil && c.fresh(info.ModTime()) {
+		return c.parse()
+	}
+
+	var modTime time.Time
+	if statErr == nil {
+		modTime = info.ModTime()
+	}
+	if err := c.download(ctx, modTime); err != nil {
+		if statErr == nil {
+			return c.parse()
+		}
+		return nil, err
+	}
+	return c.parse()
+}
+
+// fresh reports whether a master cached at modTime is fresh.
+func (c *Cache) fresh(modTime time.Time) bool {
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	if c.TTL > 0 {
+		return now.Sub(modTime) < c.TTL
+	}
+
+	now = now.In(exchangeLocation)
+	refresh := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, exchangeLocation).Add(c.RefreshAt)
+	if now.Before(refresh) {
+		refresh = refresh.AddDate(0, 0, -1)
+	}
+	return !modTime.Before(refresh)
+}
+
+func (c *Cache) parse() (*Index, error) {
+	f, err := os.Open(c.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// download downloads the master into the cache file unless it did not change since modTime.
+func (c *Cache) download(ctx context.Context, modTime time.Time) error {
+	u := c.URL
+	if u == "" {
+		u = ScripMasterURL
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if !modTime.IsZero() {
+		req.Header.Set("If-Modified-Since", modTime.UTC().Format(http.TimeFormat))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		now := time.Now()
+		return os.Chtimes(c.Path, now, now)
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("instruments: downloading instrument master: %s", resp.Status)
+	}
+
+	// Write to a temporary file renamed over the cache so a failed download keeps the cache.
+	tmp, err := os.CreateTemp(filepath.Dir(c.Path), filepath.Base(c.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("instruments: downloading instrument master: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.Path)
+}
@@ -0,0 +1,87 @@
+package instruments
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheLoad(t *testing.T) {
+	var downloads, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") != "" {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		_, _ = w.Write([]byte(scripMaster))
+	}))
+	defer server.Close()
+
+	cache := NewCache(filepath.Join(t.TempDir(), "scrip_master.json"))
+	cache.URL = server.URL
+	cache.TTL = time.Hour
+
+	for i := 0; i < 2; i++ {
+		idx, err := cache.Load(context.Background())
+		if err != nil || idx.Len() != 13 {
+			t.Fatalf("Unexpected index %v. %v", idx, err)
+		}
+	}
+	if downloads != 1 || notModified != 0 {
+		t.Errorf("Expected a single download of a fresh master, got %d downloads and %d conditional requests", downloads, notModified)
+	}
+
+	// A stale master is requested conditionally.
+	cache.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if idx, err := cache.Load(context.Background()); err != nil || idx.Len() != 13 {
+		t.Fatalf("Unexpected index %v. %v", idx, err)
+	}
+	if downloads != 1 || notModified != 1 {
+		t.Errorf("Expected a conditional request of a stale master, got %d downloads and %d conditional requests", downloads, notModified)
+	}
+
+	// A stale master is used when the download fails.
+	server.Close()
+	if idx, err := cache.Load(context.Background()); err != nil || idx.Len() != 13 {
+		t.Errorf("Expected the stale master to be used, got %v. %v", idx, err)
+	}
+
+	// Without a cached master the download error is returned.
+	if err := os.Remove(cache.Path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Load(context.Background()); err == nil {
+		t.Errorf("Expected an error without a cached master")
+	}
+}
+
+func TestCacheFresh(t *testing.T) {
+	cache := NewCache("scrip_master.json")
+	at := func(clock string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", "2024-01-"+clock, exchangeLocation)
+		return t
+	}
+
+	cases := []struct {
+		modTime, now time.Time
+		fresh        bool
+	}{
+		{at("10 09:00"), at("10 15:00"), true},
+		{at("10 08:00"), at("10 09:00"), false},
+		{at("09 09:00"), at("10 08:00"), true},
+		{at("09 09:00"), at("10 08:45"), false},
+	}
+	for _, c := range cases {
+		now := c.now
+		cache.now = func() time.Time { return now }
+		if fresh := cache.fresh(c.modTime); fresh != c.fresh {
+			t.Errorf("Expected fresh %v for a master cached at %v at %v", c.fresh, c.modTime, c.now)
+		}
+	}
+}
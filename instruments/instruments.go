@@ -0,0 +1,211 @@
+// Package instruments downloads the SmartAPI instrument master and indexes it
+// for looking up instruments by token and by symbol.
+package instruments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScripMasterURL is the url of the instrument master, updated daily.
+const ScripMasterURL = "https://margincalculator.angelbroking.com/OpenAPI_File/files/OpenAPIScripMaster.json"
+
+// expiryLayout is the layout of the expiry dates in the instrument master.
+const expiryLayout = "02Jan2006"
+
+// Instrument is an individual instrument of the instrument master.
+type Instrument struct {
+	Token          string
+	Symbol         string
+	Name           string
+	Expiry         time.Time
+	Strike         float64
+	LotSize        int
+	TickSize       float64
+	InstrumentType string
+	// Segment is the exchange segment of the instrument, e.g. NSE, NFO or MCX.
+	Segment string
+}
+
+// rawInstrument is an instrument as encoded in the instrument master.
+// Strikes and tick sizes are in paise.
+type rawInstrument struct {
+	Token          string `json:"token"`
+	Symbol         string `json:"symbol"`
+	Name           string `json:"name"`
+	Expiry         string `json:"expiry"`
+	Strike         string `json:"strike"`
+	LotSize        string `json:"lotsize"`
+	InstrumentType string `json:"instrumenttype"`
+	Segment        string `json:"exch_seg"`
+	TickSize       string `json:"tick_size"`
+}
+
+// UnmarshalJSON parses an instrument of the instrument master.
+func (i *Instrument) UnmarshalJSON(data []byte) error {
+	var raw rawInstrument
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*i = Instrument{
+		Token:          raw.Token,
+		Symbol:         raw.Symbol,
+		Name:           raw.Name,
+		InstrumentType: raw.InstrumentType,
+		Segment:        raw.Segment,
+	}
+
+	var err error
+	if raw.Expiry != "" {
+		if i.Expiry, err = time.Parse(expiryLayout, raw.Expiry); err != nil {
+			return fmt.Errorf("instruments: invalid expiry %q of token %s: %w", raw.Expiry, raw.Token, err)
+		}
+	}
+	if i.Strike, err = parsePaise(raw.Strike); err != nil {
+		return fmt.Errorf("instruments: invalid strike %q of token %s: %w", raw.Strike, raw.Token, err)
+	}
+	if i.TickSize, err = parsePaise(raw.TickSize); err != nil {
+		return fmt.Errorf("instruments: invalid tick size %q of token %s: %w", raw.TickSize, raw.Token, err)
+	}
+	if raw.LotSize != "" {
+		if i.LotSize, err = strconv.Atoi(raw.LotSize); err != nil {
+			return fmt.Errorf("instruments: invalid lot size %q of token %s: %w", raw.LotSize, raw.Token, err)
+		}
+	}
+	return nil
+}
+
+// parsePaise parses an amount in paise into rupees. Negative amounts, used for
+// instruments without strike, are returned as 0.
+func parsePaise(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	paise, err := strconv.ParseFloat(value, 64)
+	if err != nil || paise < 0 {
+		return 0, err
+	}
+	return paise / 100, nil
+}
+
+// key identifies an instrument of an exchange segment.
+type key struct {
+	segment string
+	value   string
+}
+
+// Index is an in-memory index of the instruments of the instrument master.
+type Index struct {
+	instruments []Instrument
+	byToken     map[key]int
+	bySymbol    map[key]int
+	byName      map[string][]int
+}
+
+// NewIndex indexes instruments by token, by symbol and by name.
+func NewIndex(instruments []Instrument) *Index {
+	idx := &Index{
+		instruments: instruments,
+		byToken:     make(map[key]int, len(instruments)),
+		bySymbol:    make(map[key]int, len(instruments)),
+		byName:      make(map[string][]int),
+	}
+	for i, instrument := range instruments {
+		idx.byToken[key{instrument.Segment, instrument.Token}] = i
+		idx.bySymbol[key{instrument.Segment, strings.ToUpper(instrument.Symbol)}] = i
+		name := strings.ToUpper(instrument.Name)
+		idx.byName[name] = append(idx.byName[name], i)
+	}
+	return idx
+}
+
+// Parse reads an instrument master in JSON and indexes its instruments.
+// The instruments are decoded one at a time without holding the whole document in memory.
+func Parse(r io.Reader) (*Index, error) {
+	dec := json.NewDecoder(r)
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("instruments: reading instrument master: %w", err)
+	}
+
+	var instruments []Instrument
+	for dec.More() {
+		var instrument Instrument
+		if err := dec.Decode(&instrument); err != nil {
+			return nil, fmt.Errorf("instruments: reading instrument master: %w", err)
+		}
+		instruments = append(instruments, instrument)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("instruments: reading instrument master: %w", err)
+	}
+	return NewIndex(instruments), nil
+}
+
+// Download downloads the instrument master from ScripMasterURL with client,
+// or http.DefaultClient when nil, and indexes its instruments.
+func Download(ctx context.Context, client *http.Client) (*Index, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ScripMasterURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("instruments: downloading instrument master: %s", resp.Status)
+	}
+	return Parse(resp.Body)
+}
+
+// Len returns the number of instruments.
+func (idx *Index) Len() int {
+	return len(idx.instruments)
+}
+
+// All returns the instruments in the order of the instrument master.
+func (idx *Index) All() []Instrument {
+	return idx.instruments
+}
+
+// ByToken returns the instrument of the exchange segment with token.
+func (idx *Index) ByToken(segment, token string) (Instrument, bool) {
+	i, ok := idx.byToken[key{strings.ToUpper(segment), token}]
+	if !ok {
+		return Instrument{}, false
+	}
+	return idx.instruments[i], true
+}
+
+// BySymbol returns the instrument of the exchange segment with the trading symbol, e.g. SBIN-EQ.
+// Symbols are matched case insensitively.
+func (idx *Index) BySymbol(segment, symbol string) (Instrument, bool) {
+	i, ok := idx.bySymbol[key{strings.ToUpper(segment), strings.ToUpper(symbol)}]
+	if !ok {
+		return Instrument{}, false
+	}
+	return idx.instruments[i], true
+}
+
+// Token returns the token of the trading symbol on the exchange segment.
+// It can be used as the symbol resolver of the ticker.
+func (idx *Index) Token(segment, symbol string) (string, error) {
+	instrument, ok := idx.BySymbol(segment, symbol)
+	if !ok {
+		return "", fmt.Errorf("instruments: symbol %s:%s not found", segment, symbol)
+	}
+	return instrument.Token, nil
+}
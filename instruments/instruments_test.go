@@ -0,0 +1,127 @@
+package instruments
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scripMaster is an excerpt of the instrument master.
+const scripMaster = `
+[
+{"token":"3045","symbol":"SBIN-EQ","name":"SBIN","expiry":"","strike":"-1.000000","lotsize":"1","instrumenttype":"","exch_seg":"NSE","tick_size":"5.000000"},
+{"token":"500112","symbol":"SBIN","name":"STATE BANK OF INDIA","expiry":"","strike":"-1.000000","lotsize":"1","instrumenttype":"","exch_seg":"BSE","tick_size":"5.000000"},
+{"token":"2885","symbol":"RELIANCE-EQ","name":"RELIANCE","expiry":"","strike":"-1.000000","lotsize":"1","instrumenttype":"","exch_seg":"NSE","tick_size":"10.000000"},
+{"token":"35001","symbol":"NIFTY25JAN24FUT","name":"NIFTY","expiry":"25JAN2024","strike":"-1.000000","lotsize":"50","instrumenttype":"FUTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"35002","symbol":"NIFTY29FEB24FUT","name":"NIFTY","expiry":"29FEB2024","strike":"-1.000000","lotsize":"50","instrumenttype":"FUTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"43101","symbol":"NIFTY25JAN2421500CE","name":"NIFTY","expiry":"25JAN2024","strike":"2150000.000000","lotsize":"50","instrumenttype":"OPTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"43102","symbol":"NIFTY25JAN2421500PE","name":"NIFTY","expiry":"25JAN2024","strike":"2150000.000000","lotsize":"50","instrumenttype":"OPTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"43103","symbol":"NIFTY25JAN2421600CE","name":"NIFTY","expiry":"25JAN2024","strike":"2160000.000000","lotsize":"50","instrumenttype":"OPTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"43104","symbol":"NIFTY25JAN2421600PE","name":"NIFTY","expiry":"25JAN2024","strike":"2160000.000000","lotsize":"50","instrumenttype":"OPTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"43105","symbol":"NIFTY25JAN2422000CE","name":"NIFTY","expiry":"25JAN2024","strike":"2200000.000000","lotsize":"50","instrumenttype":"OPTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"44101","symbol":"NIFTY01FEB2421500CE","name":"NIFTY","expiry":"01FEB2024","strike":"2150000.000000","lotsize":"50","instrumenttype":"OPTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"52001","symbol":"BANKNIFTY25JAN24FUT","name":"BANKNIFTY","expiry":"25JAN2024","strike":"-1.000000","lotsize":"15","instrumenttype":"FUTIDX","exch_seg":"NFO","tick_size":"5.000000"},
+{"token":"234230","symbol":"CRUDEOIL19JAN24FUT","name":"CRUDEOIL","expiry":"19JAN2024","strike":"-1.000000","lotsize":"1","instrumenttype":"FUTCOM","exch_seg":"MCX","tick_size":"100.000000"}
+]
+`
+
+func parseScripMaster(t *testing.T) *Index {
+	idx, err := Parse(strings.NewReader(scripMaster))
+	if err != nil {
+		t.Fatalf("Error parsing instrument master. %v", err)
+	}
+	return idx
+}
+
+func TestParse(t *testing.T) {
+	idx := parseScripMaster(t)
+	if idx.Len() != 13 {
+		t.Errorf("Expected 13 instruments, got %d", idx.Len())
+	}
+
+	option, ok := idx.ByToken("NFO", "43101")
+	if !ok {
+		t.Fatalf("Option not found by token")
+	}
+	want := Instrument{
+		Token:          "43101",
+		Symbol:         "NIFTY25JAN2421500CE",
+		Name:           "NIFTY",
+		Expiry:         time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC),
+		Strike:         21500,
+		LotSize:        50,
+		TickSize:       0.05,
+		InstrumentType: "OPTIDX",
+		Segment:        "NFO",
+	}
+	if option != want {
+		t.Errorf("Unexpected option %+v", option)
+	}
+
+	equity, ok := idx.BySymbol("nse", "sbin-eq")
+	if !ok || equity.Token != "3045" || equity.Strike != 0 || !equity.Expiry.IsZero() {
+		t.Errorf("Unexpected equity %+v", equity)
+	}
+
+	// Tokens are only unique within an exchange segment.
+	if _, ok := idx.ByToken("BSE", "3045"); ok {
+		t.Errorf("Unexpected instrument for a token of another segment")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"token":"3045"}`,
+		`[{"token":"3045","lotsize":"one"}]`,
+		`[{"token":"3045","expiry":"2024-01-25"}]`,
+		`[{"token":"3045"}`,
+	} {
+		if _, err := Parse(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected an error parsing %s", data)
+		}
+	}
+}
+
+func TestDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(scripMaster))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: redirectTransport(server.URL)}
+	idx, err := Download(context.Background(), client)
+	if err != nil || idx.Len() != 13 {
+		t.Errorf("Unexpected index %v. %v", idx, err)
+	}
+}
+
+// redirectTransport sends the requests for ScripMasterURL to the server at target.
+type redirectTransport string
+
+func (target redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.String() != ScripMasterURL {
+		return nil, fmt.Errorf("unexpected request %s", req.URL)
+	}
+	u, err := url.Parse(string(target))
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = u.Scheme, u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestToken(t *testing.T) {
+	idx := parseScripMaster(t)
+	if token, err := idx.Token("NSE", "RELIANCE-EQ"); err != nil || token != "2885" {
+		t.Errorf("Unexpected token %q. %v", token, err)
+	}
+	if _, err := idx.Token("NSE", "UNKNOWN-EQ"); err == nil {
+		t.Errorf("Expected an error for an unknown symbol")
+	}
+}
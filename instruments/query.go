@@ -0,0 +1,146 @@
+package instruments
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter selects instruments in queries.
+type Filter func(instrument Instrument) bool
+
+// Segments selects the instruments of any of the exchange segments.
+func Segments(segments ...string) Filter {
+	return func(instrument Instrument) bool {
+		for _, segment := range segments {
+			if strings.EqualFold(instrument.Segment, segment) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ExpiryBetween selects the instruments expiring between from and to, both inclusive.
+// A zero from or to leaves the range open on that side.
+func ExpiryBetween(from, to time.Time) Filter {
+	return func(instrument Instrument) bool {
+		if instrument.Expiry.IsZero() {
+			return false
+		}
+		return (from.IsZero() || !instrument.Expiry.Before(from)) && (to.IsZero() || !instrument.Expiry.After(to))
+	}
+}
+
+// StrikeBetween selects the instruments with a strike between low and high, both inclusive.
+func StrikeBetween(low, high float64) Filter {
+	return func(instrument Instrument) bool {
+		return instrument.Strike >= low && instrument.Strike <= high
+	}
+}
+
+// StrikeWindow selects the instruments with a strike within width of spot,
+// e.g. the strikes around the at-the-money strike of an option chain.
+func StrikeWindow(spot, width float64) Filter {
+	return StrikeBetween(spot-width, spot+width)
+}
+
+// IsOption reports whether the instrument is an option.
+func (i Instrument) IsOption() bool {
+	return strings.HasPrefix(i.InstrumentType, "OPT")
+}
+
+// IsFuture reports whether the instrument is a future.
+func (i Instrument) IsFuture() bool {
+	return strings.HasPrefix(i.InstrumentType, "FUT")
+}
+
+// OptionType returns CE or PE for options, or an empty string.
+func (i Instrument) OptionType() string {
+	if !i.IsOption() || len(i.Symbol) < 2 {
+		return ""
+	}
+	return i.Symbol[len(i.Symbol)-2:]
+}
+
+// matches reports whether the instrument is selected by all filters.
+func matches(instrument Instrument, filters []Filter) bool {
+	for _, filter := range filters {
+		if !filter(instrument) {
+			return false
+		}
+	}
+	return true
+}
+
+// Search returns the instruments whose symbol or name contains text, matched case
+// insensitively, and which are selected by the filters.
+func (idx *Index) Search(text string, filters ...Filter) []Instrument {
+	text = strings.ToUpper(text)
+	var found []Instrument
+	for _, instrument := range idx.instruments {
+		if (strings.Contains(strings.ToUpper(instrument.Symbol), text) || strings.Contains(strings.ToUpper(instrument.Name), text)) &&
+			matches(instrument, filters) {
+			found = append(found, instrument)
+		}
+	}
+	return found
+}
+
+// underlying returns the instruments with the name of the underlying selected by include and filters.
+func (idx *Index) underlying(name string, include func(Instrument) bool, filters []Filter) []Instrument {
+	var found []Instrument
+	for _, i := range idx.byName[strings.ToUpper(name)] {
+		if instrument := idx.instruments[i]; include(instrument) && matches(instrument, filters) {
+			found = append(found, instrument)
+		}
+	}
+	return found
+}
+
+// Options returns the options of the underlying, e.g. NIFTY, expiring on expiry or on
+// any date when expiry is zero, and selected by the filters. The options are sorted
+// by expiry, strike and option type, so the calls and puts of a strike are adjacent.
+func (idx *Index) Options(underlying string, expiry time.Time, filters ...Filter) []Instrument {
+	options := idx.underlying(underlying, func(instrument Instrument) bool {
+		return instrument.IsOption() && (expiry.IsZero() || instrument.Expiry.Equal(expiry))
+	}, filters)
+	sort.SliceStable(options, func(i, j int) bool {
+		a, b := options[i], options[j]
+		if !a.Expiry.Equal(b.Expiry) {
+			return a.Expiry.Before(b.Expiry)
+		}
+		if a.Strike != b.Strike {
+			return a.Strike < b.Strike
+		}
+		return a.OptionType() < b.OptionType()
+	})
+	return options
+}
+
+// Futures returns the futures of the underlying selected by the filters, sorted by expiry.
+func (idx *Index) Futures(underlying string, filters ...Filter) []Instrument {
+	futures := idx.underlying(underlying, Instrument.IsFuture, filters)
+	sort.SliceStable(futures, func(i, j int) bool {
+		return futures[i].Expiry.Before(futures[j].Expiry)
+	})
+	return futures
+}
+
+// Expiries returns the distinct expiry dates of the derivatives of the underlying in ascending order.
+func (idx *Index) Expiries(underlying string, filters ...Filter) []time.Time {
+	seen := make(map[time.Time]bool)
+	var expiries []time.Time
+	for _, instrument := range idx.underlying(underlying, func(instrument Instrument) bool {
+		return !instrument.Expiry.IsZero()
+	}, filters) {
+		if !seen[instrument.Expiry] {
+			seen[instrument.Expiry] = true
+			expiries = append(expiries, instrument.Expiry)
+		}
+	}
+	sort.Slice(expiries, func(i, j int) bool {
+		return expiries[i].Before(expiries[j])
+	})
+	return expiries
+}
@@ -0,0 +1,95 @@
+package instruments
+
+import (
+	"testing"
+	"time"
+)
+
+func symbols(instruments []Instrument) []string {
+	var s []string
+	for _, instrument := range instruments {
+		s = append(s, instrument.Symbol)
+	}
+	return s
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearch(t *testing.T) {
+	idx := parseScripMaster(t)
+
+	if got := symbols(idx.Search("sbin")); !equal(got, []string{"SBIN-EQ", "SBIN"}) {
+		t.Errorf("Unexpected search result %v", got)
+	}
+	if got := symbols(idx.Search("state bank", Segments("BSE"))); !equal(got, []string{"SBIN"}) {
+		t.Errorf("Unexpected search result by name %v", got)
+	}
+	if got := idx.Search("sbin", Segments("NFO")); len(got) != 0 {
+		t.Errorf("Unexpected search result in NFO %v", symbols(got))
+	}
+}
+
+func TestOptions(t *testing.T) {
+	idx := parseScripMaster(t)
+	expiry := time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC)
+
+	want := []string{"NIFTY25JAN2421500CE", "NIFTY25JAN2421500PE", "NIFTY25JAN2421600CE", "NIFTY25JAN2421600PE", "NIFTY25JAN2422000CE"}
+	if got := symbols(idx.Options("nifty", expiry)); !equal(got, want) {
+		t.Errorf("Unexpected options %v", got)
+	}
+
+	want = []string{"NIFTY25JAN2421500CE", "NIFTY25JAN2421500PE", "NIFTY25JAN2421600CE", "NIFTY25JAN2421600PE"}
+	if got := symbols(idx.Options("NIFTY", expiry, StrikeWindow(21550, 100))); !equal(got, want) {
+		t.Errorf("Unexpected options within the strike window %v", got)
+	}
+
+	want = []string{"NIFTY25JAN2421500CE", "NIFTY25JAN2421500PE", "NIFTY01FEB2421500CE"}
+	if got := symbols(idx.Options("NIFTY", time.Time{}, StrikeBetween(21500, 21500))); !equal(got, want) {
+		t.Errorf("Unexpected options of all expiries %v", got)
+	}
+}
+
+func TestFutures(t *testing.T) {
+	idx := parseScripMaster(t)
+
+	if got := symbols(idx.Futures("NIFTY")); !equal(got, []string{"NIFTY25JAN24FUT", "NIFTY29FEB24FUT"}) {
+		t.Errorf("Unexpected futures %v", got)
+	}
+
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if got := symbols(idx.Futures("NIFTY", ExpiryBetween(feb, time.Time{}))); !equal(got, []string{"NIFTY29FEB24FUT"}) {
+		t.Errorf("Unexpected futures after February %v", got)
+	}
+	if got := symbols(idx.Futures("CRUDEOIL", Segments("MCX"))); !equal(got, []string{"CRUDEOIL19JAN24FUT"}) {
+		t.Errorf("Unexpected MCX futures %v", got)
+	}
+}
+
+func TestExpiries(t *testing.T) {
+	idx := parseScripMaster(t)
+
+	want := []time.Time{
+		time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+	}
+	got := idx.Expiries("NIFTY")
+	if len(got) != len(want) {
+		t.Fatalf("Unexpected expiries %v", got)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Unexpected expiries %v", got)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package smartapigo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// maxMarginPositions is the maximum number of positions in a margin calculation.
+const maxMarginPositions = 50
+
+// MarginPosition represents a position for calculating margin.
+type MarginPosition struct {
+	Exchange    string  `json:"exchange"`
+	Qty         int     `json:"qty"`
+	Price       float64 `json:"price"`
+	ProductType string  `json:"productType"`
+	Token       string  `json:"token"`
+	TradeType   string  `json:"tradeType"`
+	OrderType   string  `json:"orderType"`
+}
+
+// MarginComponents represents the components of the margin required.
+type MarginComponents struct {
+	NetPremium          float64 `json:"netPremium"`
+	SpanMargin          float64 `json:"spanMargin"`
+	MarginBenefit       float64 `json:"marginBenefit"`
+	DeliveryMargin      float64 `json:"deliveryMargin"`
+	NonNFOMargin        float64 `json:"nonNFOMargin"`
+	TotalOptionsPremium float64 `json:"totOptionsPremium"`
+}
+
+// MarginBreakup represents the margin required for an exchange and product type.
+type MarginBreakup struct {
+	Exchange            string  `json:"exchange"`
+	ProductType         string  `json:"productType"`
+	TotalMarginRequired float64 `json:"totalMarginRequired"`
+}
+
+// MarginResponse represents Margin API response.
+type MarginResponse struct {
+	TotalMarginRequired float64          `json:"totalMarginRequired"`
+	MarginComponents    MarginComponents `json:"marginComponents"`
+	MarginBreakup       []MarginBreakup  `json:"marginBreakup"`
+}
+
+// GetMargin gets the margin required for a basket of up to 50 positions.
+func (c *Client) GetMargin(positions []MarginPosition) (MarginResponse, error) {
+	return c.GetMarginCtx(context.Background(), positions)
+}
+
+// GetMarginCtx is GetMargin with a context for the request.
+func (c *Client) GetMarginCtx(ctx context.Context, positions []MarginPosition) (MarginResponse, error) {
+	var margin MarginResponse
+	if len(positions) == 0 || len(positions) > maxMarginPositions {
+		return margin, NewError(InputError, "margin can be calculated for 1 to 50 positions", nil)
+	}
+
+	params := map[string]interface{}{}
+	params["positions"] = positions
+	err := c.doEnvelope(ctx, http.MethodPost, URIMargin, params, nil, &margin, true)
+	return margin, err
+}
+
+// BasketMargin represents the margin required for a basket of orders.
+// Total holds the margin of the whole basket, including the benefit of hedged legs,
+// and Legs the margin of each order on its own in the order of the basket.
+type BasketMargin struct {
+	Total MarginResponse
+	Legs  []MarginResponse
+}
+
+// GetBasketMargin gets the margin required for a basket of up to 50 orders. The
+// API returns the margin of the whole basket only, so the margin of every leg is
+// another request, sent within the rate limit of RateLimitMargin.
+func (c *Client) GetBasketMargin(basket []OrderParams) (BasketMargin, error) {
+	return c.GetBasketMarginCtx(context.Background(), basket)
+}
+
+// GetBasketMarginCtx is GetBasketMargin with a context for the request.
+func (c *Client) GetBasketMarginCtx(ctx context.Context, basket []OrderParams) (BasketMargin, error) {
+	var basketMargin BasketMargin
+
+	positions := make([]MarginPosition, 0, len(basket))
+	for _, orderParams := range basket {
+		position, err := marginPosition(orderParams)
+		if err != nil {
+			return basketMargin, err
+		}
+		positions = append(positions, position)
+	}
+
+	total, err := c.GetMarginCtx(ctx, positions)
+	if err != nil {
+		return basketMargin, err
+	}
+	basketMargin.Total = total
+
+	for _, position := range positions {
+		leg, err := c.GetMarginCtx(ctx, []MarginPosition{position})
+		if err != nil {
+			return basketMargin, err
+		}
+		basketMargin.Legs = append(basketMargin.Legs, leg)
+	}
+
+	return basketMargin, nil
+}
+
+// marginPosition converts the parameters of an order into a margin position.
+func marginPosition(orderParams OrderParams) (MarginPosition, error) {
+	qty, err := strconv.Atoi(orderParams.Quantity)
+	if err != nil {
+		return MarginPosition{}, NewError(InputError, "invalid order quantity "+orderParams.Quantity, nil)
+	}
+
+	var price float64
+	if orderParams.Price != "" {
+		if price, err = strconv.ParseFloat(orderParams.Price, 64); err != nil {
+			return MarginPosition{}, NewError(InputError, "invalid order price "+orderParams.Price, nil)
+		}
+	}
+
+	return MarginPosition{
+		Exchange:    string(orderParams.Exchange),
+		Qty:         qty,
+		Price:       price,
+		ProductType: string(orderParams.ProductType),
+		Token:       orderParams.SymbolToken,
+		TradeType:   string(orderParams.TransactionType),
+		OrderType:   string(orderParams.OrderType),
+	}, nil
+}
+
+// OrderPreview is the verdict of PreviewOrder on whether the funds available
+// cover the margin required for an order.
+type OrderPreview struct {
+	Margin MarginResponse
+	// Required is the margin required for the order.
+	Required float64
+	// Available is the net margin available in the RMS limits.
+	Available float64
+	// Shortfall is the margin missing to place the order, zero when enough is available.
+	Shortfall float64
+}
+
+// OK reports whether the funds available cover the margin required.
+func (preview OrderPreview) OK() bool {
+	return preview.Shortfall == 0
+}
+
+// PreviewOrder gets the margin required for an order and compares it with the
+// net margin available in the RMS limits, without placing the order.
+func (c *Client) PreviewOrder(orderParams OrderParams) (OrderPreview, error) {
+	return c.PreviewOrderCtx(context.Background(), orderParams)
+}
+
+// PreviewOrderCtx is PreviewOrder with a context for the requests.
+func (c *Client) PreviewOrderCtx(ctx context.Context, orderParams OrderParams) (OrderPreview, error) {
+	var preview OrderPreview
+
+	if orderParams.SymbolToken == "" && c.instruments.Load() != nil {
+		if err := c.ResolveSymbolToken(&orderParams); err != nil {
+			return preview, err
+		}
+	}
+	position, err := marginPosition(orderParams)
+	if err != nil {
+		return preview, err
+	}
+
+	if preview.Margin, err = c.GetMarginCtx(ctx, []MarginPosition{position}); err != nil {
+		return preview, err
+	}
+	rms, err := c.GetRMSCtx(ctx)
+	if err != nil {
+		return preview, err
+	}
+
+	preview.Required = preview.Margin.TotalMarginRequired
+	preview.Available = rms.Net
+	if preview.Required > preview.Available {
+		preview.Shortfall = preview.Required - preview.Available
+	}
+	return preview, nil
+}
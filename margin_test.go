@@ -0,0 +1,121 @@
+package smartapigo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func (ts *TestSuite) TestGetMargin(t *testing.T) {
+	t.Parallel()
+	positions := []MarginPosition{{
+		Exchange:    "NFO",
+		Qty:         50,
+		Price:       0,
+		ProductType: "CARRYFORWARD",
+		Token:       "67300",
+		TradeType:   "BUY",
+		OrderType:   "MARKET",
+	}}
+	margin, err := ts.TestConnect.GetMargin(positions)
+	if err != nil {
+		t.Errorf("Error while fetching margin. %v", err)
+	}
+
+	if margin.TotalMarginRequired != 29612.35 || len(margin.MarginBreakup) != 1 {
+		t.Errorf("Unexpected margin %+v", margin)
+	}
+
+	if _, err := ts.TestConnect.GetMargin(make([]MarginPosition, 51)); err == nil {
+		t.Errorf("Expected an error calculating margin for 51 positions")
+	}
+}
+
+func (ts *TestSuite) TestGetBasketMargin(t *testing.T) {
+	t.Parallel()
+	basket := []OrderParams{
+		{Exchange: "NFO", SymbolToken: "67300", TransactionType: "BUY", OrderType: "MARKET", ProductType: "CARRYFORWARD", Quantity: "50"},
+		{Exchange: "NFO", SymbolToken: "67308", TransactionType: "SELL", OrderType: "LIMIT", ProductType: "CARRYFORWARD", Quantity: "50", Price: "105.5"},
+	}
+	basketMargin, err := ts.TestConnect.GetBasketMargin(basket)
+	if err != nil {
+		t.Errorf("Error while fetching basket margin. %v", err)
+	}
+
+	if basketMargin.Total.TotalMarginRequired != 29612.35 || len(basketMargin.Legs) != 2 {
+		t.Errorf("Unexpected basket margin %+v", basketMargin)
+	}
+
+	basket[1].Quantity = "fifty"
+	if _, err := ts.TestConnect.GetBasketMargin(basket); err == nil {
+		t.Errorf("Expected an error for an invalid quantity")
+	}
+}
+
+func TestGetBasketMarginRateLimit(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIMargin, httpmock.NewStringResponder(http.StatusOK,
+		`{"status":true,"message":"SUCCESS","errorcode":"","data":{"totalMarginRequired":100}}`))
+	client.SetRateLimit(RateLimitMargin, 2, 0)
+
+	basket := []OrderParams{
+		{Exchange: "NFO", SymbolToken: "67300", TransactionType: "BUY", OrderType: "MARKET", ProductType: "CARRYFORWARD", Quantity: "50"},
+		{Exchange: "NFO", SymbolToken: "67308", TransactionType: "SELL", OrderType: "MARKET", ProductType: "CARRYFORWARD", Quantity: "50"},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetBasketMarginCtx(ctx, basket); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the margin of the second leg to be held back, got %v", err)
+	}
+	if calls := transport.GetCallCountInfo()["POST "+client.baseURI+URIMargin]; calls != 2 {
+		t.Errorf("Expected 2 margin requests within the limit, got %d", calls)
+	}
+}
+
+func TestMarginPosition(t *testing.T) {
+	position, err := marginPosition(OrderParams{
+		Exchange:        "NFO",
+		SymbolToken:     "67308",
+		TransactionType: "SELL",
+		OrderType:       "LIMIT",
+		ProductType:     "CARRYFORWARD",
+		Quantity:        "50",
+		Price:           "105.5",
+	})
+	if err != nil {
+		t.Fatalf("Error converting order params. %v", err)
+	}
+
+	want := MarginPosition{Exchange: "NFO", Qty: 50, Price: 105.5, ProductType: "CARRYFORWARD", Token: "67308", TradeType: "SELL", OrderType: "LIMIT"}
+	if position != want {
+		t.Errorf("Unexpected margin position %+v", position)
+	}
+}
+
+func (ts *TestSuite) TestPreviewOrder(t *testing.T) {
+	t.Parallel()
+	preview, err := ts.TestConnect.PreviewOrder(OrderParams{Exchange: "NFO", SymbolToken: "67300", TransactionType: "BUY", OrderType: "MARKET", ProductType: "CARRYFORWARD", Quantity: "50"})
+	if err != nil || !preview.OK() || preview.Required != 29612.35 || preview.Available != 9999999999999 {
+		t.Errorf("Unexpected preview %+v. %v", preview, err)
+	}
+}
+
+func TestPreviewOrderShortfall(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIMargin, httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"totalMarginRequired":29612.35}}`))
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIRMS, httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"net":"10000"}}`))
+
+	preview, err := client.PreviewOrder(OrderParams{Exchange: "NFO", SymbolToken: "67300", TransactionType: "BUY", OrderType: "MARKET", ProductType: "CARRYFORWARD", Quantity: "50"})
+	if err != nil || preview.OK() || preview.Available != 10000 || math.Abs(preview.Shortfall-19612.35) > 1e-9 {
+		t.Errorf("Unexpected preview %+v. %v", preview, err)
+	}
+
+	if _, err := client.PreviewOrder(OrderParams{Exchange: "NFO", SymbolToken: "67300", Quantity: "fifty"}); err == nil {
+		t.Errorf("Expected an error for an invalid quantity")
+	}
+}
@@ -1,6 +1,9 @@
 package smartapigo
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // LTPResponse represents LTP API Response.
 type LTPResponse struct {
@@ -23,8 +26,165 @@ type LTPParams struct {
 
 // GetLTP gets Last Traded Price.
 func (c *Client) GetLTP(ltpParams LTPParams) (LTPResponse, error) {
+	return c.GetLTPCtx(context.Background(), ltpParams)
+}
+
+// GetLTPCtx is GetLTP with a context for the request.
+func (c *Client) GetLTPCtx(ctx context.Context, ltpParams LTPParams) (LTPResponse, error) {
 	var ltp LTPResponse
 	params := structToMap(ltpParams, "json")
-	err := c.doEnvelope(http.MethodPost, URILTP, params, nil, &ltp, true)
+	err := c.doEnvelope(ctx, http.MethodPost, URILTP, params, nil, &ltp, true)
 	return ltp, err
 }
+
+// ExpiryType is the expiry of the contracts in market data.
+type ExpiryType string
+
+const (
+	ExpiryNear ExpiryType = "NEAR"
+	ExpiryNext ExpiryType = "NEXT"
+	ExpiryFar  ExpiryType = "FAR"
+)
+
+// OIBuildupType is the kind of open interest buildup.
+type OIBuildupType string
+
+const (
+	LongBuiltUp   OIBuildupType = "Long Built Up"
+	ShortBuiltUp  OIBuildupType = "Short Built Up"
+	ShortCovering OIBuildupType = "Short Covering"
+	LongUnwinding OIBuildupType = "Long Unwinding"
+)
+
+// OIBuildup represents an individual OI buildup response.
+type OIBuildup struct {
+	SymbolToken           int     `json:"symbolToken"`
+	TradingSymbol         string  `json:"tradingSymbol"`
+	Ltp                   float64 `json:"ltp"`
+	NetChange             float64 `json:"netChange"`
+	PercentChange         float64 `json:"percentChange"`
+	OpenInterest          float64 `json:"opnInterest"`
+	NetChangeOpenInterest float64 `json:"netChangeOpnInterest"`
+}
+
+// OIBuildups is a list of OI buildups.
+type OIBuildups []OIBuildup
+
+// GetOIBuildup gets the contracts with the open interest buildup of dataType.
+func (c *Client) GetOIBuildup(expiryType ExpiryType, dataType OIBuildupType) (OIBuildups, error) {
+	return c.GetOIBuildupCtx(context.Background(), expiryType, dataType)
+}
+
+// GetOIBuildupCtx is GetOIBuildup with a context for the request.
+func (c *Client) GetOIBuildupCtx(ctx context.Context, expiryType ExpiryType, dataType OIBuildupType) (OIBuildups, error) {
+	var oiBuildups OIBuildups
+	params := map[string]interface{}{}
+	params["expirytype"] = expiryType
+	params["datatype"] = dataType
+	err := c.doEnvelope(ctx, http.MethodPost, URIOIBuildup, params, nil, &oiBuildups, true)
+	return oiBuildups, err
+}
+
+// ScripResult represents an individual search scrip response.
+type ScripResult struct {
+	Exchange      string `json:"exchange"`
+	TradingSymbol string `json:"tradingsymbol"`
+	SymbolToken   string `json:"symboltoken"`
+}
+
+// ScripResults is a list of search scrip results.
+type ScripResults []ScripResult
+
+// SearchScrip searches the scrips of an exchange matching searchText.
+func (c *Client) SearchScrip(exchange Exchange, searchText string) (ScripResults, error) {
+	return c.SearchScripCtx(context.Background(), exchange, searchText)
+}
+
+// SearchScripCtx is SearchScrip with a context for the request.
+func (c *Client) SearchScripCtx(ctx context.Context, exchange Exchange, searchText string) (ScripResults, error) {
+	var scrips ScripResults
+	params := map[string]interface{}{}
+	params["exchange"] = exchange
+	params["searchscrip"] = searchText
+	err := c.doEnvelope(ctx, http.MethodPost, URISearchScrip, params, nil, &scrips, true)
+	return scrips, err
+}
+
+// QuoteMode is the mode of a market data request.
+type QuoteMode string
+
+const (
+	QuoteModeFull QuoteMode = "FULL"
+	QuoteModeOHLC QuoteMode = "OHLC"
+	QuoteModeLTP  QuoteMode = "LTP"
+)
+
+// DepthItem represents a single market depth entry.
+type DepthItem struct {
+	Price    float64 `json:"price"`
+	Quantity int     `json:"quantity"`
+	Orders   int     `json:"orders"`
+}
+
+// Depth represents a group of buy/sell market depth entries.
+type Depth struct {
+	Buy  []DepthItem `json:"buy"`
+	Sell []DepthItem `json:"sell"`
+}
+
+// Quote represents the market data of a scrip. Only the fields of the requested
+// mode are set, LTP mode sets the ltp and OHLC mode also sets the open, high, low and close.
+type Quote struct {
+	Exchange      string  `json:"exchange"`
+	TradingSymbol string  `json:"tradingSymbol"`
+	SymbolToken   string  `json:"symbolToken"`
+	Ltp           float64 `json:"ltp"`
+	Open          float64 `json:"open"`
+	High          float64 `json:"high"`
+	Low           float64 `json:"low"`
+	Close         float64 `json:"close"`
+	LastTradeQty  int     `json:"lastTradeQty"`
+	ExchFeedTime  Time    `json:"exchFeedTime"`
+	ExchTradeTime Time    `json:"exchTradeTime"`
+	NetChange     float64 `json:"netChange"`
+	PercentChange float64 `json:"percentChange"`
+	AvgPrice      float64 `json:"avgPrice"`
+	TradeVolume   int     `json:"tradeVolume"`
+	OpenInterest  float64 `json:"opnInterest"`
+	LowerCircuit  float64 `json:"lowerCircuit"`
+	UpperCircuit  float64 `json:"upperCircuit"`
+	TotalBuyQty   float64 `json:"totBuyQuan"`
+	TotalSellQty  float64 `json:"totSellQuan"`
+	WeekLow52     float64 `json:"52WeekLow"`
+	WeekHigh52    float64 `json:"52WeekHigh"`
+	Depth         Depth   `json:"depth"`
+}
+
+// UnfetchedQuote represents a scrip whose market data could not be fetched.
+type UnfetchedQuote struct {
+	Exchange    string `json:"exchange"`
+	SymbolToken string `json:"symbolToken"`
+	Message     string `json:"message"`
+	ErrorCode   string `json:"errorCode"`
+}
+
+// MarketData represents Market Data API response.
+type MarketData struct {
+	Fetched   []Quote          `json:"fetched"`
+	Unfetched []UnfetchedQuote `json:"unfetched"`
+}
+
+// GetMarketData gets the market data of the tokens of each exchange in mode.
+func (c *Client) GetMarketData(mode QuoteMode, exchangeTokens map[Exchange][]string) (MarketData, error) {
+	return c.GetMarketDataCtx(context.Background(), mode, exchangeTokens)
+}
+
+// GetMarketDataCtx is GetMarketData with a context for the request.
+func (c *Client) GetMarketDataCtx(ctx context.Context, mode QuoteMode, exchangeTokens map[Exchange][]string) (MarketData, error) {
+	var marketData MarketData
+	params := map[string]interface{}{}
+	params["mode"] = mode
+	params["exchangeTokens"] = exchangeTokens
+	err := c.doEnvelope(ctx, http.MethodPost, URIMarketData, params, nil, &marketData, true)
+	return marketData, err
+}
@@ -21,3 +21,52 @@ func (ts *TestSuite) TestGetLTP(t *testing.T) {
 	}
 
 }
+
+func (ts *TestSuite) TestGetOIBuildup(t *testing.T) {
+	t.Parallel()
+	oiBuildups, err := ts.TestConnect.GetOIBuildup(ExpiryNear, LongBuiltUp)
+	if err != nil {
+		t.Errorf("Error while fetching OI buildup. %v", err)
+	}
+
+	if len(oiBuildups) != 1 || oiBuildups[0].Ltp != 723.8 || oiBuildups[0].OpenInterest != 24982.5 {
+		t.Errorf("Unexpected OI buildup %+v", oiBuildups)
+	}
+}
+
+func (ts *TestSuite) TestSearchScrip(t *testing.T) {
+	t.Parallel()
+	scrips, err := ts.TestConnect.SearchScrip(NSE, "SBIN")
+	if err != nil {
+		t.Errorf("Error while searching scrip. %v", err)
+	}
+
+	if len(scrips) != 2 || scrips[0].SymbolToken != "3045" {
+		t.Errorf("Unexpected scrips %+v", scrips)
+	}
+}
+
+func (ts *TestSuite) TestGetMarketData(t *testing.T) {
+	t.Parallel()
+	marketData, err := ts.TestConnect.GetMarketData(QuoteModeFull, map[Exchange][]string{NSE: {"3045", "0"}})
+	if err != nil {
+		t.Errorf("Error while fetching market data. %v", err)
+	}
+
+	if len(marketData.Fetched) != 1 || len(marketData.Unfetched) != 1 {
+		t.Fatalf("Unexpected market data %+v", marketData)
+	}
+
+	quote := marketData.Fetched[0]
+	if quote.Ltp != 571.8 || quote.UpperCircuit != 626.8 || quote.WeekHigh52 != 629.55 {
+		t.Errorf("Unexpected quote %+v", quote)
+	}
+
+	if len(quote.Depth.Buy) != 2 || quote.Depth.Sell[0].Quantity != 1092 {
+		t.Errorf("Unexpected depth %+v", quote.Depth)
+	}
+
+	if quote.ExchFeedTime.IsZero() {
+		t.Errorf("Expected the exchange feed time to be parsed")
+	}
+}
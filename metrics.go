@@ -0,0 +1,53 @@
+package smartapigo
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Metrics receives instrumentation events of the REST requests of the client.
+// Implementations must be safe for concurrent use as requests are made from
+// several goroutines.
+type Metrics interface {
+	// RequestDone is called after every attempt of a request with its endpoint,
+	// e.g. URIGetOrderBook, its error code and its latency. The error code is
+	// empty for successful requests, the http status for API errors without a
+	// code and "transport" for other failures, e.g. without a response.
+	RequestDone(endpoint string, errorCode string, latency time.Duration)
+	// RateLimitWait is called with the time a request waited for the rate limit
+	// of its group.
+	RateLimitWait(group RateLimitGroup, wait time.Duration)
+}
+
+// NoopMetrics is a Metrics implementation which discards all events.
+type NoopMetrics struct{}
+
+// RequestDone implements Metrics.
+func (NoopMetrics) RequestDone(string, string, time.Duration) {}
+
+// RateLimitWait implements Metrics.
+func (NoopMetrics) RateLimitWait(RateLimitGroup, time.Duration) {}
+
+// SetMetrics sets the instrumentation receiving the events of the requests.
+func (c *Client) SetMetrics(m Metrics) {
+	if m == nil {
+		m = NoopMetrics{}
+	}
+	c.metrics = m
+}
+
+// errorCode returns the error code reported to Metrics for the error of a request.
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	var apiErr Error
+	if !errors.As(err, &apiErr) {
+		return "transport"
+	}
+	if apiErr.Code == "" {
+		return strconv.Itoa(apiErr.HTTPStatus)
+	}
+	return apiErr.Code
+}
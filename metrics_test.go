@@ -0,0 +1,24 @@
+package smartapigo
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{Error{Code: "AB1009", HTTPStatus: http.StatusOK}, "AB1009"},
+		{Error{HTTPStatus: http.StatusServiceUnavailable}, "503"},
+		{errors.New("connection reset"), "transport"},
+	}
+	for _, c := range cases {
+		if got := errorCode(c.err); got != c.want {
+			t.Errorf("Expected error code %q for %v, got %q", c.want, c.err, got)
+		}
+	}
+}
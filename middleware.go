@@ -0,0 +1,36 @@
+package smartapigo
+
+import "net/http"
+
+// Doer sends an HTTP request and returns its response, like *http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc is an adapter to use a function as a Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer sending the requests of the Client. It can inspect
+// and modify requests and responses, or answer requests without calling next.
+type Middleware func(next Doer) Doer
+
+// Use adds middlewares invoked around every REST request of the Client.
+// The first middleware added is the outermost one.
+func (c *Client) Use(middlewares ...Middleware) {
+	h := c.httpClient.GetClient()
+	h.middlewares = append(h.middlewares, middlewares...)
+}
+
+// doer returns the http client wrapped in the middlewares.
+func (h *httpClient) doer() Doer {
+	var doer Doer = h.client
+	for i := len(h.middlewares) - 1; i >= 0; i-- {
+		doer = h.middlewares[i](doer)
+	}
+	return doer
+}
@@ -0,0 +1,59 @@
+package smartapigo
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestMiddleware(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIUserProfile, func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("X-Audit") != "outer,inner" {
+			return httpmock.NewStringResponse(http.StatusBadRequest, `{"status":false,"message":"missing header"}`), nil
+		}
+		return httpmock.NewStringResponse(http.StatusOK, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"clientcode":"test"}}`), nil
+	})
+
+	var calls []string
+	audit := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				if audited := req.Header.Get("X-Audit"); audited != "" {
+					name = audited + "," + name
+				}
+				req.Header.Set("X-Audit", name)
+				return next.Do(req)
+			})
+		}
+	}
+	client.Use(audit("outer"), audit("inner"))
+
+	// Middlewares are kept when the http client is replaced.
+	client.SetHTTPClient(&http.Client{Transport: transport})
+
+	profile, err := client.GetUserProfile()
+	if err != nil || profile.ClientCode != "test" {
+		t.Errorf("Unexpected profile %+v. %v", profile, err)
+	}
+	if strings.Join(calls, ",") != "outer,inner" {
+		t.Errorf("Unexpected middleware calls %v", calls)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	client, _ := newMockClient()
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, "chaos"), nil
+		})
+	})
+
+	_, err := client.GetUserProfile()
+	if apiErr, ok := err.(Error); !ok || apiErr.HTTPStatus != http.StatusServiceUnavailable {
+		t.Errorf("Expected the injected failure, got %v", err)
+	}
+}
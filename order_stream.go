@@ -0,0 +1,29 @@
+package smartapigo
+
+import (
+	"context"
+
+	"github.com/shammishailaj/smartapigo/orderstream"
+)
+
+// NewOrderStream creates an order status stream authenticated with the session
+// of the client. Before every reconnect the stream takes the current access
+// token, as renewed with RenewAccessToken or the session refresher, and it stops
+// reconnecting once the session is invalidated. The stream connects to the
+// environment of the client and traces with its tracer when it traces order
+// streams too.
+func (c *Client) NewOrderStream() *orderstream.Client {
+	stream := orderstream.New(c.AccessToken())
+	stream.SetRootURL(c.env.OrderStreamURL)
+	if tracer, ok := c.tracer.(orderstream.Tracer); ok {
+		stream.SetTracer(tracer)
+	}
+	stream.SetTokenProvider(func(ctx context.Context) (string, error) {
+		accessToken := c.AccessToken()
+		if accessToken == "" {
+			return "", orderstream.ErrNoSession
+		}
+		return accessToken, nil
+	})
+	return stream
+}
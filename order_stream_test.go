@@ -0,0 +1,86 @@
+package smartapigo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shammishailaj/smartapigo/orderstream"
+)
+
+func TestNewOrderStream(t *testing.T) {
+	tokens := make(chan string, 10)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens <- r.Header.Get("Authorization")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		// Drop the connection to make the stream reconnect.
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"user-id":"test","status-code":"200","order-status":"AB00","error-message":"","orderData":{}}`))
+		conn.Close()
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+
+	client := New("test", "test", "test")
+	client.SetAccessToken("jwt-1")
+	stream := client.NewOrderStream()
+	stream.SetRootURL(*u)
+	reconnected := make(chan struct{})
+	var renew sync.Once
+	stream.SetOnReconnect(func(attempt int, delay time.Duration) {
+		renew.Do(func() {
+			client.SetAccessToken("jwt-2")
+			close(reconnected)
+		})
+	})
+
+	done := make(chan struct{})
+	go func() {
+		stream.Connect()
+		close(done)
+	}()
+
+	if token := <-tokens; token != "Bearer jwt-1" {
+		t.Errorf("Unexpected first authorization %q", token)
+	}
+	<-reconnected
+	select {
+	case token := <-tokens:
+		if token != "Bearer jwt-2" {
+			t.Errorf("Expected the renewed token on reconnect, got %q", token)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the reconnect.")
+	}
+
+	// The stream stops reconnecting once the session is invalidated.
+	client.InvalidateSession()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		_ = stream.Close()
+		t.Fatalf("Expected the stream to stop without a session.")
+	}
+}
+
+func TestNewOrderStreamNoSession(t *testing.T) {
+	client := New("test", "test", "test")
+	stream := client.NewOrderStream()
+	errs := make(chan error, 1)
+	stream.SetOnError(func(err error) {
+		errs <- err
+	})
+	stream.Connect()
+	if err := <-errs; err == nil || !errors.Is(err, orderstream.ErrNoSession) {
+		t.Errorf("Expected no session error, got %v", err)
+	}
+}
@@ -1,9 +1,88 @@
 package smartapigo
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"sync"
 )
 
+// Variety is the variety of an order.
+type Variety string
+
+// TransactionType is the side of an order.
+type TransactionType string
+
+// OrderType is the type of an order.
+type OrderType string
+
+// ProductType is the product of an order.
+type ProductType string
+
+// Duration is the validity of an order.
+type Duration string
+
+const (
+	VarietyNormal   Variety = "NORMAL"
+	VarietyStopLoss Variety = "STOPLOSS"
+	VarietyAMO      Variety = "AMO"
+	VarietyRobo     Variety = "ROBO"
+
+	TransactionBuy  TransactionType = "BUY"
+	TransactionSell TransactionType = "SELL"
+
+	OrderTypeMarket OrderType = "MARKET"
+	OrderTypeLimit  OrderType = "LIMIT"
+	OrderTypeSLL    OrderType = "STOPLOSS_LIMIT"
+	OrderTypeSLM    OrderType = "STOPLOSS_MARKET"
+
+	ProductDelivery     ProductType = "DELIVERY"
+	ProductCarryForward ProductType = "CARRYFORWARD"
+	ProductMargin       ProductType = "MARGIN"
+	ProductIntraday     ProductType = "INTRADAY"
+	ProductBracket      ProductType = "BO"
+
+	DurationDay Duration = "DAY"
+	DurationIOC Duration = "IOC"
+)
+
+// Valid reports whether the variety is one of the known varieties.
+func (v Variety) Valid() bool {
+	switch v {
+	case VarietyNormal, VarietyStopLoss, VarietyAMO, VarietyRobo:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether the transaction type is BUY or SELL.
+func (t TransactionType) Valid() bool {
+	return t == TransactionBuy || t == TransactionSell
+}
+
+// Valid reports whether the order type is one of the known order types.
+func (t OrderType) Valid() bool {
+	switch t {
+	case OrderTypeMarket, OrderTypeLimit, OrderTypeSLL, OrderTypeSLM:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether the product type is one of the known product types.
+func (p ProductType) Valid() bool {
+	switch p {
+	case ProductDelivery, ProductCarryForward, ProductMargin, ProductIntraday, ProductBracket:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether the duration is DAY or IOC.
+func (d Duration) Valid() bool {
+	return d == DurationDay || d == DurationIOC
+}
+
 // Order represents a individual order response.
 type Order struct {
 	Variety                 string `json:"variety"`
@@ -17,7 +96,7 @@ type Order struct {
 	SquareOff               string `json:"squareoff"`
 	StopLoss                string `json:"stoploss"`
 	TrailingStopLoss        string `json:"trailingstoploss"`
-	TrailingSymbol          string `json:"trailingsymbol"`
+	TradingSymbol           string `json:"tradingsymbol"`
 	TransactionType         string `json:"transactiontype"`
 	Exchange                string `json:"exchange"`
 	SymbolToken             string `json:"symboltoken"`
@@ -39,6 +118,10 @@ type Order struct {
 	ExchangeOrderUpdateTime string `json:"exchorderupdatetime"`
 	FillID                  string `json:"fillid"`
 	FillTime                string `json:"filltime"`
+	OrderTag                string `json:"ordertag"`
+
+	// Deprecated: TrailingSymbol is never set, use TradingSymbol.
+	TrailingSymbol string `json:"trailingsymbol"`
 }
 
 // Orders is a list of orders.
@@ -46,32 +129,47 @@ type Orders []Order
 
 // OrderParams represents parameters for placing an order.
 type OrderParams struct {
-	Variety         string `json:"variety"`
-	TradingSymbol   string `json:"tradingsymbol"`
-	SymbolToken     string `json:"symboltoken"`
-	TransactionType string `json:"transactiontype"`
-	Exchange        string `json:"exchange"`
-	OrderType       string `json:"ordertype"`
-	ProductType     string `json:"producttype"`
-	Duration        string `json:"duration"`
-	Price           string `json:"price"`
-	SquareOff       string `json:"squareoff"`
-	StopLoss        string `json:"stoploss"`
-	Quantity        string `json:"quantity"`
+	Variety          Variety         `json:"variety"`
+	TradingSymbol    string          `json:"tradingsymbol"`
+	SymbolToken      string          `json:"symboltoken"`
+	TransactionType  TransactionType `json:"transactiontype"`
+	Exchange         Exchange        `json:"exchange"`
+	OrderType        OrderType       `json:"ordertype"`
+	ProductType      ProductType     `json:"producttype"`
+	Duration         Duration        `json:"duration"`
+	Price            string          `json:"price"`
+	SquareOff        string          `json:"squareoff"`
+	StopLoss         string          `json:"stoploss"`
+	Quantity         string          `json:"quantity"`
+	TriggerPrice     string          `json:"triggerprice,omitempty"`
+	TrailingStopLoss string          `json:"trailingstoploss,omitempty"`
+	OrderTag         string          `json:"ordertag,omitempty"`
 }
 
 // OrderParams represents parameters for modifying an order.
 type ModifyOrderParams struct {
-	Variety       string `json:"variety"`
-	OrderID       string `json:"orderid"`
-	OrderType     string `json:"ordertype"`
-	ProductType   string `json:"producttype"`
-	Duration      string `json:"duration"`
-	Price         string `json:"price"`
-	Quantity      string `json:"quantity"`
-	TradingSymbol string `json:"tradingsymbol"`
-	SymbolToken   string `json:"symboltoken"`
-	Exchange      string `json:"exchange"`
+	Variety       Variety     `json:"variety"`
+	OrderID       string      `json:"orderid"`
+	OrderType     OrderType   `json:"ordertype"`
+	ProductType   ProductType `json:"producttype"`
+	Duration      Duration    `json:"duration"`
+	Price         string      `json:"price"`
+	Quantity      string      `json:"quantity"`
+	TradingSymbol string      `json:"tradingsymbol"`
+	SymbolToken   string      `json:"symboltoken"`
+	Exchange      Exchange    `json:"exchange"`
+	TriggerPrice  string      `json:"triggerprice,omitempty"`
+}
+
+// OrderChanges represents the changes to an order modified with ModifyOrderPartial.
+// Empty fields are left unchanged.
+type OrderChanges struct {
+	OrderType    OrderType
+	ProductType  ProductType
+	Duration     Duration
+	Price        string
+	Quantity     string
+	TriggerPrice string
 }
 
 // OrderResponse represents the order place success response.
@@ -120,8 +218,8 @@ type Position struct {
 	Precision             string `json:"precision"`
 	Multiplier            string `json:"multiplier"`
 	BoardLotSize          string `json:"boardlotsize"`
-	BuyQuantity           string `json:"buyquantity"`
-	SellQuantity          string `json:"sellquantity"`
+	BuyQuantity           string `json:"buyqty"`
+	SellQuantity          string `json:"sellqty"`
 	BuyAmount             string `json:"buyamount"`
 	SellAmount            string `json:"sellamount"`
 	SymbolGroup           string `json:"symbolgroup"`
@@ -152,81 +250,319 @@ type Positions []Position
 
 // ConvertPositionParams represents the input params for a position conversion.
 type ConvertPositionParams struct {
-	Exchange        string `url:"exchange"`
-	TradingSymbol   string `url:"tradingsymbol"`
-	OldProductType  string `url:"oldproducttype"`
-	NewProductType  string `url:"newproducttype"`
-	TransactionType string `url:"transactiontype"`
-	Quantity        int    `url:"quantity"`
+	Exchange        string `json:"exchange"`
+	TradingSymbol   string `json:"tradingsymbol"`
+	OldProductType  string `json:"oldproducttype"`
+	NewProductType  string `json:"newproducttype"`
+	TransactionType string `json:"transactiontype"`
+	Quantity        int    `json:"quantity"`
 	Type            string `json:"type"`
 }
 
 // GetOrderBook gets user orders.
 func (c *Client) GetOrderBook() (Orders, error) {
+	return c.GetOrderBookCtx(context.Background())
+}
+
+// GetOrderBookCtx is GetOrderBook with a context for the request.
+func (c *Client) GetOrderBookCtx(ctx context.Context) (Orders, error) {
 	var orders Orders
-	err := c.doEnvelope(http.MethodGet, URIGetOrderBook, nil, nil, &orders, true)
+	err := c.doEnvelope(ctx, http.MethodGet, URIGetOrderBook, nil, nil, &orders, true)
 	return orders, err
 }
 
-// PlaceOrder places an order.
+// GetOrdersByTag gets the orders of the order book placed with the order tag.
+func (c *Client) GetOrdersByTag(tag string) (Orders, error) {
+	return c.GetOrdersByTagCtx(context.Background(), tag)
+}
+
+// GetOrdersByTagCtx is GetOrdersByTag with a context for the request.
+func (c *Client) GetOrdersByTagCtx(ctx context.Context, tag string) (Orders, error) {
+	orders, err := c.GetOrderBookCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tagged Orders
+	for _, order := range orders {
+		if order.OrderTag == tag {
+			tagged = append(tagged, order)
+		}
+	}
+	return tagged, nil
+}
+
+// PlaceOrder places an order. An empty SymbolToken is resolved from the trading
+// symbol when an instrument master is set with SetInstruments.
 func (c *Client) PlaceOrder(orderParams OrderParams) (OrderResponse, error) {
+	return c.PlaceOrderCtx(context.Background(), orderParams)
+}
+
+// PlaceOrderCtx is PlaceOrder with a context for the request.
+func (c *Client) PlaceOrderCtx(ctx context.Context, orderParams OrderParams) (OrderResponse, error) {
 	var (
 		orderResponse OrderResponse
 		params        map[string]interface{}
 		err           error
 	)
 
+	if orderParams.SymbolToken == "" && c.instruments.Load() != nil {
+		if err = c.ResolveSymbolToken(&orderParams); err != nil {
+			return orderResponse, err
+		}
+	}
+
+	if book := c.paperBook(); book != nil {
+		return book.place(orderParams)
+	}
+
 	params = structToMap(orderParams, "json")
 
-	err = c.doEnvelope(http.MethodPost, URIPlaceOrder, params, nil, &orderResponse, true)
+	err = c.doEnvelope(ctx, http.MethodPost, URIPlaceOrder, params, nil, &orderResponse, true)
 	return orderResponse, err
 }
 
+// PlaceResult is the result of placing an order.
+type PlaceResult struct {
+	OrderID  string
+	Response OrderResponse
+	Err      error
+}
+
+// PlaceOrders places the orders concurrently within the rate limits, e.g. the
+// legs of a multi-leg entry, and returns the result of every order in the order
+// of the orders.
+func (c *Client) PlaceOrders(orders []OrderParams) []PlaceResult {
+	return c.PlaceOrdersCtx(context.Background(), orders)
+}
+
+// PlaceOrdersCtx is PlaceOrders with a context for the requests.
+func (c *Client) PlaceOrdersCtx(ctx context.Context, orders []OrderParams) []PlaceResult {
+	results := make([]PlaceResult, len(orders))
+	var wg sync.WaitGroup
+	for i, orderParams := range orders {
+		wg.Add(1)
+		go func(i int, orderParams OrderParams) {
+			defer wg.Done()
+			response, err := c.PlaceOrderCtx(ctx, orderParams)
+			results[i] = PlaceResult{OrderID: response.OrderID, Response: response, Err: err}
+		}(i, orderParams)
+	}
+	wg.Wait()
+	return results
+}
+
 // ModifyOrder for modifying an order.
 func (c *Client) ModifyOrder(modifyOrderParams ModifyOrderParams) (OrderResponse, error) {
+	return c.ModifyOrderCtx(context.Background(), modifyOrderParams)
+}
+
+// ModifyOrderCtx is ModifyOrder with a context for the request.
+func (c *Client) ModifyOrderCtx(ctx context.Context, modifyOrderParams ModifyOrderParams) (OrderResponse, error) {
 	var (
 		orderResponse OrderResponse
 		params        map[string]interface{}
 		err           error
 	)
 
+	if book := c.paperBook(); book != nil {
+		return book.modify(modifyOrderParams)
+	}
+
 	params = structToMap(modifyOrderParams, "json")
 
-	err = c.doEnvelope(http.MethodPost, URIModifyOrder, params, nil, &orderResponse, true)
+	err = c.doEnvelope(ctx, http.MethodPost, URIModifyOrder, params, nil, &orderResponse, true)
 	return orderResponse, err
 }
 
+// ModifyOrderPartial modifies an order with only the changes given, keeping the
+// other fields of the order as they are in the order book.
+func (c *Client) ModifyOrderPartial(orderID string, changes OrderChanges) (OrderResponse, error) {
+	return c.ModifyOrderPartialCtx(context.Background(), orderID, changes)
+}
+
+// ModifyOrderPartialCtx is ModifyOrderPartial with a context for the requests.
+func (c *Client) ModifyOrderPartialCtx(ctx context.Context, orderID string, changes OrderChanges) (OrderResponse, error) {
+	orders, err := c.GetOrderBookCtx(ctx)
+	if err != nil {
+		return OrderResponse{}, err
+	}
+
+	for _, order := range orders {
+		if order.OrderID == orderID {
+			return c.ModifyOrderCtx(ctx, order.modify(changes))
+		}
+	}
+	return OrderResponse{}, NewError(InputError, "order "+orderID+" not found in the order book", nil)
+}
+
+// modify returns the params modifying the order with the changes.
+func (order Order) modify(changes OrderChanges) ModifyOrderParams {
+	modifyOrderParams := ModifyOrderParams{
+		Variety:       Variety(order.Variety),
+		OrderID:       order.OrderID,
+		OrderType:     OrderType(order.OrderType),
+		ProductType:   ProductType(order.ProductType),
+		Duration:      Duration(order.Duration),
+		Price:         order.Price,
+		Quantity:      order.Quantity,
+		TradingSymbol: order.TradingSymbol,
+		SymbolToken:   order.SymbolToken,
+		Exchange:      Exchange(order.Exchange),
+		TriggerPrice:  order.TriggerPrice,
+	}
+	if modifyOrderParams.Variety == "" {
+		modifyOrderParams.Variety = VarietyNormal
+	}
+	if changes.OrderType != "" {
+		modifyOrderParams.OrderType = changes.OrderType
+	}
+	if changes.ProductType != "" {
+		modifyOrderParams.ProductType = changes.ProductType
+	}
+	if changes.Duration != "" {
+		modifyOrderParams.Duration = changes.Duration
+	}
+	if changes.Price != "" {
+		modifyOrderParams.Price = changes.Price
+	}
+	if changes.Quantity != "" {
+		modifyOrderParams.Quantity = changes.Quantity
+	}
+	if changes.TriggerPrice != "" {
+		modifyOrderParams.TriggerPrice = changes.TriggerPrice
+	}
+	return modifyOrderParams
+}
+
 // CancelOrder for cancellation of an order.
 func (c *Client) CancelOrder(variety string, orderid string) (OrderResponse, error) {
+	return c.CancelOrderCtx(context.Background(), variety, orderid)
+}
+
+// CancelOrderCtx is CancelOrder with a context for the request.
+func (c *Client) CancelOrderCtx(ctx context.Context, variety string, orderid string) (OrderResponse, error) {
 	var (
 		orderResponse OrderResponse
 		err           error
 	)
 
+	if book := c.paperBook(); book != nil {
+		return book.cancel(orderid)
+	}
+
 	params := make(map[string]interface{})
 	params["variety"] = variety
 	params["orderid"] = orderid
 
-	err = c.doEnvelope(http.MethodPost, URICancelOrder, params, nil, &orderResponse, true)
+	err = c.doEnvelope(ctx, http.MethodPost, URICancelOrder, params, nil, &orderResponse, true)
 	return orderResponse, err
 }
 
+// OrderFilter selects orders by their trading symbol, order tag and variety.
+// Empty fields match any order.
+type OrderFilter struct {
+	TradingSymbol string
+	OrderTag      string
+	Variety       Variety
+}
+
+// CancelResult is the result of cancelling an order.
+type CancelResult struct {
+	OrderID  string
+	Response OrderResponse
+	Err      error
+}
+
+// pendingStatuses are the statuses of the orders which can be cancelled.
+var pendingStatuses = []string{"open", "open pending", "trigger pending", "modify pending", "after market order req received"}
+
+// Pending reports whether the order is open or waiting for its trigger.
+func (order Order) Pending() bool {
+	for _, status := range pendingStatuses {
+		if strings.EqualFold(order.OrderStatus, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the order matches the filter.
+func (filter OrderFilter) match(order Order) bool {
+	return (filter.TradingSymbol == "" || order.TradingSymbol == filter.TradingSymbol) &&
+		(filter.OrderTag == "" || order.OrderTag == filter.OrderTag) &&
+		(filter.Variety == "" || Variety(order.Variety) == filter.Variety)
+}
+
+// CancelAllOrders cancels the pending orders of the order book matching the
+// filter, concurrently within the rate limits, and returns the result of every
+// cancellation.
+func (c *Client) CancelAllOrders(filter OrderFilter) ([]CancelResult, error) {
+	return c.CancelAllOrdersCtx(context.Background(), filter)
+}
+
+// CancelAllOrdersCtx is CancelAllOrders with a context for the requests.
+func (c *Client) CancelAllOrdersCtx(ctx context.Context, filter OrderFilter) ([]CancelResult, error) {
+	orders, err := c.GetOrderBookCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending Orders
+	for _, order := range orders {
+		if order.Pending() && filter.match(order) {
+			pending = append(pending, order)
+		}
+	}
+
+	results := make([]CancelResult, len(pending))
+	var wg sync.WaitGroup
+	for i, order := range pending {
+		wg.Add(1)
+		go func(i int, order Order) {
+			defer wg.Done()
+			variety := order.Variety
+			if variety == "" {
+				variety = string(VarietyNormal)
+			}
+			response, err := c.CancelOrderCtx(ctx, variety, order.OrderID)
+			results[i] = CancelResult{OrderID: order.OrderID, Response: response, Err: err}
+		}(i, order)
+	}
+	wg.Wait()
+	return results, nil
+}
+
 // GetPositions gets user positions.
 func (c *Client) GetPositions() (Positions, error) {
+	return c.GetPositionsCtx(context.Background())
+}
+
+// GetPositionsCtx is GetPositions with a context for the request.
+func (c *Client) GetPositionsCtx(ctx context.Context) (Positions, error) {
 	var positions Positions
-	err := c.doEnvelope(http.MethodGet, URIGetPositions, nil, nil, &positions, true)
+	err := c.doEnvelope(ctx, http.MethodGet, URIGetPositions, nil, nil, &positions, true)
 	return positions, err
 }
 
 // GetTradeBook gets user trades.
 func (c *Client) GetTradeBook() (Trades, error) {
+	return c.GetTradeBookCtx(context.Background())
+}
+
+// GetTradeBookCtx is GetTradeBook with a context for the request.
+func (c *Client) GetTradeBookCtx(ctx context.Context) (Trades, error) {
 	var trades Trades
-	err := c.doEnvelope(http.MethodGet, URIGetTradeBook, nil, nil, &trades, true)
+	err := c.doEnvelope(ctx, http.MethodGet, URIGetTradeBook, nil, nil, &trades, true)
 	return trades, err
 }
 
 // ConvertPosition converts position's product type.
 func (c *Client) ConvertPosition(convertPositionParams ConvertPositionParams) error {
+	return c.ConvertPositionCtx(context.Background(), convertPositionParams)
+}
+
+// ConvertPositionCtx is ConvertPosition with a context for the request.
+func (c *Client) ConvertPositionCtx(ctx context.Context, convertPositionParams ConvertPositionParams) error {
 	var (
 		params map[string]interface{}
 		err    error
@@ -234,6 +570,6 @@ func (c *Client) ConvertPosition(convertPositionParams ConvertPositionParams) er
 
 	params = structToMap(convertPositionParams, "json")
 
-	err = c.doEnvelope(http.MethodPost, URIConvertPosition, params, nil, nil, true)
+	err = c.doEnvelope(ctx, http.MethodPost, URIConvertPosition, params, nil, nil, true)
 	return err
 }
@@ -1,7 +1,16 @@
 package smartapigo
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
 )
 
 func (ts *TestSuite) TestGetOrders(t *testing.T) {
@@ -17,6 +26,18 @@ func (ts *TestSuite) TestGetOrders(t *testing.T) {
 	}
 }
 
+func (ts *TestSuite) TestGetOrdersByTag(t *testing.T) {
+	t.Parallel()
+	orders, err := ts.TestConnect.GetOrdersByTag("strat-1")
+	if err != nil || len(orders) != 1 || orders[0].OrderID != "201020000000080" {
+		t.Errorf("Unexpected tagged orders %+v. %v", orders, err)
+	}
+	orders, err = ts.TestConnect.GetOrdersByTag("strat-2")
+	if err != nil || len(orders) != 0 {
+		t.Errorf("Expected no orders for another tag, got %+v. %v", orders, err)
+	}
+}
+
 func (ts *TestSuite) TestGetTrades(t *testing.T) {
 	t.Parallel()
 	trades, err := ts.TestConnect.GetTradeBook()
@@ -32,7 +53,7 @@ func (ts *TestSuite) TestGetTrades(t *testing.T) {
 
 func (ts *TestSuite) TestPlaceOrder(t *testing.T) {
 	t.Parallel()
-	params := OrderParams{"NORMAL", "SBIN-EQ", "3045", "BUY", "NSE", "LIMIT", "INTRADAY", "DAY", "19500", "0", "0", "1"}
+	params := OrderParams{Variety: "NORMAL", TradingSymbol: "SBIN-EQ", SymbolToken: "3045", TransactionType: "BUY", Exchange: "NSE", OrderType: "LIMIT", ProductType: "INTRADAY", Duration: "DAY", Price: "19500", SquareOff: "0", StopLoss: "0", Quantity: "1"}
 	orderResponse, err := ts.TestConnect.PlaceOrder(params)
 	if err != nil {
 		t.Errorf("Error while placing order. %v", err)
@@ -44,7 +65,7 @@ func (ts *TestSuite) TestPlaceOrder(t *testing.T) {
 
 func (ts *TestSuite) TestModifyOrder(t *testing.T) {
 	t.Parallel()
-	params := ModifyOrderParams{"NORMAL", "test", "LIMIT", "INTRADAY", "DAY", "19400", "1","SBI-EQ","3045","NSE"}
+	params := ModifyOrderParams{Variety: "NORMAL", OrderID: "test", OrderType: "LIMIT", ProductType: "INTRADAY", Duration: "DAY", Price: "19400", Quantity: "1", TradingSymbol: "SBI-EQ", SymbolToken: "3045", Exchange: "NSE"}
 	orderResponse, err := ts.TestConnect.ModifyOrder( params)
 	if err != nil {
 		t.Errorf("Error while updating order. %v", err)
@@ -88,4 +109,134 @@ func (ts *TestSuite) TestConvertPosition(t *testing.T) {
 		t.Errorf("Error while fetching positions. %v", err)
 	}
 
-}
\ No newline at end of file
+}
+func TestGetOrderBookCtx(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetOrderBook, func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetOrderBookCtx(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the request to time out, got %v", err)
+	}
+}
+
+func TestCancelAllOrders(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetOrderBook, httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":[
+		{"variety":"NORMAL","tradingsymbol":"SBIN-EQ","orderid":"1","orderstatus":"open","ordertag":"strat-1"},
+		{"variety":"STOPLOSS","tradingsymbol":"SBIN-EQ","orderid":"2","orderstatus":"trigger pending","ordertag":"strat-1"},
+		{"variety":"NORMAL","tradingsymbol":"SBIN-EQ","orderid":"3","orderstatus":"complete","ordertag":"strat-1"},
+		{"variety":"NORMAL","tradingsymbol":"INFY-EQ","orderid":"4","orderstatus":"open","ordertag":"strat-1"},
+		{"variety":"NORMAL","tradingsymbol":"SBIN-EQ","orderid":"5","orderstatus":"open","ordertag":"strat-2"}
+	]}`))
+
+	var (
+		mu        sync.Mutex
+		cancelled = map[string]string{}
+	)
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URICancelOrder, func(req *http.Request) (*http.Response, error) {
+		var params map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		cancelled[params["orderid"]] = params["variety"]
+		mu.Unlock()
+		if params["orderid"] == "2" {
+			return httpmock.NewStringResponse(200, `{"status":false,"message":"Order already executed","errorcode":"AB4008","data":null}`), nil
+		}
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"orderid":"`+params["orderid"]+`"}}`), nil
+	})
+
+	results, err := client.CancelAllOrders(OrderFilter{TradingSymbol: "SBIN-EQ", OrderTag: "strat-1"})
+	if err != nil {
+		t.Fatalf("Error while cancelling orders. %v", err)
+	}
+	if len(results) != 2 || results[0].OrderID != "1" || results[0].Err != nil || results[0].Response.OrderID != "1" ||
+		results[1].OrderID != "2" || results[1].Err == nil {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	if len(cancelled) != 2 || cancelled["1"] != "NORMAL" || cancelled["2"] != "STOPLOSS" {
+		t.Errorf("Unexpected cancelled orders %v", cancelled)
+	}
+}
+
+func TestPlaceOrders(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIPlaceOrder, func(req *http.Request) (*http.Response, error) {
+		var params map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		if params["tradingsymbol"] == "UNKNOWN-EQ" {
+			return httpmock.NewStringResponse(200, `{"status":false,"message":"Symbol Not Found","errorcode":"AB1009","data":null}`), nil
+		}
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"script":"`+params["tradingsymbol"]+`","orderid":"`+params["symboltoken"]+`"}}`), nil
+	})
+
+	leg := func(tradingSymbol, symbolToken string) OrderParams {
+		return OrderParams{Variety: VarietyNormal, TradingSymbol: tradingSymbol, SymbolToken: symbolToken, TransactionType: TransactionBuy,
+			Exchange: NFO, OrderType: OrderTypeMarket, ProductType: ProductCarryForward, Duration: DurationDay, Quantity: "50"}
+	}
+	results := client.PlaceOrders([]OrderParams{leg("NIFTY-CE", "1"), leg("UNKNOWN-EQ", "2"), leg("NIFTY-PE", "3")})
+	if len(results) != 3 || results[0].OrderID != "1" || results[0].Err != nil || results[0].Response.Script != "NIFTY-CE" ||
+		!errors.Is(results[1].Err, ErrOrderRejected) || results[2].OrderID != "3" || results[2].Err != nil {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
+
+func TestPlaceOrderBracket(t *testing.T) {
+	client, transport := newMockClient()
+	var params map[string]string
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIPlaceOrder, func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"script":"SBIN-EQ","orderid":"1"}}`), nil
+	})
+
+	_, err := client.PlaceOrder(OrderParams{Variety: VarietyRobo, TradingSymbol: "SBIN-EQ", SymbolToken: "3045", TransactionType: TransactionBuy,
+		Exchange: NSE, OrderType: OrderTypeLimit, ProductType: ProductBracket, Duration: DurationDay, Price: "455", Quantity: "1",
+		SquareOff: "5", StopLoss: "3", TrailingStopLoss: "1"})
+	if err != nil {
+		t.Fatalf("Error while placing a bracket order. %v", err)
+	}
+	if params["squareoff"] != "5" || params["stoploss"] != "3" || params["trailingstoploss"] != "1" {
+		t.Errorf("Unexpected bracket params %v", params)
+	}
+}
+
+func TestModifyOrderPartial(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetOrderBook, httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":[
+		{"variety":"STOPLOSS","ordertype":"STOPLOSS_LIMIT","producttype":"INTRADAY","duration":"DAY","price":"455","triggerprice":"454","quantity":"10","tradingsymbol":"SBIN-EQ","symboltoken":"3045","exchange":"NSE","orderid":"1","orderstatus":"trigger pending"}
+	]}`))
+
+	var params map[string]string
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIModifyOrder, func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"orderid":"1"}}`), nil
+	})
+
+	orderResponse, err := client.ModifyOrderPartial("1", OrderChanges{Price: "456"})
+	if err != nil || orderResponse.OrderID != "1" {
+		t.Fatalf("Unexpected order response %+v. %v", orderResponse, err)
+	}
+	expected := map[string]string{
+		"variety": "STOPLOSS", "orderid": "1", "ordertype": "STOPLOSS_LIMIT", "producttype": "INTRADAY", "duration": "DAY",
+		"price": "456", "quantity": "10", "tradingsymbol": "SBIN-EQ", "symboltoken": "3045", "exchange": "NSE", "triggerprice": "454",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Unexpected modify params %v", params)
+	}
+
+	if _, err := client.ModifyOrderPartial("2", OrderChanges{Price: "456"}); err == nil {
+		t.Errorf("Expected an error for an unknown order")
+	}
+}
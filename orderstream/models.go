@@ -0,0 +1,104 @@
+package orderstream
+
+import "strconv"
+
+// OrderStatusCode represents the "order-status" code sent with every order update.
+type OrderStatusCode string
+
+const (
+	// StatusConnected is sent once right after the connection is established.
+	StatusConnected OrderStatusCode = "AB00"
+	// StatusOpen is sent when an order is open at the exchange.
+	StatusOpen OrderStatusCode = "AB01"
+	// StatusCancelled is sent when an order is cancelled.
+	StatusCancelled OrderStatusCode = "AB02"
+	// StatusRejected is sent when an order is rejected.
+	StatusRejected OrderStatusCode = "AB03"
+	// StatusModified is sent when an order is modified.
+	StatusModified OrderStatusCode = "AB04"
+	// StatusComplete is sent when an order is completely filled.
+	StatusComplete OrderStatusCode = "AB05"
+	// StatusAfterMarketOrderReceived is sent when an AMO is received.
+	StatusAfterMarketOrderReceived OrderStatusCode = "AB06"
+	// StatusAfterMarketOrderCancelled is sent when an AMO is cancelled.
+	StatusAfterMarketOrderCancelled OrderStatusCode = "AB07"
+	// StatusAfterMarketOrderModified is sent when an AMO is modified.
+	StatusAfterMarketOrderModified OrderStatusCode = "AB08"
+	// StatusOpenPending is sent when an order is pending at the exchange.
+	StatusOpenPending OrderStatusCode = "AB09"
+	// StatusTriggerPending is sent when a stop loss order is waiting for its trigger.
+	StatusTriggerPending OrderStatusCode = "AB10"
+	// StatusModifyPending is sent when a modification is pending at the exchange.
+	StatusModifyPending OrderStatusCode = "AB11"
+)
+
+// OrderUpdate represents a single message received on the order status stream.
+type OrderUpdate struct {
+	UserID       string          `json:"user-id"`
+	StatusCode   string          `json:"status-code"`
+	OrderStatus  OrderStatusCode `json:"order-status"`
+	ErrorMessage string          `json:"error-message"`
+	OrderData    OrderData       `json:"orderData"`
+
+	// PreviousOrderStatus is the order status of the previous update of the
+	// order received on the stream, empty for its first update.
+	PreviousOrderStatus string `json:"-"`
+}
+
+// StatusChanged reports whether the update changed the status of the order.
+func (update OrderUpdate) StatusChanged() bool {
+	return update.OrderData.OrderStatus != update.PreviousOrderStatus
+}
+
+// OrderData represents the order details carried by an order update.
+type OrderData struct {
+	Variety                 string  `json:"variety"`
+	OrderType               string  `json:"ordertype"`
+	OrderTag                string  `json:"ordertag"`
+	ProductType             string  `json:"producttype"`
+	Price                   float64 `json:"price"`
+	TriggerPrice            float64 `json:"triggerprice"`
+	Quantity                string  `json:"quantity"`
+	DisclosedQuantity       string  `json:"disclosedquantity"`
+	Duration                string  `json:"duration"`
+	SquareOff               float64 `json:"squareoff"`
+	StopLoss                float64 `json:"stoploss"`
+	TrailingStopLoss        float64 `json:"trailingstoploss"`
+	TradingSymbol           string  `json:"tradingsymbol"`
+	TransactionType         string  `json:"transactiontype"`
+	Exchange                string  `json:"exchange"`
+	SymbolToken             string  `json:"symboltoken"`
+	InstrumentType          string  `json:"instrumenttype"`
+	StrikePrice             float64 `json:"strikeprice"`
+	OptionType              string  `json:"optiontype"`
+	ExpiryDate              string  `json:"expirydate"`
+	LotSize                 string  `json:"lotsize"`
+	CancelSize              string  `json:"cancelsize"`
+	AveragePrice            float64 `json:"averageprice"`
+	FilledShares            string  `json:"filledshares"`
+	UnfilledShares          string  `json:"unfilledshares"`
+	OrderID                 string  `json:"orderid"`
+	Text                    string  `json:"text"`
+	Status                  string  `json:"status"`
+	OrderStatus             string  `json:"orderstatus"`
+	UpdateTime              string  `json:"updatetime"`
+	ExchangeTime            string  `json:"exchtime"`
+	ExchangeOrderUpdateTime string  `json:"exchorderupdatetime"`
+	FillID                  string  `json:"fillid"`
+	FillTime                string  `json:"filltime"`
+	ParentOrderID           string  `json:"parentorderid"`
+	ClientCode              string  `json:"clientcode"`
+	UniqueOrderID           string  `json:"uniqueorderid"`
+}
+
+// FilledQuantity returns the quantity of the order filled so far.
+func (data OrderData) FilledQuantity() int {
+	filled, _ := strconv.Atoi(data.FilledShares)
+	return filled
+}
+
+// UnfilledQuantity returns the quantity of the order pending to be filled.
+func (data OrderData) UnfilledQuantity() int {
+	unfilled, _ := strconv.Atoi(data.UnfilledShares)
+	return unfilled
+}
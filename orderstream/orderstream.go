@@ -0,0 +1,384 @@
+// Package orderstream implements a client for the SmartAPI order status websocket
+// which pushes real-time order and trade updates for the logged in user.
+package orderstream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Client represents an order status stream connection.
+type Client struct {
+	Conn                *websocket.Conn
+	url                 url.URL
+	accessToken         string
+	tokenProvider       TokenProvider
+	statuses            map[string]string
+	callbacks           callbacks
+	autoReconnect       bool
+	reconnectMaxRetries int
+	reconnectMaxDelay   time.Duration
+	connectTimeout      time.Duration
+	reconnectAttempt    int
+	closing             bool
+	closed              chan struct{}
+	tracer              Tracer
+	span                Span
+	mu                  sync.Mutex
+}
+
+// callbacks represents callbacks available in the order stream.
+type callbacks struct {
+	onOrderUpdate func(OrderUpdate)
+	onNoReconnect func(int)
+	onReconnect   func(int, time.Duration)
+	onConnect     func()
+	onClose       func(int, string)
+	onError       func(error)
+}
+
+const (
+	// Default maximum number of reconnect attempts
+	defaultReconnectMaxAttempts = 300
+	// Auto reconnect min delay. Reconnect delay can't be less than this.
+	reconnectMinDelay time.Duration = 5000 * time.Millisecond
+	// Default auto reconnect delay to be used for auto reconnection.
+	defaultReconnectMaxDelay time.Duration = 60000 * time.Millisecond
+	// Connect timeout for initial server handshake.
+	defaultConnectTimeout time.Duration = 7000 * time.Millisecond
+	// Interval in which the "ping" heartbeat is sent to keep the connection alive.
+	pingInterval time.Duration = 10000 * time.Millisecond
+)
+
+var (
+	// Default order status stream url.
+	streamURL = url.URL{Scheme: "wss", Host: "tns.angelone.in", Path: "/smart-order-update"}
+)
+
+// New creates a new order stream instance for the given JWT access token.
+func New(accessToken string) *Client {
+	return &Client{
+		accessToken:         accessToken,
+		url:                 streamURL,
+		autoReconnect:       true,
+		reconnectMaxDelay:   defaultReconnectMaxDelay,
+		reconnectMaxRetries: defaultReconnectMaxAttempts,
+		connectTimeout:      defaultConnectTimeout,
+		statuses:            make(map[string]string),
+		closed:              make(chan struct{}),
+		tracer:              NoopTracer{},
+	}
+}
+
+// SetRootURL sets order stream root url.
+func (c *Client) SetRootURL(u url.URL) {
+	c.url = u
+}
+
+// SetAccessToken sets the JWT access token used to authenticate the stream.
+func (c *Client) SetAccessToken(accessToken string) {
+	c.accessToken = accessToken
+}
+
+// SetConnectTimeout sets default timeout for initial connect handshake
+func (c *Client) SetConnectTimeout(val time.Duration) {
+	c.connectTimeout = val
+}
+
+// SetAutoReconnect enable/disable auto reconnect.
+func (c *Client) SetAutoReconnect(val bool) {
+	c.autoReconnect = val
+}
+
+// SetReconnectMaxDelay sets maximum auto reconnect delay.
+func (c *Client) SetReconnectMaxDelay(val time.Duration) error {
+	if val < reconnectMinDelay {
+		return fmt.Errorf("ReconnectMaxDelay can't be less than %fms", reconnectMinDelay.Seconds()*1000)
+	}
+
+	c.reconnectMaxDelay = val
+	return nil
+}
+
+// SetReconnectMaxRetries sets maximum reconnect attempts.
+func (c *Client) SetReconnectMaxRetries(val int) {
+	c.reconnectMaxRetries = val
+}
+
+// SetOnOrderUpdate sets the callback invoked for every order update.
+func (c *Client) SetOnOrderUpdate(f func(update OrderUpdate)) {
+	c.callbacks.onOrderUpdate = f
+}
+
+// SetOnConnect sets the callback invoked once the connection is established.
+func (c *Client) SetOnConnect(f func()) {
+	c.callbacks.onConnect = f
+}
+
+// SetOnError sets the callback invoked on any error.
+func (c *Client) SetOnError(f func(err error)) {
+	c.callbacks.onError = f
+}
+
+// SetOnClose sets the callback invoked when the server closes the connection.
+func (c *Client) SetOnClose(f func(code int, reason string)) {
+	c.callbacks.onClose = f
+}
+
+// SetOnReconnect sets the callback invoked before every reconnect attempt.
+func (c *Client) SetOnReconnect(f func(attempt int, delay time.Duration)) {
+	c.callbacks.onReconnect = f
+}
+
+// SetOnNoReconnect sets the callback invoked when reconnect attempts are exhausted.
+func (c *Client) SetOnNoReconnect(f func(attempt int)) {
+	c.callbacks.onNoReconnect = f
+}
+
+// Connect starts the connection to the order stream. Since its blocking its recommended to use it in go routine.
+func (c *Client) Connect() {
+	c.startSpan()
+	defer c.endSpan()
+
+	for {
+		if c.isClosing() {
+			return
+		}
+
+		// If reconnect attempt exceeds max then close the loop
+		if c.reconnectAttempt > c.reconnectMaxRetries {
+			c.triggerNoReconnect(c.reconnectAttempt)
+			return
+		}
+
+		// If its a reconnect then wait exponentially based on reconnect attempt
+		if c.reconnectAttempt > 0 {
+			nextDelay := time.Duration(math.Pow(2, float64(c.reconnectAttempt))) * time.Second
+			if nextDelay > c.reconnectMaxDelay {
+				nextDelay = c.reconnectMaxDelay
+			}
+
+			c.triggerReconnect(c.reconnectAttempt, nextDelay)
+			if !c.waitReconnect(nextDelay) {
+				return
+			}
+
+			// Close the previous connection if exists
+			if c.Conn != nil {
+				c.Conn.Close()
+			}
+		}
+
+		if err := c.refreshAccessToken(); err != nil {
+			c.triggerError(err)
+			// A session which has ended can't be renewed by retrying.
+			if c.autoReconnect && !errors.Is(err, ErrNoSession) {
+				c.reconnectAttempt++
+				continue
+			}
+			return
+		}
+
+		d := websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: c.connectTimeout,
+		}
+
+		headers := http.Header{}
+		c.mu.Lock()
+		headers.Add("Authorization", "Bearer "+c.accessToken)
+		c.mu.Unlock()
+
+		if c.isClosing() {
+			return
+		}
+		conn, _, err := d.Dial(c.url.String(), headers)
+		if err != nil {
+			c.triggerError(err)
+			// If auto reconnect is enabled then try reconnecting else return error
+			if c.autoReconnect {
+				c.reconnectAttempt++
+				continue
+			}
+			return
+		}
+
+		// Assign the current connection to the instance, unless the stream was
+		// closed while connecting.
+		c.mu.Lock()
+		if c.closing {
+			c.mu.Unlock()
+			conn.Close()
+			return
+		}
+		c.Conn = conn
+		c.mu.Unlock()
+
+		// Trigger connect callback.
+		c.triggerConnect()
+
+		// Reset auto reconnect vars
+		c.reconnectAttempt = 0
+
+		// Set on close handler
+		c.Conn.SetCloseHandler(c.handleClose)
+
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+
+		// Receive order updates in a go routine.
+		wg.Add(2)
+		go c.readMessage(&wg, done)
+		go c.ping(&wg, done)
+
+		// Wait for go routines to finish before doing next reconnect
+		wg.Wait()
+		c.Conn.Close()
+
+		if !c.autoReconnect || c.isClosing() {
+			return
+		}
+		c.reconnectAttempt++
+	}
+}
+
+// Close tries to close the connection gracefully and stops any further reconnects.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closing {
+		close(c.closed)
+	}
+	c.closing = true
+	if c.Conn == nil {
+		return nil
+	}
+	return c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+// waitReconnect waits for delay before the next reconnect attempt. It returns
+// false if the stream is closed in the meantime.
+func (c *Client) waitReconnect(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-c.closed:
+		return false
+	case <-timer.C:
+		return !c.isClosing()
+	}
+}
+
+func (c *Client) isClosing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closing
+}
+
+func (c *Client) handleClose(code int, reason string) error {
+	c.triggerClose(code, reason)
+	return nil
+}
+
+// Trigger callback methods
+func (c *Client) triggerError(err error) {
+	if c.callbacks.onError != nil {
+		c.callbacks.onError(err)
+	}
+}
+
+func (c *Client) triggerClose(code int, reason string) {
+	if c.callbacks.onClose != nil {
+		c.callbacks.onClose(code, reason)
+	}
+}
+
+func (c *Client) triggerConnect() {
+	c.currentSpan().Connect()
+	if c.callbacks.onConnect != nil {
+		c.callbacks.onConnect()
+	}
+}
+
+func (c *Client) triggerReconnect(attempt int, delay time.Duration) {
+	c.currentSpan().Reconnect(attempt, delay)
+	if c.callbacks.onReconnect != nil {
+		c.callbacks.onReconnect(attempt, delay)
+	}
+}
+
+func (c *Client) triggerNoReconnect(attempt int) {
+	if c.callbacks.onNoReconnect != nil {
+		c.callbacks.onNoReconnect(attempt)
+	}
+}
+
+func (c *Client) triggerOrderUpdate(update OrderUpdate) {
+	if c.callbacks.onOrderUpdate != nil {
+		c.callbacks.onOrderUpdate(update)
+	}
+}
+
+// ping sends the "ping" heartbeat periodically until done is closed.
+func (c *Client) ping(wg *sync.WaitGroup, done chan struct{}) {
+	defer wg.Done()
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			err := c.Conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+			c.mu.Unlock()
+			if err != nil {
+				c.triggerError(fmt.Errorf("Error sending ping: %v", err))
+			}
+		}
+	}
+}
+
+// readMessage reads the order updates in a loop.
+func (c *Client) readMessage(wg *sync.WaitGroup, done chan struct{}) {
+	defer wg.Done()
+	defer close(done)
+
+	for {
+		_, msg, err := c.Conn.ReadMessage()
+		if err != nil {
+			if !c.isClosing() {
+				c.triggerError(fmt.Errorf("Error reading data: %v", err))
+			}
+			return
+		}
+
+		if string(msg) == "pong" {
+			continue
+		}
+
+		var update OrderUpdate
+		if err := json.Unmarshal(msg, &update); err != nil {
+			c.triggerError(err)
+			continue
+		}
+
+		// Keep the last status of every order across reconnects.
+		if orderID := update.OrderData.OrderID; orderID != "" {
+			update.PreviousOrderStatus = c.statuses[orderID]
+			c.statuses[orderID] = update.OrderData.OrderStatus
+		}
+
+		c.triggerOrderUpdate(update)
+	}
+}
@@ -0,0 +1,158 @@
+package orderstream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const mockOrderUpdate = `{"user-id":"test","status-code":"200","order-status":"AB05","error-message":"","orderData":{"variety":"NORMAL","ordertype":"LIMIT","producttype":"DELIVERY","price":551.0,"quantity":"1","tradingsymbol":"SBIN-EQ","transactiontype":"BUY","exchange":"NSE","symboltoken":"3045","averageprice":550.5,"filledshares":"1","unfilledshares":"0","orderid":"201020000000080","status":"complete","orderstatus":"complete"}}`
+
+func TestOrderUpdates(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(mockOrderUpdate))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+
+	client := New("test_token")
+	client.SetRootURL(*u)
+	client.SetAutoReconnect(false)
+
+	updates := make(chan OrderUpdate, 1)
+	client.SetOnOrderUpdate(func(update OrderUpdate) {
+		updates <- update
+	})
+	client.SetOnError(func(err error) {
+		if !strings.Contains(err.Error(), "close") {
+			t.Errorf("Unexpected error on order stream. %v", err)
+		}
+	})
+
+	go client.Connect()
+
+	select {
+	case update := <-updates:
+		if update.OrderStatus != StatusComplete {
+			t.Errorf("Order status is not parsed properly. %v", update.OrderStatus)
+		}
+		if update.OrderData.OrderID != "201020000000080" || update.OrderData.AveragePrice != 550.5 {
+			t.Errorf("Order data is not parsed properly. %+v", update.OrderData)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for order update.")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Errorf("Error while closing order stream. %v", err)
+	}
+}
+
+func TestOrderStatusTransitions(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, msg := range []string{
+			`{"order-status":"AB01","orderData":{"orderid":"1","orderstatus":"open","filledshares":"0","unfilledshares":"10"}}`,
+			`{"order-status":"AB01","orderData":{"orderid":"1","orderstatus":"open","filledshares":"4","unfilledshares":"6"}}`,
+			`{"order-status":"AB05","orderData":{"orderid":"1","orderstatus":"complete","filledshares":"10","unfilledshares":"0"}}`,
+		} {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+
+	client := New("test_token")
+	client.SetRootURL(*u)
+	client.SetAutoReconnect(false)
+	updates := make(chan OrderUpdate, 3)
+	client.SetOnOrderUpdate(func(update OrderUpdate) {
+		updates <- update
+	})
+	go client.Connect()
+	defer client.Close()
+
+	expected := []struct {
+		previous string
+		changed  bool
+		filled   int
+	}{{"", true, 0}, {"open", false, 4}, {"open", true, 10}}
+	for i, e := range expected {
+		select {
+		case update := <-updates:
+			if update.PreviousOrderStatus != e.previous || update.StatusChanged() != e.changed || update.OrderData.FilledQuantity() != e.filled {
+				t.Errorf("Unexpected update %d %+v", i, update)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for order update.")
+		}
+	}
+}
+
+func TestCloseDuringReconnect(t *testing.T) {
+	var dials atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dials.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+
+	client := New("test_token")
+	client.SetRootURL(*u)
+	client.SetOnReconnect(func(attempt int, delay time.Duration) {
+		go client.Close()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		client.Connect()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Connect did not return after Close during the reconnect delay.")
+	}
+	if dials.Load() != 1 {
+		t.Errorf("Expected no dial after Close, got %d dials", dials.Load())
+	}
+}
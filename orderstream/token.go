@@ -0,0 +1,39 @@
+package orderstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoSession is returned by a token provider when there is no session to
+// authenticate the stream with, which stops reconnecting.
+var ErrNoSession = errors.New("no session")
+
+// TokenProvider returns the current JWT access token.
+type TokenProvider func(ctx context.Context) (accessToken string, err error)
+
+// SetTokenProvider sets the provider which is called before every connection attempt,
+// so that a long running stream picks up a renewed session once the access token expires.
+func (c *Client) SetTokenProvider(p TokenProvider) {
+	c.tokenProvider = p
+}
+
+// refreshAccessToken updates the access token from the token provider.
+func (c *Client) refreshAccessToken() error {
+	if c.tokenProvider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.connectTimeout)
+	defer cancel()
+	accessToken, err := c.tokenProvider(ctx)
+	if err != nil {
+		return fmt.Errorf("Error refreshing access token: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = accessToken
+	return nil
+}
@@ -0,0 +1,77 @@
+package orderstream
+
+import (
+	"time"
+)
+
+// Tracer traces the runs of Connect, e.g. with OpenTelemetry through the otel
+// package of the module.
+type Tracer interface {
+	// StartOrderStream is called when Connect starts with the address of the
+	// server and returns the span of the run.
+	StartOrderStream(address string) Span
+}
+
+// Span is the span of a run of Connect.
+type Span interface {
+	// Connect is called for every connection established.
+	Connect()
+	// Reconnect is called before every reconnect attempt with its delay.
+	Reconnect(attempt int, delay time.Duration)
+	// End is called once the run stops.
+	End()
+}
+
+// NoopTracer is a Tracer implementation which traces nothing.
+type NoopTracer struct{}
+
+// StartOrderStream implements Tracer.
+func (NoopTracer) StartOrderStream(string) Span {
+	return noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) Connect()                     {}
+func (noopSpan) Reconnect(int, time.Duration) {}
+func (noopSpan) End()                         {}
+
+// SetTracer sets the tracer of the stream, which traces every run of Connect
+// with its connects and reconnects. Nothing is traced by default.
+func (c *Client) SetTracer(t Tracer) {
+	if t == nil {
+		t = NoopTracer{}
+	}
+	c.tracer = t
+}
+
+// startSpan starts the span of a run of Connect.
+func (c *Client) startSpan() {
+	span := c.tracer.StartOrderStream(c.url.Host)
+
+	c.mu.Lock()
+	c.span = span
+	c.mu.Unlock()
+}
+
+// endSpan ends the span of a run of Connect.
+func (c *Client) endSpan() {
+	c.mu.Lock()
+	span := c.span
+	c.span = nil
+	c.mu.Unlock()
+
+	if span != nil {
+		span.End()
+	}
+}
+
+// currentSpan returns the span of the current run, if any.
+func (c *Client) currentSpan() Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.span == nil {
+		return noopSpan{}
+	}
+	return c.span
+}
@@ -0,0 +1,131 @@
+// Package otel traces the REST requests of a client, the ticker and the order
+// stream with OpenTelemetry.
+//
+//	tracer := otel.NewTracer(tracerProvider)
+//	client.SetTracer(tracer)
+//
+// Streams created from the client with NewOrderStream and
+// websocket.NewFromClient take the tracer of the client.
+package otel
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	smartapi "github.com/shammishailaj/smartapigo"
+	"github.com/shammishailaj/smartapigo/orderstream"
+	"github.com/shammishailaj/smartapigo/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// InstrumentationName is the name of the tracer of the spans.
+const InstrumentationName = "github.com/shammishailaj/smartapigo/otel"
+
+// Tracer records a span for every REST request with its endpoint, http status
+// and error code, and a span for every run of a ticker or an order stream with
+// an event for every connect, reconnect and subscription change.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates a tracer recording its spans with the tracer provider.
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	return &Tracer{tracer: tp.Tracer(InstrumentationName)}
+}
+
+// StartRequest implements smartapi.Tracer.
+func (t *Tracer) StartRequest(ctx context.Context, method, endpoint string) (context.Context, smartapi.RequestSpan) {
+	ctx, span := t.tracer.Start(ctx, endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("smartapi.endpoint", endpoint),
+			attribute.String("http.request.method", method),
+		))
+	return ctx, requestSpan{span}
+}
+
+// StartTicker implements websocket.Tracer.
+func (t *Tracer) StartTicker(ctx context.Context, address string) (context.Context, websocket.Span) {
+	ctx, span := t.tracer.Start(ctx, "smartapi.ticker",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("server.address", address)))
+	return ctx, streamSpan{span}
+}
+
+// StartOrderStream implements orderstream.Tracer.
+func (t *Tracer) StartOrderStream(address string) orderstream.Span {
+	_, span := t.tracer.Start(context.Background(), "smartapi.orderstream",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("server.address", address)))
+	return orderStreamSpan{streamSpan{span}}
+}
+
+// requestSpan is the span of a REST request.
+type requestSpan struct {
+	span trace.Span
+}
+
+func (s requestSpan) End(outcome smartapi.RequestOutcome) {
+	if outcome.RequestID != "" {
+		s.span.SetAttributes(attribute.String("smartapi.request_id", outcome.RequestID))
+	}
+	status := outcome.HTTPStatus
+	if err := outcome.Err; err != nil {
+		var apiErr smartapi.Error
+		if errors.As(err, &apiErr) {
+			if apiErr.Code != "" {
+				s.span.SetAttributes(attribute.String("smartapi.error_code", apiErr.Code))
+			}
+			if apiErr.HTTPStatus != 0 {
+				status = apiErr.HTTPStatus
+			}
+		}
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	if status != 0 {
+		s.span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+	s.span.End()
+}
+
+// streamSpan is the span of a run of a ticker.
+type streamSpan struct {
+	span trace.Span
+}
+
+func (s streamSpan) Connect() {
+	s.span.AddEvent("connect")
+}
+
+func (s streamSpan) Reconnect(attempt int, delay time.Duration) {
+	s.span.AddEvent("reconnect", trace.WithAttributes(attribute.Int("attempt", attempt), attribute.String("delay", delay.String())))
+}
+
+func (s streamSpan) Subscribe(scrips, subscribed int) {
+	s.span.AddEvent("subscribe", trace.WithAttributes(attribute.Int("scrips", scrips), attribute.Int("subscribed", subscribed)))
+}
+
+func (s streamSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// orderStreamSpan is the span of a run of an order stream.
+type orderStreamSpan struct {
+	streamSpan
+}
+
+func (s orderStreamSpan) End() {
+	s.streamSpan.End(nil)
+}
@@ -0,0 +1,102 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	smartapi "github.com/shammishailaj/smartapigo"
+	"github.com/shammishailaj/smartapigo/orderstream"
+	"github.com/shammishailaj/smartapigo/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// The tracer must be usable for the client and both streams.
+var (
+	_ smartapi.Tracer    = (*Tracer)(nil)
+	_ websocket.Tracer   = (*Tracer)(nil)
+	_ orderstream.Tracer = (*Tracer)(nil)
+)
+
+func newRecordingTracer() (*Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), recorder
+}
+
+func TestRequestSpans(t *testing.T) {
+	tracer, recorder := newRecordingTracer()
+
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewStringResponder(200, "127.0.0.1"))
+	client := smartapi.NewWithHTTPClient("test", "test", "test", &http.Client{Transport: transport})
+	transport.RegisterResponder(http.MethodGet, client.Environment().BaseURI+smartapi.URIGetOrderBook,
+		httpmock.NewStringResponder(http.StatusOK, `{"status":true,"message":"SUCCESS","errorcode":"","data":[]}`))
+	transport.RegisterResponder(http.MethodGet, client.Environment().BaseURI+smartapi.URIGetTradeBook,
+		httpmock.NewStringResponder(http.StatusOK, `{"status":false,"message":"Invalid Token","errorcode":"AG8001","data":null}`))
+	client.SetTracer(tracer)
+
+	if _, err := client.GetOrderBookCtx(smartapi.WithRequestID(context.Background(), "req-1")); err != nil {
+		t.Fatalf("Error while fetching order book. %v", err)
+	}
+	if _, err := client.GetTradeBook(); err == nil {
+		t.Fatalf("Expected an error for the trade book")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected a span per request, got %d", len(spans))
+	}
+	if span := spans[0]; span.Name() != smartapi.URIGetOrderBook || span.Status().Code == codes.Error ||
+		!hasAttribute(span.Attributes(), attribute.String("smartapi.endpoint", smartapi.URIGetOrderBook)) ||
+		!hasAttribute(span.Attributes(), attribute.String("smartapi.request_id", "req-1")) ||
+		!hasAttribute(span.Attributes(), attribute.Int("http.response.status_code", http.StatusOK)) {
+		t.Errorf("Unexpected span %s %v %v", span.Name(), span.Status(), span.Attributes())
+	}
+	if span := spans[1]; span.Status().Code != codes.Error ||
+		!hasAttribute(span.Attributes(), attribute.String("smartapi.error_code", "AG8001")) {
+		t.Errorf("Unexpected span %s %v %v", span.Name(), span.Status(), span.Attributes())
+	}
+}
+
+func TestStreamSpans(t *testing.T) {
+	tracer, recorder := newRecordingTracer()
+
+	_, ticker := tracer.StartTicker(context.Background(), "smartapisocket.angelone.in")
+	ticker.Connect()
+	ticker.Subscribe(2, 2)
+	ticker.Reconnect(1, 5*time.Second)
+	ticker.End(errors.New("connection lost"))
+
+	stream := tracer.StartOrderStream("tns.angelone.in")
+	stream.Connect()
+	stream.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected a span per run, got %d", len(spans))
+	}
+	if span := spans[0]; span.Name() != "smartapi.ticker" || span.Status().Code != codes.Error || len(span.Events()) != 4 ||
+		span.Events()[1].Name != "subscribe" || !hasAttribute(span.Events()[2].Attributes, attribute.Int("attempt", 1)) {
+		t.Errorf("Unexpected span %s %v %v", span.Name(), span.Status(), span.Events())
+	}
+	if span := spans[1]; span.Name() != "smartapi.orderstream" || span.Status().Code == codes.Error ||
+		len(span.Events()) != 1 || span.Events()[0].Name != "connect" ||
+		!hasAttribute(span.Attributes(), attribute.String("server.address", "tns.angelone.in")) {
+		t.Errorf("Unexpected span %s %v %v", span.Name(), span.Status(), span.Events())
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
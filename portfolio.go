@@ -1,6 +1,7 @@
 package smartapigo
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -26,7 +27,58 @@ type Holdings []Holding
 
 // GetHoldings gets a list of holdings.
 func (c *Client) GetHoldings() (Holdings, error) {
+	return c.GetHoldingsCtx(context.Background())
+}
+
+// GetHoldingsCtx is GetHoldings with a context for the request.
+func (c *Client) GetHoldingsCtx(ctx context.Context) (Holdings, error) {
 	var holdings Holdings
-	err := c.doEnvelope(http.MethodGet, URIGetHoldings, nil, nil, &holdings, true)
+	err := c.doEnvelope(ctx, http.MethodGet, URIGetHoldings, nil, nil, &holdings, true)
 	return holdings, err
-}
\ No newline at end of file
+}
+// HoldingDetail is an individual holding of the all holdings response.
+type HoldingDetail struct {
+	Tradingsymbol      string  `json:"tradingsymbol"`
+	Exchange           string  `json:"exchange"`
+	ISIN               string  `json:"isin"`
+	SymbolToken        string  `json:"symboltoken"`
+	Product            string  `json:"product"`
+	T1Quantity         float64 `json:"t1quantity"`
+	RealisedQuantity   float64 `json:"realisedquantity"`
+	Quantity           float64 `json:"quantity"`
+	AuthorisedQuantity float64 `json:"authorisedquantity"`
+	CollateralQuantity float64 `json:"collateralquantity"`
+	CollateralType     string  `json:"collateraltype"`
+	Haircut            float64 `json:"haircut"`
+	AveragePrice       float64 `json:"averageprice"`
+	Ltp                float64 `json:"ltp"`
+	Close              float64 `json:"close"`
+	ProfitAndLoss      float64 `json:"profitandloss"`
+	PnLPercentage      float64 `json:"pnlpercentage"`
+}
+
+// TotalHolding is the summary of all holdings.
+type TotalHolding struct {
+	TotalHoldingValue  float64 `json:"totalholdingvalue"`
+	TotalInvestedValue float64 `json:"totalinvvalue"`
+	TotalProfitAndLoss float64 `json:"totalprofitandloss"`
+	TotalPnLPercentage float64 `json:"totalpnlpercentage"`
+}
+
+// AllHoldings represents All Holdings API response.
+type AllHoldings struct {
+	Holdings     []HoldingDetail `json:"holdings"`
+	TotalHolding TotalHolding    `json:"totalholding"`
+}
+
+// GetAllHoldings gets the holdings along with the totals of the portfolio.
+func (c *Client) GetAllHoldings() (AllHoldings, error) {
+	return c.GetAllHoldingsCtx(context.Background())
+}
+
+// GetAllHoldingsCtx is GetAllHoldings with a context for the request.
+func (c *Client) GetAllHoldingsCtx(ctx context.Context) (AllHoldings, error) {
+	var allHoldings AllHoldings
+	err := c.doEnvelope(ctx, http.MethodGet, URIGetAllHoldings, nil, nil, &allHoldings, true)
+	return allHoldings, err
+}
@@ -18,3 +18,19 @@ func (ts *TestSuite) TestGetHoldings(t *testing.T) {
 	}
 
 }
+
+func (ts *TestSuite) TestGetAllHoldings(t *testing.T) {
+	t.Parallel()
+	allHoldings, err := ts.TestConnect.GetAllHoldings()
+	if err != nil {
+		t.Errorf("Error while fetching all holdings. %v", err)
+	}
+
+	if len(allHoldings.Holdings) != 1 || allHoldings.Holdings[0].AveragePrice != 111.87 {
+		t.Errorf("Unexpected holdings %+v", allHoldings.Holdings)
+	}
+
+	if allHoldings.TotalHolding.TotalProfitAndLoss != 178.14 || allHoldings.TotalHolding.TotalPnLPercentage != 3.48 {
+		t.Errorf("Unexpected total holding %+v", allHoldings.TotalHolding)
+	}
+}
@@ -0,0 +1,87 @@
+package smartapigo
+
+import (
+	"strconv"
+)
+
+// LTPSource returns the last traded price of the instrument with the symbol
+// token on the exchange, e.g. from a websocket feed or GetMarketData.
+type LTPSource func(exchange, symbolToken string) (float64, bool)
+
+// number parses a numeric field of a response, returning zero for empty or
+// invalid values.
+func number(value string) float64 {
+	parsed, _ := strconv.ParseFloat(value, 64)
+	return parsed
+}
+
+// NetQuantity returns the net quantity of the position, including the quantity
+// carried forward, negative for short positions.
+func (position Position) NetQuantity() float64 {
+	return number(position.NetQty)
+}
+
+// DayQuantity returns the quantity bought less the quantity sold today.
+func (position Position) DayQuantity() float64 {
+	return number(position.BuyQuantity) - number(position.SellQuantity)
+}
+
+// multiplier returns the value of a price point of the position.
+func (position Position) multiplier() float64 {
+	if multiplier := number(position.Multiplier); multiplier > 0 {
+		return multiplier
+	}
+	return 1
+}
+
+// MTM returns the mark to market profit or loss of the position at the last
+// traded price: the value sold less the value bought plus the value of the net
+// quantity.
+func (position Position) MTM(ltp float64) float64 {
+	return number(position.TotalSellValue) - number(position.TotalBuyValue) + position.NetQuantity()*ltp*position.multiplier()
+}
+
+// Unrealized returns the profit or loss of the net quantity of the position at
+// the last traded price.
+func (position Position) Unrealized(ltp float64) float64 {
+	return position.NetQuantity() * (ltp - number(position.AverageNetPrice)) * position.multiplier()
+}
+
+// Net returns the positions with an open net quantity.
+func (positions Positions) Net() Positions {
+	var net Positions
+	for _, position := range positions {
+		if position.NetQuantity() != 0 {
+			net = append(net, position)
+		}
+	}
+	return net
+}
+
+// Day returns the positions traded today.
+func (positions Positions) Day() Positions {
+	var day Positions
+	for _, position := range positions {
+		if number(position.BuyQuantity) != 0 || number(position.SellQuantity) != 0 {
+			day = append(day, position)
+		}
+	}
+	return day
+}
+
+// MTM returns the total mark to market profit or loss of the positions at the
+// last traded prices of the source. Closed positions need no price.
+func (positions Positions) MTM(source LTPSource) (float64, error) {
+	var mtm float64
+	for _, position := range positions {
+		var ltp float64
+		if position.NetQuantity() != 0 {
+			var ok bool
+			if ltp, ok = source(position.Exchange, position.SymbolToken); !ok {
+				return 0, NewError(InputError, "no last traded price for "+position.Exchange+":"+position.Tradingsymbol, nil)
+			}
+		}
+		mtm += position.MTM(ltp)
+	}
+	return mtm, nil
+}
@@ -0,0 +1,53 @@
+package smartapigo
+
+import (
+	"testing"
+)
+
+var testPositions = Positions{
+	// Bought 10 at 100 yesterday and 5 at 110 today, sold 8 at 120 today.
+	{Exchange: "NSE", SymbolToken: "1", Tradingsymbol: "A-EQ", Multiplier: "-1", BuyQuantity: "5", SellQuantity: "8", CfBuyQty: "10",
+		NetQty: "7", TotalBuyValue: "1550", TotalSellValue: "960", AverageNetPrice: "103.33"},
+	// Carried forward short of 2 lots of 50.
+	{Exchange: "NFO", SymbolToken: "2", Tradingsymbol: "B-FUT", Multiplier: "1", BuyQuantity: "0", SellQuantity: "0", CfSellQty: "100",
+		NetQty: "-100", TotalBuyValue: "0", TotalSellValue: "20000", AverageNetPrice: "200"},
+	// Closed intraday.
+	{Exchange: "NSE", SymbolToken: "3", Tradingsymbol: "C-EQ", BuyQuantity: "10", SellQuantity: "10",
+		NetQty: "0", TotalBuyValue: "1000", TotalSellValue: "1050"},
+}
+
+func TestPositionViews(t *testing.T) {
+	net, day := testPositions.Net(), testPositions.Day()
+	if len(net) != 2 || net[0].Tradingsymbol != "A-EQ" || net[1].Tradingsymbol != "B-FUT" {
+		t.Errorf("Unexpected net testPositions %+v", net)
+	}
+	if len(day) != 2 || day[0].Tradingsymbol != "A-EQ" || day[1].Tradingsymbol != "C-EQ" {
+		t.Errorf("Unexpected day testPositions %+v", day)
+	}
+	if testPositions[0].DayQuantity() != -3 || testPositions[0].NetQuantity() != 7 {
+		t.Errorf("Unexpected quantities %v %v", testPositions[0].DayQuantity(), testPositions[0].NetQuantity())
+	}
+}
+
+func TestPositionMTM(t *testing.T) {
+	if mtm := testPositions[0].MTM(115); mtm != 215 {
+		t.Errorf("Unexpected mtm %v", mtm)
+	}
+	if unrealized := testPositions[1].Unrealized(190); unrealized != 1000 {
+		t.Errorf("Unexpected unrealized %v", unrealized)
+	}
+
+	ltps := map[string]float64{"NSE:1": 115, "NFO:2": 190}
+	source := func(exchange, symbolToken string) (float64, bool) {
+		ltp, ok := ltps[exchange+":"+symbolToken]
+		return ltp, ok
+	}
+	if mtm, err := testPositions.MTM(source); err != nil || mtm != 215+1000+50 {
+		t.Errorf("Unexpected total mtm %v. %v", mtm, err)
+	}
+
+	delete(ltps, "NFO:2")
+	if _, err := testPositions.MTM(source); err == nil {
+		t.Errorf("Expected an error for a missing last traded price")
+	}
+}
@@ -0,0 +1,66 @@
+package smartapigo
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxPostbackSize is the maximum size of a postback payload.
+const maxPostbackSize = 1 << 20
+
+// OrderPostback represents an order update pushed by the API to the postback url.
+type OrderPostback struct {
+	Order
+	UniqueOrderID string `json:"uniqueorderid"`
+	ParentOrderID string `json:"parentorderid"`
+	ClientCode    string `json:"clientcode"`
+}
+
+// PostbackHandler is an http.Handler receiving the order postbacks of the API
+// and invoking the callbacks registered with OnOrder for each of them. Payloads
+// which are not a JSON order are rejected with 400 Bad Request.
+type PostbackHandler struct {
+	mu        sync.RWMutex
+	callbacks []func(OrderPostback)
+}
+
+// NewPostbackHandler creates a handler of order postbacks.
+func NewPostbackHandler() *PostbackHandler {
+	return &PostbackHandler{}
+}
+
+// OnOrder registers a callback invoked with every order postback received.
+func (h *PostbackHandler) OnOrder(f func(postback OrderPostback)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.callbacks = append(h.callbacks, f)
+}
+
+// ServeHTTP decodes the postback of the request and invokes the callbacks.
+func (h *PostbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var postback OrderPostback
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxPostbackSize)).Decode(&postback); err != nil {
+		http.Error(w, "invalid postback: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if postback.OrderID == "" || postback.OrderStatus == "" {
+		http.Error(w, "invalid postback: orderid and orderstatus are required", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.RLock()
+	callbacks := h.callbacks
+	h.mu.RUnlock()
+	for _, f := range callbacks {
+		f(postback)
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,54 @@
+package smartapigo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestPostbackHandler(t *testing.T) {
+	handler := NewPostbackHandler()
+	var postbacks []OrderPostback
+	handler.OnOrder(func(postback OrderPostback) {
+		postbacks = append(postbacks, postback)
+	})
+	handler.OnOrder(func(postback OrderPostback) {
+		postbacks = append(postbacks, postback)
+	})
+
+	payload, err := ioutil.ReadFile(path.Join(mockBaseDir, "order_postback.json"))
+	if err != nil {
+		t.Fatalf("Error while reading mock postback. %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/postback", strings.NewReader(string(payload))))
+	if rec.Code != http.StatusOK || len(postbacks) != 2 {
+		t.Fatalf("Unexpected response %d with %d callbacks", rec.Code, len(postbacks))
+	}
+	if p := postbacks[0]; p.OrderID != "201020000000080" || p.OrderStatus != "complete" || p.TradingSymbol != "SBIN-EQ" ||
+		p.UniqueOrderID != "34reqfachdfih" || p.OrderTag != "strat-1" {
+		t.Errorf("Unexpected postback %+v", p)
+	}
+
+	tests := []struct {
+		method, body string
+		code         int
+	}{
+		{http.MethodGet, "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "not json", http.StatusBadRequest},
+		{http.MethodPost, `{"orderstatus":"complete"}`, http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(test.method, "/postback", strings.NewReader(test.body)))
+		if rec.Code != test.code {
+			t.Errorf("%s %q: expected %d, got %d", test.method, test.body, test.code, rec.Code)
+		}
+	}
+	if len(postbacks) != 2 {
+		t.Errorf("Unexpected callbacks for invalid postbacks")
+	}
+}
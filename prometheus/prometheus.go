@@ -0,0 +1,84 @@
+// Package prometheus exposes the health of the REST requests of a client as
+// Prometheus metrics.
+//
+//	collector := prometheus.NewCollector("smartapi")
+//	registry.MustRegister(collector)
+//	client.SetMetrics(collector)
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	smartapi "github.com/shammishailaj/smartapigo"
+)
+
+// Collector is a prometheus.Collector which receives the request events as smartapigo.Metrics.
+type Collector struct {
+	requests      *prom.CounterVec
+	errors        *prom.CounterVec
+	latency       *prom.HistogramVec
+	rateLimitWait *prom.HistogramVec
+}
+
+// NewCollector creates a collector whose metrics are prefixed with namespace.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rest",
+			Name:      "requests_total",
+			Help:      "Number of requests by endpoint.",
+		}, []string{"endpoint"}),
+		errors: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rest",
+			Name:      "errors_total",
+			Help:      "Number of failed requests by endpoint and error code.",
+		}, []string{"endpoint", "code"}),
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rest",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the requests by endpoint.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"endpoint"}),
+		rateLimitWait: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rest",
+			Name:      "rate_limit_wait_seconds",
+			Help:      "Time the requests waited for the rate limit by rate limit group.",
+			Buckets:   prom.ExponentialBuckets(0.001, 4, 8),
+		}, []string{"group"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.latency.Describe(ch)
+	c.rateLimitWait.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.latency.Collect(ch)
+	c.rateLimitWait.Collect(ch)
+}
+
+// RequestDone implements smartapigo.Metrics.
+func (c *Collector) RequestDone(endpoint string, errorCode string, latency time.Duration) {
+	c.requests.WithLabelValues(endpoint).Inc()
+	if errorCode != "" {
+		c.errors.WithLabelValues(endpoint, errorCode).Inc()
+	}
+	c.latency.WithLabelValues(endpoint).Observe(latency.Seconds())
+}
+
+// RateLimitWait implements smartapigo.Metrics.
+func (c *Collector) RateLimitWait(group smartapi.RateLimitGroup, wait time.Duration) {
+	c.rateLimitWait.WithLabelValues(string(group)).Observe(wait.Seconds())
+}
@@ -0,0 +1,58 @@
+package prometheus
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	smartapi "github.com/shammishailaj/smartapigo"
+)
+
+// The collector must be usable as client metrics.
+var _ smartapi.Metrics = (*Collector)(nil)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("smartapi")
+	registry := prom.NewRegistry()
+	registry.MustRegister(c)
+
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewStringResponder(200, "127.0.0.1"))
+	client := smartapi.NewWithHTTPClient("test", "test", "test", &http.Client{Transport: transport})
+	transport.RegisterResponder(http.MethodGet, client.Environment().BaseURI+smartapi.URIGetOrderBook,
+		httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":[]}`))
+	transport.RegisterResponder(http.MethodPost, client.Environment().BaseURI+smartapi.URIPlaceOrder,
+		httpmock.NewStringResponder(200, `{"status":false,"message":"Symbol Not Found","errorcode":"AB1009","data":null}`))
+	client.SetMetrics(c)
+
+	if _, err := client.GetOrderBook(); err != nil {
+		t.Fatalf("Error while fetching order book. %v", err)
+	}
+	if _, err := client.PlaceOrder(smartapi.OrderParams{TradingSymbol: "UNKNOWN-EQ", SymbolToken: "1", Quantity: "1"}); err == nil {
+		t.Fatalf("Expected the order to be rejected")
+	}
+	c.RateLimitWait(smartapi.RateLimitHistorical, 2*time.Second)
+
+	expected := `
+# HELP smartapi_rest_errors_total Number of failed requests by endpoint and error code.
+# TYPE smartapi_rest_errors_total counter
+smartapi_rest_errors_total{code="AB1009",endpoint="rest/secure/angelbroking/order/v1/placeOrder"} 1
+# HELP smartapi_rest_requests_total Number of requests by endpoint.
+# TYPE smartapi_rest_requests_total counter
+smartapi_rest_requests_total{endpoint="rest/secure/angelbroking/order/v1/getOrderBook"} 1
+smartapi_rest_requests_total{endpoint="rest/secure/angelbroking/order/v1/placeOrder"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "smartapi_rest_errors_total", "smartapi_rest_requests_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c, "smartapi_rest_request_duration_seconds"); n != 2 {
+		t.Errorf("Unexpected request latency metrics %d", n)
+	}
+	if n := testutil.CollectAndCount(c, "smartapi_rest_rate_limit_wait_seconds"); n != 2 {
+		t.Errorf("Unexpected rate limit wait metrics %d", n)
+	}
+}
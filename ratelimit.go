@@ -0,0 +1,264 @@
+package smartapigo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitGroup is a group of endpoints sharing a rate limit.
+type RateLimitGroup string
+
+const (
+	// RateLimitOrders groups the order placement, modification and cancellation endpoints.
+	RateLimitOrders RateLimitGroup = "orders"
+	// RateLimitHistorical groups the candle data endpoint.
+	RateLimitHistorical RateLimitGroup = "historical"
+	// RateLimitMarket groups the LTP, quote, search scrip and OI buildup endpoints.
+	RateLimitMarket RateLimitGroup = "market"
+	// RateLimitMargin groups the margin calculator endpoint.
+	RateLimitMargin RateLimitGroup = "margin"
+)
+
+// rateLimitGroups maps endpoints to their rate limit group.
+var rateLimitGroups = map[string]RateLimitGroup{
+	URIPlaceOrder:    RateLimitOrders,
+	URIModifyOrder:   RateLimitOrders,
+	URICancelOrder:   RateLimitOrders,
+	URIGetCandleData: RateLimitHistorical,
+	URILTP:           RateLimitMarket,
+	URIMarketData:    RateLimitMarket,
+	URISearchScrip:   RateLimitMarket,
+	URIOIBuildup:     RateLimitMarket,
+	URIMargin:        RateLimitMargin,
+}
+
+// defaultRateLimits are the per second and per minute limits enforced by SmartAPI.
+var defaultRateLimits = map[RateLimitGroup][2]int{
+	RateLimitOrders:     {10, 500},
+	RateLimitHistorical: {3, 180},
+	RateLimitMarket:     {10, 500},
+	RateLimitMargin:     {10, 0},
+}
+
+// rateLimiter queues requests exceeding the per second or per minute limit of a group.
+type rateLimiter struct {
+	perSecond int
+	perMinute int
+	sent      []time.Time
+	// pausedUntil is the time until which the API asked not to send requests.
+	pausedUntil time.Time
+	mu          sync.Mutex
+}
+
+// wait blocks until a request can be sent without exceeding the limits, or ctx is done.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	for {
+		r.mu.Lock()
+		now := time.Now()
+		delay := r.reserve(now)
+		r.mu.Unlock()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve records a request sent at now and returns 0, or returns how long
+// to wait before a request can be sent.
+func (r *rateLimiter) reserve(now time.Time) time.Duration {
+	if now.Before(r.pausedUntil) {
+		return r.pausedUntil.Sub(now)
+	}
+
+	// Forget the requests older than the minute window.
+	i := 0
+	for i < len(r.sent) && now.Sub(r.sent[i]) >= time.Minute {
+		i++
+	}
+	r.sent = r.sent[i:]
+
+	if r.perMinute > 0 && len(r.sent) >= r.perMinute {
+		return r.sent[len(r.sent)-r.perMinute].Add(time.Minute).Sub(now)
+	}
+	if r.perSecond > 0 && len(r.sent) >= r.perSecond {
+		if oldest := r.sent[len(r.sent)-r.perSecond]; now.Sub(oldest) < time.Second {
+			return oldest.Add(time.Second).Sub(now)
+		}
+	}
+
+	r.sent = append(r.sent, now)
+	return 0
+}
+
+// pause holds back the requests until the time given.
+func (r *rateLimiter) pause(until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if until.After(r.pausedUntil) {
+		r.pausedUntil = until
+	}
+}
+
+// rateLimiters holds the rate limiters of a client.
+type rateLimiters struct {
+	limiters map[RateLimitGroup]*rateLimiter
+	mu       sync.RWMutex
+}
+
+func newRateLimiters() *rateLimiters {
+	r := &rateLimiters{limiters: make(map[RateLimitGroup]*rateLimiter)}
+	for group, limits := range defaultRateLimits {
+		r.set(group, limits[0], limits[1])
+	}
+	return r
+}
+
+func (r *rateLimiters) set(group RateLimitGroup, perSecond, perMinute int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if perSecond <= 0 && perMinute <= 0 {
+		delete(r.limiters, group)
+		return
+	}
+	r.limiters[group] = &rateLimiter{perSecond: perSecond, perMinute: perMinute}
+}
+
+// wait blocks until a request to uri is within the limits of its group.
+func (r *rateLimiters) wait(ctx context.Context, uri string) error {
+	group, ok := rateLimitGroups[uri]
+	if !ok {
+		return nil
+	}
+
+	r.mu.RLock()
+	limiter := r.limiters[group]
+	r.mu.RUnlock()
+	if limiter == nil {
+		return nil
+	}
+	return limiter.wait(ctx)
+}
+
+// pause holds back the requests to the group of uri until the time given.
+func (r *rateLimiters) pause(uri string, until time.Time) {
+	group, ok := rateLimitGroups[uri]
+	if !ok {
+		return
+	}
+
+	r.mu.RLock()
+	limiter := r.limiters[group]
+	r.mu.RUnlock()
+	if limiter != nil {
+		limiter.pause(until)
+	}
+}
+
+// SetRateLimit sets the number of requests per second and per minute sent to the
+// endpoints of group. Requests over the limit are queued until they can be sent.
+// A limit of 0 is not enforced, and both limits 0 disables rate limiting of the group.
+// Regardless of the limits, requests are held back while the API asks to wait
+// with Retry-After or an exhausted X-RateLimit-Remaining.
+func (c *Client) SetRateLimit(group RateLimitGroup, perSecond, perMinute int) {
+	c.rateLimiters.set(group, perSecond, perMinute)
+}
+
+// RateLimitInfo holds the rate limit hints sent with a response. Fields are
+// zero for hints the response doesn't have. Remaining is only set along with
+// Limit.
+type RateLimitInfo struct {
+	// Limit is the number of requests allowed in the window, from X-RateLimit-Limit.
+	Limit int
+	// Remaining is the number of requests left in the window, from X-RateLimit-Remaining.
+	Remaining int
+	// Reset is the time until the window resets, from X-RateLimit-Reset.
+	Reset time.Duration
+	// RetryAfter is the time to wait before sending another request, from Retry-After.
+	RetryAfter time.Duration
+
+	// hasRemaining tells an exhausted window from a response without X-RateLimit-Remaining.
+	hasRemaining bool
+}
+
+// Wait returns how long the hints ask to wait before sending another
+// request, or 0 if requests can be sent right away.
+func (info RateLimitInfo) Wait() time.Duration {
+	if info.RetryAfter > 0 {
+		return info.RetryAfter
+	}
+	if info.Limit > 0 && info.hasRemaining && info.Remaining <= 0 {
+		return info.Reset
+	}
+	return 0
+}
+
+// parseRateLimitInfo returns the rate limit hints of the response headers
+// received at now.
+func parseRateLimitInfo(header http.Header, now time.Time) RateLimitInfo {
+	var info RateLimitInfo
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil && limit > 0 {
+		info.Limit = limit
+		if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+			info.Remaining, info.hasRemaining = remaining, true
+		}
+	}
+	info.Reset = parseDelay(header.Get("X-RateLimit-Reset"), now)
+	info.RetryAfter = parseDelay(header.Get("Retry-After"), now)
+	return info
+}
+
+// unixSecondsThreshold tells delays in seconds from unix timestamps in
+// X-RateLimit-Reset, which is sent in both formats by different servers.
+const unixSecondsThreshold = 1000000000
+
+// parseDelay parses a delay in seconds, a unix timestamp or an HTTP date into
+// the time to wait from now.
+func parseDelay(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds >= unixSecondsThreshold {
+			return positive(time.Unix(int64(seconds), 0).Sub(now))
+		}
+		return positive(time.Duration(seconds * float64(time.Second)))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return positive(date.Sub(now))
+	}
+	return 0
+}
+
+func positive(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+type rateLimitInfoKey struct{}
+
+// withRateLimitInfo returns a context storing the rate limit hints of the
+// response to the request made with it in info.
+func withRateLimitInfo(ctx context.Context, info *RateLimitInfo) context.Context {
+	return context.WithValue(ctx, rateLimitInfoKey{}, info)
+}
+
+// storeRateLimitInfo stores the rate limit hints in the info set on the
+// context, if any.
+func storeRateLimitInfo(ctx context.Context, info RateLimitInfo) {
+	if sink, ok := ctx.Value(rateLimitInfoKey{}).(*RateLimitInfo); ok && sink != nil {
+		*sink = info
+	}
+}
@@ -0,0 +1,132 @@
+package smartapigo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := &rateLimiter{perSecond: 2, perMinute: 3}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve(now); delay != 0 {
+			t.Fatalf("Request %d delayed by %v", i, delay)
+		}
+	}
+	if delay := limiter.reserve(now.Add(100 * time.Millisecond)); delay != 900*time.Millisecond {
+		t.Errorf("Expected the third request in a second to wait 900ms, got %v", delay)
+	}
+	if delay := limiter.reserve(now.Add(time.Second)); delay != 0 {
+		t.Errorf("Expected a request in the next second to be sent, got %v", delay)
+	}
+	if delay := limiter.reserve(now.Add(2 * time.Second)); delay != 58*time.Second {
+		t.Errorf("Expected the fourth request in a minute to wait 58s, got %v", delay)
+	}
+	if delay := limiter.reserve(now.Add(time.Minute)); delay != 0 {
+		t.Errorf("Expected a request in the next minute to be sent, got %v", delay)
+	}
+}
+
+func TestRateLimiters(t *testing.T) {
+	limiters := newRateLimiters()
+	limiters.set(RateLimitHistorical, 1, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limiters.wait(ctx, URIGetCandleData); err != nil {
+		t.Fatalf("Error waiting for the first request. %v", err)
+	}
+	if err := limiters.wait(ctx, URIGetCandleData); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the second request to be queued, got %v", err)
+	}
+	if err := limiters.wait(context.Background(), URIUserProfile); err != nil {
+		t.Errorf("Unexpected error for an endpoint without limit. %v", err)
+	}
+
+	limiters.set(RateLimitHistorical, 0, 0)
+	if err := limiters.wait(context.Background(), URIGetCandleData); err != nil {
+		t.Errorf("Unexpected error after disabling the limit. %v", err)
+	}
+}
+
+func TestParseRateLimitInfo(t *testing.T) {
+	now := time.Date(2024, 1, 25, 9, 15, 0, 0, time.UTC)
+	cases := []struct {
+		header http.Header
+		want   RateLimitInfo
+	}{
+		{http.Header{}, RateLimitInfo{}},
+		{http.Header{"Retry-After": {"3"}}, RateLimitInfo{RetryAfter: 3 * time.Second}},
+		{http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, RateLimitInfo{RetryAfter: time.Minute}},
+		{http.Header{"X-Ratelimit-Limit": {"10"}, "X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"2"}}, RateLimitInfo{Limit: 10, Reset: 2 * time.Second, hasRemaining: true}},
+		{http.Header{"X-Ratelimit-Limit": {"10"}, "X-Ratelimit-Reset": {"2"}}, RateLimitInfo{Limit: 10, Reset: 2 * time.Second}},
+		{http.Header{"X-Ratelimit-Limit": {"10"}, "X-Ratelimit-Remaining": {"4"}, "X-Ratelimit-Reset": {strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)}}, RateLimitInfo{Limit: 10, Remaining: 4, Reset: 30 * time.Second, hasRemaining: true}},
+		{http.Header{"Retry-After": {"soon"}}, RateLimitInfo{}},
+	}
+	for _, c := range cases {
+		if got := parseRateLimitInfo(c.header, now); got != c.want {
+			t.Errorf("Expected %+v for %v, got %+v", c.want, c.header, got)
+		}
+	}
+
+	if wait := (RateLimitInfo{Limit: 10, Remaining: 4, Reset: 30 * time.Second, hasRemaining: true}).Wait(); wait != 0 {
+		t.Errorf("Expected no wait with requests remaining, got %v", wait)
+	}
+	if wait := (RateLimitInfo{Limit: 10, Reset: 2 * time.Second, hasRemaining: true}).Wait(); wait != 2*time.Second {
+		t.Errorf("Expected to wait for the reset, got %v", wait)
+	}
+	if wait := (RateLimitInfo{Limit: 10, Reset: 2 * time.Second}).Wait(); wait != 0 {
+		t.Errorf("Expected no wait without X-RateLimit-Remaining, got %v", wait)
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URILTP, func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusForbidden, "Access denied because of exceeding access rate")
+		resp.Header.Set("Retry-After", "30")
+		return resp, nil
+	})
+
+	_, err := client.GetLTP(LTPParams{Exchange: "NSE", TradingSymbol: "SBIN-EQ", SymbolToken: "3045"})
+	var apiErr Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) || apiErr.RateLimit.RetryAfter != 30*time.Second {
+		t.Fatalf("Unexpected error %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetLTPCtx(ctx, LTPParams{Exchange: "NSE", TradingSymbol: "SBIN-EQ", SymbolToken: "3045"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the next request to be held back, got %v", err)
+	}
+	if err := client.rateLimiters.wait(context.Background(), URIGetCandleData); err != nil {
+		t.Errorf("Unexpected error for another endpoint group. %v", err)
+	}
+}
+
+func TestRateLimitHeadersWithoutRemaining(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URILTP, func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"ltp":455}}`)
+		resp.Header.Set("X-RateLimit-Limit", "10")
+		resp.Header.Set("X-RateLimit-Reset", "30")
+		return resp, nil
+	})
+
+	params := LTPParams{Exchange: "NSE", TradingSymbol: "SBIN-EQ", SymbolToken: "3045"}
+	if _, err := client.GetLTP(params); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetLTPCtx(ctx, params); err != nil {
+		t.Errorf("Expected the next request to be sent without X-RateLimit-Remaining, got %v", err)
+	}
+}
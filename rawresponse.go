@@ -0,0 +1,24 @@
+package smartapigo
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type rawResponseKey struct{}
+
+// WithRawResponse returns a context storing the raw JSON envelope of the
+// responses to the requests made with it in raw, e.g. to read fields the API
+// returns which aren't part of the typed results yet. Methods making several
+// requests leave the envelope of the last one. Error responses are stored too.
+func WithRawResponse(ctx context.Context, raw *json.RawMessage) context.Context {
+	return context.WithValue(ctx, rawResponseKey{}, raw)
+}
+
+// storeRawResponse copies the body of a response into the raw response set on
+// the context, if any.
+func storeRawResponse(ctx context.Context, body []byte) {
+	if raw, ok := ctx.Value(rawResponseKey{}).(*json.RawMessage); ok && raw != nil {
+		*raw = append((*raw)[:0], body...)
+	}
+}
@@ -0,0 +1,41 @@
+package smartapigo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestWithRawResponse(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetOrderBook, httpmock.NewStringResponder(http.StatusOK,
+		`{"status":true,"message":"SUCCESS","errorcode":"","data":[{"orderid":"201020000000080","newfield":"value"}]}`))
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetTradeBook, httpmock.NewStringResponder(http.StatusOK,
+		`{"status":false,"message":"Invalid Token","errorcode":"AG8001","data":null}`))
+
+	var raw json.RawMessage
+	ctx := WithRawResponse(context.Background(), &raw)
+	orders, err := client.GetOrderBookCtx(ctx)
+	if err != nil || len(orders) != 1 || orders[0].OrderID != "201020000000080" {
+		t.Fatalf("Unexpected order book %+v. %v", orders, err)
+	}
+
+	var envelope struct {
+		Data []struct {
+			NewField string `json:"newfield"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil || len(envelope.Data) != 1 || envelope.Data[0].NewField != "value" {
+		t.Errorf("Unexpected raw response %s. %v", raw, err)
+	}
+
+	if _, err := client.GetTradeBookCtx(ctx); err == nil {
+		t.Fatalf("Expected an error for the trade book")
+	}
+	if !json.Valid(raw) || string(raw) != `{"status":false,"message":"Invalid Token","errorcode":"AG8001","data":null}` {
+		t.Errorf("Expected the raw error response, got %s", raw)
+	}
+}
@@ -0,0 +1,31 @@
+package smartapigo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestRequestID(t *testing.T) {
+	client, transport := newMockClient()
+	var ids []string
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIUserProfile, func(req *http.Request) (*http.Response, error) {
+		ids = append(ids, req.Header.Get(RequestIDHeader))
+		return httpmock.NewStringResponse(http.StatusBadRequest, `{"status":false,"message":"Invalid Token","errorcode":"AG8001","data":null}`), nil
+	})
+
+	_, err := client.GetUserProfileCtx(WithRequestID(context.Background(), "support-42"))
+	var apiErr Error
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "support-42" {
+		t.Errorf("Expected the error to carry the request id, got %+v", err)
+	}
+
+	client.GetUserProfile()
+	client.GetUserProfile()
+	if len(ids) != 3 || ids[0] != "support-42" || len(ids[1]) != 32 || ids[1] == ids[2] {
+		t.Errorf("Unexpected request ids %q", ids)
+	}
+}
@@ -0,0 +1,104 @@
+package smartapigo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// transientErrorCodes are the error codes of failures which may succeed when retried.
+var transientErrorCodes = map[string]bool{
+	"AB1004": true, // Something Went Wrong, Please Try After Sometime
+	"AB2001": true, // Internal Error, Please Try After Sometime
+}
+
+// RetryPolicy describes how requests failing with a transient error are retried.
+// Timeouts, connection errors, rate limited and 5xx responses and the transient
+// error codes of the API are retried, waiting at least as long as the Retry-After
+// of the response.
+//
+// Requests placing orders are only retried when the order has an OrderTag, with
+// which the caller can tell whether a retried order was placed more than once.
+// Requests creating GTT rules are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry, doubled on every retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+}
+
+// backoff returns the delay before the retry following attempt, with jitter
+// between half and the whole of the exponential delay.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MinBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// SetRetryPolicy enables retrying requests failing with a transient error.
+// A policy with MaxAttempts less than 2 disables retries.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// retryable reports whether the request to uri with params may be sent again.
+func retryable(uri string, params map[string]interface{}) bool {
+	switch uri {
+	case URIPlaceOrder:
+		tag, _ := params["ordertag"].(string)
+		return tag != ""
+	case URICreateGTTRule:
+		return false
+	}
+	return true
+}
+
+// transient reports whether err is a failure which may succeed when retried.
+func transient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Is(ErrRateLimited) || apiErr.HTTPStatus >= http.StatusInternalServerError ||
+			transientErrorCodes[apiErr.Code]
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryAfter returns the time the API asked to wait before retrying err.
+func retryAfter(err error) time.Duration {
+	var apiErr Error
+	if errors.As(err, &apiErr) {
+		return apiErr.RateLimit.Wait()
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package smartapigo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// newRetryClient returns a client whose requests to uri fail with status failures times before succeeding.
+func newRetryClient(uri string, status int, failures int) (*Client, *int) {
+	client, transport := newMockClient()
+
+	calls := 0
+	transport.RegisterResponder(http.MethodPost, client.baseURI+uri, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls <= failures {
+			return httpmock.NewStringResponse(status, "<html>unavailable</html>"), nil
+		}
+		return httpmock.NewStringResponse(http.StatusOK, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"orderid":"201020000000080"}}`), nil
+	})
+	return client, &calls
+}
+
+func TestRetryPolicy(t *testing.T) {
+	client, calls := newRetryClient(URIModifyOrder, http.StatusServiceUnavailable, 2)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+	orderResponse, err := client.ModifyOrder(ModifyOrderParams{OrderID: "201020000000080"})
+	if err != nil {
+		t.Fatalf("Error while modifying order. %v", err)
+	}
+	if *calls != 3 || orderResponse.OrderID != "201020000000080" {
+		t.Errorf("Unexpected %d calls and response %+v", *calls, orderResponse)
+	}
+}
+
+func TestRetryPolicyRateLimit(t *testing.T) {
+	client, calls := newRetryClient(URIModifyOrder, http.StatusServiceUnavailable, 2)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+	client.SetRateLimit(RateLimitOrders, 0, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := client.ModifyOrderCtx(ctx, ModifyOrderParams{OrderID: "201020000000080"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the retry over the limit to be held back, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 calls within the limit, got %d", *calls)
+	}
+}
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	client, calls := newRetryClient(URIModifyOrder, http.StatusTooManyRequests, 5)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond})
+
+	_, err := client.ModifyOrder(ModifyOrderParams{OrderID: "201020000000080"})
+	if apiErr, ok := err.(Error); !ok || apiErr.HTTPStatus != http.StatusTooManyRequests {
+		t.Errorf("Expected a too many requests error, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", *calls)
+	}
+}
+
+func TestRetryPolicyPlaceOrder(t *testing.T) {
+	client, calls := newRetryClient(URIPlaceOrder, http.StatusBadGateway, 1)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+
+	if _, err := client.PlaceOrder(OrderParams{Quantity: "1"}); err == nil {
+		t.Errorf("Expected an order without tag not to be retried")
+	}
+	if *calls != 1 {
+		t.Errorf("Expected 1 call, got %d", *calls)
+	}
+
+	if _, err := client.PlaceOrder(OrderParams{Quantity: "1", OrderTag: "strategy-1"}); err != nil {
+		t.Errorf("Expected an order with tag to be sent. %v", err)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		delay := policy.backoff(attempt + 1)
+		if delay < max/2 || delay > max {
+			t.Errorf("Backoff %v of attempt %d not within [%v, %v]", delay, attempt+1, max/2, max)
+		}
+	}
+}
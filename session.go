@@ -0,0 +1,146 @@
+package smartapigo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// sessionRefreshRetryDelay is the delay before retrying a failed session renewal.
+	sessionRefreshRetryDelay = time.Minute
+	// sessionRefreshInterval is the renewal interval of access tokens without expiry.
+	sessionRefreshInterval = time.Hour
+)
+
+// SetOnSessionRefreshed sets the callback invoked with the tokens of the session
+// every time the access token is renewed, either with RenewAccessToken or by the
+// session refresher. It can be used to hand the new feed token to a ticker.
+func (c *Client) SetOnSessionRefreshed(f func(tokens UserSessionTokens)) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.onRefreshed = f
+}
+
+func (c *Client) triggerSessionRefreshed(tokens UserSessionTokens) {
+	c.sessionMu.RLock()
+	f := c.onRefreshed
+	c.sessionMu.RUnlock()
+	if f != nil {
+		f(tokens)
+	}
+}
+
+// StartSessionRefresher renews the access token with the refresh token of the
+// session before it expires, until ctx is done, StopSessionRefresher is called
+// or the session is invalidated. The access token is renewed before its expiry
+// at the time the JWT expires, or every hour when the expiry is unknown. Failed
+// renewals are retried every minute. A refresher started before is stopped.
+func (c *Client) StartSessionRefresher(ctx context.Context, before time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.sessionMu.Lock()
+	if c.stopRefresher != nil {
+		c.stopRefresher()
+	}
+	c.stopRefresher = cancel
+	c.sessionMu.Unlock()
+	go c.refreshSession(ctx, before)
+}
+
+// StopSessionRefresher stops the session refresher started with StartSessionRefresher.
+func (c *Client) StopSessionRefresher() {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	if c.stopRefresher != nil {
+		c.stopRefresher()
+		c.stopRefresher = nil
+	}
+}
+
+// SetOnSessionInvalidated sets the callback invoked when the session is
+// invalidated, either with InvalidateSession or by logging out. It can be used
+// to close the tickers and order streams of the session.
+func (c *Client) SetOnSessionInvalidated(f func()) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.onInvalidated = f
+}
+
+// InvalidateSession ends the session locally without a request: it stops the
+// session refresher and clears the tokens of the session, so tickers created
+// with the client stop reconnecting, then invokes the invalidation callback.
+func (c *Client) InvalidateSession() {
+	c.StopSessionRefresher()
+
+	c.sessionMu.Lock()
+	c.accessToken, c.refreshToken, c.feedToken = "", "", ""
+	f := c.onInvalidated
+	c.sessionMu.Unlock()
+
+	if f != nil {
+		f()
+	}
+}
+
+func (c *Client) refreshSession(ctx context.Context, before time.Duration) {
+	delay := sessionRefreshDelay(c.AccessToken(), before, time.Now())
+	for {
+		if err := sleep(ctx, delay); err != nil {
+			return
+		}
+
+		if _, err := c.RenewAccessTokenCtx(ctx, c.RefreshToken()); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.httpClient.GetClient().hLog.Printf("Session renewal failed: %v", err)
+			delay = sessionRefreshRetryDelay
+			continue
+		}
+		// Wait at least the retry delay if the new token expires too soon as well.
+		if delay = sessionRefreshDelay(c.AccessToken(), before, time.Now()); delay < sessionRefreshRetryDelay {
+			delay = sessionRefreshRetryDelay
+		}
+	}
+}
+
+// sessionRefreshDelay returns how long to wait before renewing the access token.
+func sessionRefreshDelay(accessToken string, before time.Duration, now time.Time) time.Duration {
+	expiry, ok := jwtExpiry(accessToken)
+	if !ok {
+		return sessionRefreshInterval
+	}
+	if delay := expiry.Add(-before).Sub(now); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// SessionExpiry returns the expiry time of the access token of the current
+// session, or false if the session has no access token with an expiry.
+func (c *Client) SessionExpiry() (time.Time, bool) {
+	return jwtExpiry(c.AccessToken())
+}
+
+// jwtExpiry returns the expiry time in the exp claim of a JWT.
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
@@ -0,0 +1,156 @@
+package smartapigo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// newJWT returns an unsigned JWT expiring at expiry.
+func newJWT(expiry time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"test","exp":%d}`, expiry.Unix())))
+	return "eyJhbGciOiJIUzUxMiJ9." + payload + ".signature"
+}
+
+func TestJWTExpiry(t *testing.T) {
+	expiry := time.Unix(1700000000, 0)
+	if got, ok := jwtExpiry(newJWT(expiry)); !ok || !got.Equal(expiry) {
+		t.Errorf("Expected expiry %v, got %v", expiry, got)
+	}
+	if _, ok := jwtExpiry("not a jwt"); ok {
+		t.Errorf("Expected no expiry for an invalid token")
+	}
+
+	now := expiry.Add(-time.Hour)
+	if delay := sessionRefreshDelay(newJWT(expiry), 10*time.Minute, now); delay != 50*time.Minute {
+		t.Errorf("Expected a 50m delay, got %v", delay)
+	}
+	if delay := sessionRefreshDelay(newJWT(expiry), 2*time.Hour, now); delay != 0 {
+		t.Errorf("Expected no delay, got %v", delay)
+	}
+	if delay := sessionRefreshDelay("opaque", time.Minute, now); delay != sessionRefreshInterval {
+		t.Errorf("Expected the default interval, got %v", delay)
+	}
+
+	client := New("test", "test", "test")
+	client.SetAccessToken(newJWT(expiry))
+	if got, ok := client.SessionExpiry(); !ok || !got.Equal(expiry) {
+		t.Errorf("Expected session expiry %v, got %v", expiry, got)
+	}
+}
+
+func TestSessionRefresher(t *testing.T) {
+	client, transport := newMockClient()
+	renewed := newJWT(time.Now().Add(24 * time.Hour))
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIUserSessionRenew, httpmock.NewStringResponder(200,
+		`{"status":true,"message":"SUCCESS","errorcode":"","data":{"jwtToken":"`+renewed+`","refreshToken":"refresh-2","feedToken":"feed-2"}}`))
+
+	client.SetAccessToken(newJWT(time.Now().Add(time.Second)))
+	refreshed := make(chan UserSessionTokens, 1)
+	client.SetOnSessionRefreshed(func(tokens UserSessionTokens) {
+		refreshed <- tokens
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.StartSessionRefresher(ctx, 900*time.Millisecond)
+
+	select {
+	case tokens := <-refreshed:
+		if tokens.AccessToken != renewed || tokens.FeedToken != "feed-2" || client.RefreshToken() != "refresh-2" {
+			t.Errorf("Unexpected tokens %+v", tokens)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Session not refreshed")
+	}
+}
+
+func TestInvalidateSession(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIUserSessionRenew, httpmock.NewStringResponder(200,
+		`{"status":true,"message":"SUCCESS","errorcode":"","data":{"jwtToken":"jwt-2","refreshToken":"refresh-2","feedToken":"feed-2"}}`))
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URILogout, httpmock.NewStringResponder(200,
+		`{"status":true,"message":"SUCCESS","errorcode":"","data":null}`))
+
+	client.setSessionTokens(UserSessionTokens{AccessToken: newJWT(time.Now().Add(time.Second)), RefreshToken: "refresh", FeedToken: "feed"})
+	invalidated := 0
+	client.SetOnSessionInvalidated(func() {
+		invalidated++
+	})
+	client.StartSessionRefresher(context.Background(), 500*time.Millisecond)
+
+	client.InvalidateSession()
+	if client.AccessToken() != "" || client.RefreshToken() != "" || client.FeedToken() != "" || invalidated != 1 {
+		t.Errorf("Expected the session to be cleared")
+	}
+	time.Sleep(time.Second)
+	if renewals := transport.GetCallCountInfo()["POST "+client.baseURI+URIUserSessionRenew]; renewals != 0 {
+		t.Errorf("Expected the refresher to be stopped, got %d renewals", renewals)
+	}
+
+	client.SetAccessToken("jwt")
+	if ok, err := client.Logout(); !ok || err != nil {
+		t.Fatalf("Error while logging out. %v", err)
+	}
+	if client.AccessToken() != "" || invalidated != 2 {
+		t.Errorf("Expected the session to be invalidated on logout")
+	}
+}
+
+func TestSessionRenewalDuringRequests(t *testing.T) {
+	client, transport := newMockClient()
+	var renewals int32
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIUserSessionRenew, func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&renewals, 1)
+		return httpmock.NewStringResponse(200, fmt.Sprintf(`{"status":true,"message":"SUCCESS","errorcode":"","data":{"jwtToken":"jwt-%d","refreshToken":"refresh-%d","feedToken":"feed-%d"}}`, n, n, n)), nil
+	})
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIUserProfile, func(req *http.Request) (*http.Response, error) {
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer jwt-") {
+			return httpmock.NewStringResponse(http.StatusUnauthorized, `{"status":false,"message":"Invalid Token","errorcode":"AG8001","data":null}`), nil
+		}
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"clientcode":"test"}}`), nil
+	})
+	client.setSessionTokens(UserSessionTokens{AccessToken: "jwt-0", RefreshToken: "refresh-0", FeedToken: "feed-0"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if _, err := client.RenewAccessToken(client.RefreshToken()); err != nil {
+					t.Errorf("Error while renewing session. %v", err)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if _, err := client.GetUserProfile(); err != nil {
+					t.Errorf("Error while fetching profile. %v", err)
+				}
+				session := client.GetSession()
+				generation := strings.TrimPrefix(session.AccessToken, "jwt-")
+				if session.RefreshToken != "refresh-"+generation || session.FeedToken != "feed-"+generation {
+					t.Errorf("Inconsistent session %+v", session)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if renewals != 50 || client.GetSession().AccessToken == "jwt-0" {
+		t.Errorf("Expected 50 renewals, got %d renewing to %+v", renewals, client.GetSession())
+	}
+}
@@ -0,0 +1,224 @@
+package smartapigo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSlicePollInterval is the order book polling interval while waiting for
+// a slice to fill.
+const defaultSlicePollInterval = time.Second
+
+// defaultSliceFillTimeout is the longest wait for a slice to fill.
+const defaultSliceFillTimeout = 5 * time.Minute
+
+// SliceOptions configures how PlaceSlicedOrder slices an order.
+type SliceOptions struct {
+	// MaxQuantity is the largest quantity of a slice, usually the freeze quantity
+	// of the instrument set by the exchange. It is required as the instrument
+	// master doesn't carry freeze quantities.
+	MaxQuantity int
+	// WaitForFill places every slice once the one before it is complete instead
+	// of once it is placed.
+	WaitForFill bool
+	// PollInterval is the order book polling interval while waiting for a slice
+	// to fill, one second by default.
+	PollInterval time.Duration
+	// FillTimeout is the longest wait for a slice to fill, five minutes by
+	// default. A slice still open after it stops the order with an error
+	// matching context.DeadlineExceeded.
+	FillTimeout time.Duration
+}
+
+// Slice is a child order of a sliced order.
+type Slice struct {
+	OrderParams OrderParams
+	OrderID     string
+	// Order is the order of the slice in the order book as last fetched, empty
+	// until it is.
+	Order Order
+}
+
+// SlicedOrder is an order placed in slices.
+type SlicedOrder struct {
+	OrderParams OrderParams
+	Slices      []Slice
+}
+
+// Quantity returns the quantity of the placed slices.
+func (sliced SlicedOrder) Quantity() int {
+	quantity := 0
+	for _, slice := range sliced.Slices {
+		n, _ := strconv.Atoi(slice.OrderParams.Quantity)
+		quantity += n
+	}
+	return quantity
+}
+
+// FilledQuantity returns the quantity of the slices filled as last fetched.
+func (sliced SlicedOrder) FilledQuantity() int {
+	quantity := 0
+	for _, slice := range sliced.Slices {
+		n, _ := strconv.Atoi(slice.Order.FilledShares)
+		quantity += n
+	}
+	return quantity
+}
+
+// Complete reports whether every slice of the order is placed and complete as
+// last fetched.
+func (sliced SlicedOrder) Complete() bool {
+	total, _ := strconv.Atoi(sliced.OrderParams.Quantity)
+	if sliced.Quantity() != total {
+		return false
+	}
+	for _, slice := range sliced.Slices {
+		if !strings.EqualFold(slice.Order.OrderStatus, "complete") {
+			return false
+		}
+	}
+	return true
+}
+
+// PlaceSlicedOrder places a large order in slices of at most the max quantity of
+// the options, rounded down to the lot size of the instrument when the instrument
+// master is loaded and has the instrument. Slices are placed one after another,
+// or each once the one before it is complete. On error the order placed so far
+// is returned with the error, which matches ErrOrderRejected when a slice is
+// rejected or cancelled while waiting for it to fill.
+func (c *Client) PlaceSlicedOrder(orderParams OrderParams, opts SliceOptions) (SlicedOrder, error) {
+	return c.PlaceSlicedOrderCtx(context.Background(), orderParams, opts)
+}
+
+// PlaceSlicedOrderCtx is PlaceSlicedOrder with a context for the requests.
+func (c *Client) PlaceSlicedOrderCtx(ctx context.Context, orderParams OrderParams, opts SliceOptions) (SlicedOrder, error) {
+	sliced := SlicedOrder{OrderParams: orderParams}
+
+	quantities, err := c.sliceQuantities(orderParams, opts)
+	if err != nil {
+		return sliced, err
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultSlicePollInterval
+	}
+	if opts.FillTimeout <= 0 {
+		opts.FillTimeout = defaultSliceFillTimeout
+	}
+	if orderParams.SymbolToken == "" && c.instruments.Load() != nil {
+		if err = c.ResolveSymbolToken(&orderParams); err != nil {
+			return sliced, err
+		}
+	}
+
+	for _, quantity := range quantities {
+		slice := Slice{OrderParams: orderParams}
+		slice.OrderParams.Quantity = strconv.Itoa(quantity)
+		response, err := c.PlaceOrderCtx(ctx, slice.OrderParams)
+		if err != nil {
+			return sliced, err
+		}
+		slice.OrderID = response.OrderID
+		sliced.Slices = append(sliced.Slices, slice)
+
+		if opts.WaitForFill {
+			fillCtx, cancel := context.WithTimeout(ctx, opts.FillTimeout)
+			order, err := c.waitForFill(fillCtx, slice.OrderID, opts.PollInterval)
+			cancel()
+			sliced.Slices[len(sliced.Slices)-1].Order = order
+			if err != nil {
+				return sliced, err
+			}
+		}
+	}
+	return sliced, nil
+}
+
+// GetSlicedOrder returns the sliced order with the orders of its slices fetched
+// from the order book.
+func (c *Client) GetSlicedOrder(sliced SlicedOrder) (SlicedOrder, error) {
+	return c.GetSlicedOrderCtx(context.Background(), sliced)
+}
+
+// GetSlicedOrderCtx is GetSlicedOrder with a context for the request.
+func (c *Client) GetSlicedOrderCtx(ctx context.Context, sliced SlicedOrder) (SlicedOrder, error) {
+	orders, err := c.GetOrderBookCtx(ctx)
+	if err != nil {
+		return sliced, err
+	}
+
+	slices := make([]Slice, len(sliced.Slices))
+	copy(slices, sliced.Slices)
+	for i := range slices {
+		for _, order := range orders {
+			if order.OrderID == slices[i].OrderID {
+				slices[i].Order = order
+				break
+			}
+		}
+	}
+	sliced.Slices = slices
+	return sliced, nil
+}
+
+// sliceQuantities returns the quantities of the slices of the order.
+func (c *Client) sliceQuantities(orderParams OrderParams, opts SliceOptions) ([]int, error) {
+	total, err := strconv.Atoi(orderParams.Quantity)
+	if err != nil || total <= 0 {
+		return nil, NewError(InputError, "invalid quantity "+strconv.Quote(orderParams.Quantity), nil)
+	}
+
+	maxQuantity, lotSize := opts.MaxQuantity, 1
+	if maxQuantity <= 0 {
+		return nil, NewError(InputError, "max quantity of slices of "+orderParams.TradingSymbol+" is required", nil)
+	}
+	if c.instruments.Load() != nil {
+		if instrument, err := c.ResolveSymbol(string(orderParams.Exchange), orderParams.TradingSymbol); err == nil && instrument.LotSize > 0 {
+			lotSize = instrument.LotSize
+		}
+	}
+	maxQuantity -= maxQuantity % lotSize
+	if maxQuantity == 0 || total%lotSize != 0 {
+		return nil, NewError(InputError, "quantity "+orderParams.Quantity+" cannot be sliced in lots of "+strconv.Itoa(lotSize), nil)
+	}
+
+	var quantities []int
+	for total > 0 {
+		quantity := maxQuantity
+		if total < quantity {
+			quantity = total
+		}
+		quantities = append(quantities, quantity)
+		total -= quantity
+	}
+	return quantities, nil
+}
+
+// waitForFill polls the order book until the order is complete, returning the
+// order as last fetched.
+func (c *Client) waitForFill(ctx context.Context, orderID string, pollInterval time.Duration) (Order, error) {
+	var last Order
+	for {
+		orders, err := c.GetOrderBookCtx(ctx)
+		if err != nil {
+			return last, err
+		}
+		for _, order := range orders {
+			if order.OrderID != orderID {
+				continue
+			}
+			last = order
+			switch strings.ToLower(order.OrderStatus) {
+			case "complete":
+				return order, nil
+			case "rejected", "cancelled":
+				return order, fmt.Errorf("%w: order %s is %s: %s", ErrOrderRejected, orderID, order.OrderStatus, order.Text)
+			}
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return last, fmt.Errorf("order %s not filled: %w", orderID, err)
+		}
+	}
+}
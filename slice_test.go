@@ -0,0 +1,140 @@
+package smartapigo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// newSliceClient returns a client placing orders with ids 1, 2, ... and an order
+// book with the status of every placed order set by status.
+func newSliceClient(t *testing.T, status func(orderID string) string) (*Client, *[]string) {
+	client, transport := newMockClient()
+	client.SetInstruments(newInstruments(t))
+
+	var (
+		mu         sync.Mutex
+		quantities []string
+	)
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIPlaceOrder, func(req *http.Request) (*http.Response, error) {
+		var params map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		quantities = append(quantities, params["quantity"])
+		return httpmock.NewStringResponse(200, fmt.Sprintf(`{"status":true,"message":"SUCCESS","errorcode":"","data":{"orderid":"%d"}}`, len(quantities))), nil
+	})
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetOrderBook, func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var orders []string
+		for i, quantity := range quantities {
+			orderID := fmt.Sprint(i + 1)
+			filled := "0"
+			if status(orderID) == "complete" {
+				filled = quantity
+			}
+			orders = append(orders, fmt.Sprintf(`{"orderid":"%s","orderstatus":"%s","quantity":"%s","filledshares":"%s"}`, orderID, status(orderID), quantity, filled))
+		}
+		return httpmock.NewStringResponse(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":[`+strings.Join(orders, ",")+`]}`), nil
+	})
+	return client, &quantities
+}
+
+var niftyFuture = OrderParams{
+	Variety:         VarietyNormal,
+	TradingSymbol:   "NIFTY25JAN24FUT",
+	TransactionType: TransactionBuy,
+	Exchange:        NFO,
+	OrderType:       OrderTypeMarket,
+	ProductType:     ProductCarryForward,
+	Duration:        DurationDay,
+	Quantity:        "4000",
+}
+
+func TestPlaceSlicedOrder(t *testing.T) {
+	client, quantities := newSliceClient(t, func(orderID string) string {
+		if orderID == "3" {
+			return "open"
+		}
+		return "complete"
+	})
+
+	sliced, err := client.PlaceSlicedOrder(niftyFuture, SliceOptions{MaxQuantity: 1800})
+	if err != nil {
+		t.Fatalf("Error while placing sliced order. %v", err)
+	}
+	if strings.Join(*quantities, ",") != "1800,1800,400" || len(sliced.Slices) != 3 || sliced.Slices[2].OrderID != "3" ||
+		sliced.Slices[0].OrderParams.SymbolToken != "35001" || sliced.Quantity() != 4000 {
+		t.Errorf("Unexpected slices %v %+v", *quantities, sliced)
+	}
+
+	sliced, err = client.GetSlicedOrder(sliced)
+	if err != nil || sliced.FilledQuantity() != 3600 || sliced.Complete() {
+		t.Errorf("Unexpected sliced order %+v. %v", sliced, err)
+	}
+
+	// The max quantity is rounded down to the lot size.
+	if _, err = client.PlaceSlicedOrder(niftyFuture, SliceOptions{MaxQuantity: 1020}); err != nil || len(*quantities) != 7 || (*quantities)[3] != "1000" {
+		t.Errorf("Unexpected slices %v. %v", *quantities, err)
+	}
+
+	odd := niftyFuture
+	odd.Quantity = "4010"
+	if _, err = client.PlaceSlicedOrder(odd, SliceOptions{MaxQuantity: 1800}); err == nil {
+		t.Errorf("Expected an error for a quantity not in lots")
+	}
+	if _, err = client.PlaceSlicedOrder(niftyFuture, SliceOptions{}); err == nil {
+		t.Errorf("Expected an error without a max quantity")
+	}
+
+	// Instruments missing from the instrument master are sliced in single units.
+	unknown := niftyFuture
+	unknown.TradingSymbol, unknown.SymbolToken, unknown.Quantity = "NIFTY29FEB24FUT", "35002", "25"
+	if _, err = client.PlaceSlicedOrder(unknown, SliceOptions{MaxQuantity: 10}); err != nil || strings.Join((*quantities)[7:], ",") != "10,10,5" {
+		t.Errorf("Unexpected slices %v. %v", *quantities, err)
+	}
+}
+
+func TestPlaceSlicedOrderWaitForFill(t *testing.T) {
+	client, quantities := newSliceClient(t, func(orderID string) string {
+		if orderID == "2" {
+			return "rejected"
+		}
+		return "complete"
+	})
+
+	sliced, err := client.PlaceSlicedOrder(niftyFuture, SliceOptions{MaxQuantity: 1800, WaitForFill: true, PollInterval: time.Millisecond})
+	if !errors.Is(err, ErrOrderRejected) {
+		t.Errorf("Expected the rejected slice to stop the order, got %v", err)
+	}
+	if len(*quantities) != 2 || len(sliced.Slices) != 2 || sliced.Slices[0].Order.OrderStatus != "complete" ||
+		sliced.Slices[1].Order.OrderStatus != "rejected" || sliced.FilledQuantity() != 1800 {
+		t.Errorf("Unexpected sliced order %+v", sliced)
+	}
+}
+
+func TestPlaceSlicedOrderFillTimeout(t *testing.T) {
+	client, quantities := newSliceClient(t, func(orderID string) string {
+		return "open"
+	})
+
+	sliced, err := client.PlaceSlicedOrder(niftyFuture, SliceOptions{MaxQuantity: 1800, WaitForFill: true,
+		PollInterval: time.Millisecond, FillTimeout: 20 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the open slice to time out, got %v", err)
+	}
+	if len(*quantities) != 1 || len(sliced.Slices) != 1 || sliced.Slices[0].Order.OrderStatus != "open" {
+		t.Errorf("Unexpected sliced order %+v", sliced)
+	}
+}
@@ -0,0 +1,39 @@
+package smartapigo
+
+import (
+	"github.com/shammishailaj/smartapigo/instruments"
+)
+
+// SetInstruments sets the instrument master index used to resolve symbols to tokens,
+// loaded e.g. with instruments.NewCache(path).Load(ctx).
+func (c *Client) SetInstruments(idx *instruments.Index) {
+	c.instruments.Store(idx)
+}
+
+// ResolveSymbol returns the instrument of the trading symbol on the exchange, e.g.
+// ResolveSymbol("NSE", "SBIN-EQ"), with its token, lot size and tick size.
+func (c *Client) ResolveSymbol(exchange, tradingSymbol string) (instruments.Instrument, error) {
+	idx := c.instruments.Load()
+	if idx == nil {
+		return instruments.Instrument{}, NewError(InputError, "no instrument master set to resolve symbols", nil)
+	}
+	instrument, ok := idx.BySymbol(exchange, tradingSymbol)
+	if !ok {
+		return instruments.Instrument{}, NewError(InputError, "symbol "+exchange+":"+tradingSymbol+" not found", nil)
+	}
+	return instrument, nil
+}
+
+// ResolveSymbolToken sets the SymbolToken of the order params from the instrument
+// master when it is empty.
+func (c *Client) ResolveSymbolToken(orderParams *OrderParams) error {
+	if orderParams.SymbolToken != "" {
+		return nil
+	}
+	instrument, err := c.ResolveSymbol(string(orderParams.Exchange), orderParams.TradingSymbol)
+	if err != nil {
+		return err
+	}
+	orderParams.SymbolToken = instrument.Token
+	return nil
+}
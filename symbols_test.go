@@ -0,0 +1,65 @@
+package smartapigo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shammishailaj/smartapigo/instruments"
+)
+
+const scripMaster = `[
+{"token":"3045","symbol":"SBIN-EQ","name":"SBIN","expiry":"","strike":"-1.000000","lotsize":"1","instrumenttype":"","exch_seg":"NSE","tick_size":"5.000000"},
+{"token":"35001","symbol":"NIFTY25JAN24FUT","name":"NIFTY","expiry":"25JAN2024","strike":"-1.000000","lotsize":"50","instrumenttype":"FUTIDX","exch_seg":"NFO","tick_size":"5.000000"}
+]`
+
+func newInstruments(t *testing.T) *instruments.Index {
+	idx, err := instruments.Parse(strings.NewReader(scripMaster))
+	if err != nil {
+		t.Fatalf("Error parsing instrument master. %v", err)
+	}
+	return idx
+}
+
+func TestResolveSymbol(t *testing.T) {
+	client := New("test", "test", "test")
+	if _, err := client.ResolveSymbol("NSE", "SBIN-EQ"); err == nil {
+		t.Errorf("Expected an error without instrument master")
+	}
+
+	client.SetInstruments(newInstruments(t))
+	instrument, err := client.ResolveSymbol("NFO", "NIFTY25JAN24FUT")
+	if err != nil || instrument.Token != "35001" || instrument.LotSize != 50 || instrument.TickSize != 0.05 {
+		t.Errorf("Unexpected instrument %+v. %v", instrument, err)
+	}
+	if _, err := client.ResolveSymbol("NSE", "UNKNOWN-EQ"); err == nil {
+		t.Errorf("Expected an error for an unknown symbol")
+	}
+}
+
+func TestPlaceOrderResolvesSymbolToken(t *testing.T) {
+	client, transport := newMockClient()
+	client.SetInstruments(newInstruments(t))
+
+	var token string
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URIPlaceOrder, func(req *http.Request) (*http.Response, error) {
+		var params map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		token, _ = params["symboltoken"].(string)
+		return httpmock.NewStringResponse(http.StatusOK, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"orderid":"201020000000080"}}`), nil
+	})
+
+	params := OrderParams{Exchange: "NSE", TradingSymbol: "SBIN-EQ", Quantity: "1"}
+	if _, err := client.PlaceOrder(params); err != nil || token != "3045" {
+		t.Errorf("Expected the symbol token to be resolved, got %q. %v", token, err)
+	}
+
+	params.TradingSymbol = "UNKNOWN-EQ"
+	if _, err := client.PlaceOrder(params); err == nil {
+		t.Errorf("Expected an error for an unknown symbol")
+	}
+}
@@ -0,0 +1,66 @@
+package smartapigo
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// rfc6238Secret is the base32 encoding of the SHA1 secret "12345678901234567890" of RFC 6238.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTP(t *testing.T) {
+	// The RFC 6238 test vectors truncated to 6 digits.
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range cases {
+		code, err := GenerateTOTP(rfc6238Secret, time.Unix(unix, 0))
+		if err != nil || code != want {
+			t.Errorf("Expected %s at %d, got %s. %v", want, unix, code, err)
+		}
+	}
+
+	if code, err := GenerateTOTP("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", time.Unix(59, 0)); err != nil || code != "287082" {
+		t.Errorf("Expected lower case secrets with spaces to be accepted, got %s. %v", code, err)
+	}
+	if _, err := GenerateTOTP("not base32!", time.Now()); err == nil {
+		t.Errorf("Expected an error for an invalid secret")
+	}
+}
+
+func TestGenerateSessionTOTPSecret(t *testing.T) {
+	client, transport := newMockClient()
+	client.SetTOTPSecret(rfc6238Secret)
+
+	var totp string
+	transport.RegisterResponder(http.MethodPost, client.baseURI+URILogin, func(req *http.Request) (*http.Response, error) {
+		var params map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		totp = params["totp"]
+		return httpmock.NewStringResponse(http.StatusOK, `{"status":true,"message":"SUCCESS","errorcode":"","data":{"jwtToken":"jwt"}}`), nil
+	})
+
+	before, _ := GenerateTOTP(rfc6238Secret, time.Now())
+	if _, err := client.GenerateSession(""); err != nil {
+		t.Fatalf("Error while generating session. %v", err)
+	}
+	after, _ := GenerateTOTP(rfc6238Secret, time.Now())
+	if totp != before && totp != after {
+		t.Errorf("Expected the current TOTP, got %q", totp)
+	}
+
+	if _, err := client.GenerateSession("123456"); err != nil || totp != "123456" {
+		t.Errorf("Expected the given TOTP to be sent, got %q. %v", totp, err)
+	}
+}
@@ -0,0 +1,75 @@
+package smartapigo
+
+import (
+	"context"
+)
+
+// Tracer traces the REST requests of the client, e.g. with OpenTelemetry
+// through the otel package. Implementations must be safe for concurrent use as
+// requests are made from several goroutines.
+type Tracer interface {
+	// StartRequest is called before every request with its method and endpoint,
+	// e.g. URIGetOrderBook. It returns the context the request is sent with and
+	// the span ended once the request is done, retries included.
+	StartRequest(ctx context.Context, method, endpoint string) (context.Context, RequestSpan)
+}
+
+// RequestSpan is the span of a traced request.
+type RequestSpan interface {
+	// End is called once the request is done with its outcome.
+	End(outcome RequestOutcome)
+}
+
+// RequestOutcome is the outcome of a traced request.
+type RequestOutcome struct {
+	// RequestID is the id the request was sent with.
+	RequestID string
+	// HTTPStatus is the status of the last response, 0 without one.
+	HTTPStatus int
+	// Err is the error of the request, if any.
+	Err error
+}
+
+// NoopTracer is a Tracer implementation which traces nothing.
+type NoopTracer struct{}
+
+// StartRequest implements Tracer.
+func (NoopTracer) StartRequest(ctx context.Context, method, endpoint string) (context.Context, RequestSpan) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End(RequestOutcome) {}
+
+// SetTracer sets the tracer of the REST requests of the client. Streams created
+// from the client with NewOrderStream and websocket.NewFromClient take the
+// tracer of the client when it traces them too, as the tracer of the otel
+// package does. Nothing is traced by default.
+func (c *Client) SetTracer(t Tracer) {
+	if t == nil {
+		t = NoopTracer{}
+	}
+	c.tracer = t
+}
+
+// Tracer returns the tracer set with SetTracer.
+func (c *Client) Tracer() Tracer {
+	return c.tracer
+}
+
+type responseStatusKey struct{}
+
+// withResponseStatus returns a context storing the http status of the response
+// to the request made with it in status.
+func withResponseStatus(ctx context.Context, status *int) context.Context {
+	return context.WithValue(ctx, responseStatusKey{}, status)
+}
+
+// storeResponseStatus stores the http status in the status set on the context,
+// if any.
+func storeResponseStatus(ctx context.Context, status int) {
+	if sink, ok := ctx.Value(responseStatusKey{}).(*int); ok && sink != nil {
+		*sink = status
+	}
+}
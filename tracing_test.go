@@ -0,0 +1,57 @@
+package smartapigo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+type recordingTracer struct {
+	mu       sync.Mutex
+	outcomes map[string]RequestOutcome
+}
+
+func (t *recordingTracer) StartRequest(ctx context.Context, method, endpoint string) (context.Context, RequestSpan) {
+	return ctx, recordingSpan{t, endpoint}
+}
+
+type recordingSpan struct {
+	tracer   *recordingTracer
+	endpoint string
+}
+
+func (s recordingSpan) End(outcome RequestOutcome) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.outcomes[s.endpoint] = outcome
+}
+
+func TestTracing(t *testing.T) {
+	client, transport := newMockClient()
+	tracer := &recordingTracer{outcomes: make(map[string]RequestOutcome)}
+	client.SetTracer(tracer)
+
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetOrderBook, httpmock.NewStringResponder(http.StatusOK, `{"status":true,"message":"SUCCESS","errorcode":"","data":[]}`))
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetTradeBook, httpmock.NewStringResponder(http.StatusOK, `{"status":false,"message":"Invalid Token","errorcode":"AG8001","data":null}`))
+
+	if _, err := client.GetOrderBookCtx(WithRequestID(context.Background(), "req-1")); err != nil {
+		t.Fatalf("Error while fetching order book. %v", err)
+	}
+	if _, err := client.GetTradeBook(); err == nil {
+		t.Fatalf("Expected an error for the trade book")
+	}
+
+	if len(tracer.outcomes) != 2 {
+		t.Fatalf("Expected a span per request, got %v", tracer.outcomes)
+	}
+	if outcome := tracer.outcomes[URIGetOrderBook]; outcome.RequestID != "req-1" || outcome.HTTPStatus != http.StatusOK || outcome.Err != nil {
+		t.Errorf("Unexpected outcome %+v", outcome)
+	}
+	if outcome := tracer.outcomes[URIGetTradeBook]; outcome.RequestID == "" || outcome.HTTPStatus != http.StatusOK || !errors.Is(outcome.Err, ErrTokenExpired) {
+		t.Errorf("Unexpected outcome %+v", outcome)
+	}
+}
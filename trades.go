@@ -0,0 +1,106 @@
+package smartapigo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Fill represents a trade of an order with its price and quantity parsed.
+type Fill struct {
+	OrderID         string
+	FillID          string
+	Exchange        string
+	TradingSymbol   string
+	ProductType     string
+	TransactionType string
+	Price           float64
+	Quantity        float64
+	Value           float64
+	FillTime        string
+}
+
+// Fills is a list of fills.
+type Fills []Fill
+
+// Quantity returns the total quantity of the fills.
+func (fills Fills) Quantity() float64 {
+	var quantity float64
+	for _, fill := range fills {
+		quantity += fill.Quantity
+	}
+	return quantity
+}
+
+// AveragePrice returns the average price of the fills weighted by their quantity,
+// or zero when there are no fills.
+func (fills Fills) AveragePrice() float64 {
+	var quantity, value float64
+	for _, fill := range fills {
+		quantity += fill.Quantity
+		value += fill.Price * fill.Quantity
+	}
+	if quantity == 0 {
+		return 0
+	}
+	return value / quantity
+}
+
+// fill parses the price, size and value of the trade.
+func (trade Trade) fill() (Fill, error) {
+	fill := Fill{
+		OrderID:         trade.OrderID,
+		FillID:          trade.FillID,
+		Exchange:        trade.Exchange,
+		TradingSymbol:   trade.TradingSymbol,
+		ProductType:     trade.ProductType,
+		TransactionType: trade.TransactionType,
+		FillTime:        trade.FillTime,
+	}
+
+	values := []struct {
+		name, value string
+		parsed      *float64
+	}{
+		{"fillprice", trade.FillPrice, &fill.Price},
+		{"fillsize", trade.FillSize, &fill.Quantity},
+		{"tradevalue", trade.TradeValue, &fill.Value},
+	}
+	for _, v := range values {
+		if v.value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(v.value, 64)
+		if err != nil {
+			return Fill{}, fmt.Errorf("invalid %s %q of trade %s: %w", v.name, v.value, trade.FillID, err)
+		}
+		*v.parsed = parsed
+	}
+	return fill, nil
+}
+
+// GetTradesByOrderID gets the fills of an order from the trade book.
+func (c *Client) GetTradesByOrderID(orderID string) (Fills, error) {
+	return c.GetTradesByOrderIDCtx(context.Background(), orderID)
+}
+
+// GetTradesByOrderIDCtx is GetTradesByOrderID with a context for the request.
+func (c *Client) GetTradesByOrderIDCtx(ctx context.Context, orderID string) (Fills, error) {
+	trades, err := c.GetTradeBookCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var fills Fills
+	for _, trade := range trades {
+		if trade.OrderID != orderID {
+			continue
+		}
+		fill, err := trade.fill()
+		if err != nil {
+			return nil, err
+		}
+		fills = append(fills, fill)
+	}
+	return fills, nil
+}
@@ -0,0 +1,39 @@
+package smartapigo
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func (ts *TestSuite) TestGetTradesByOrderID(t *testing.T) {
+	t.Parallel()
+	fills, err := ts.TestConnect.GetTradesByOrderID("201020000000095")
+	if err != nil || len(fills) != 1 || fills[0].Price != 17500 || fills[0].Quantity != 1 || fills[0].TradingSymbol != "ITC-EQ" {
+		t.Errorf("Unexpected fills %+v. %v", fills, err)
+	}
+}
+
+func TestGetTradesByOrderIDAveragePrice(t *testing.T) {
+	client, transport := newMockClient()
+	transport.RegisterResponder(http.MethodGet, client.baseURI+URIGetTradeBook, httpmock.NewStringResponder(200, `{"status":true,"message":"SUCCESS","errorcode":"","data":[
+		{"orderid":"1","fillid":"a","fillprice":"455.10","fillsize":"10","tradevalue":"4551"},
+		{"orderid":"2","fillid":"b","fillprice":"300","fillsize":"5","tradevalue":"1500"},
+		{"orderid":"1","fillid":"c","fillprice":"455.40","fillsize":"30","tradevalue":"13662"}
+	]}`))
+
+	fills, err := client.GetTradesByOrderID("1")
+	if err != nil || len(fills) != 2 {
+		t.Fatalf("Unexpected fills %+v. %v", fills, err)
+	}
+	if fills.Quantity() != 40 {
+		t.Errorf("Unexpected quantity %v", fills.Quantity())
+	}
+	if average := fills.AveragePrice(); average < 455.3249 || average > 455.3251 {
+		t.Errorf("Unexpected average price %v", average)
+	}
+	if (Fills{}).AveragePrice() != 0 {
+		t.Errorf("Expected no average price without fills")
+	}
+}
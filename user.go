@@ -1,7 +1,12 @@
 package smartapigo
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // UserSession represents the response after a successful authentication.
@@ -24,9 +29,90 @@ type UserProfile struct {
 	Email         string   `json:"email"`
 	Phone         string   `json:"mobileno"`
 	Broker        string   `json:"broker"`
-	Products      []string `json:"products"`
-	LastLoginTime string   `json:"lastlogintime"`
-	Exchanges     []string `json:"exchanges"`
+	Products      Products  `json:"products"`
+	LastLoginTime string    `json:"lastlogintime"`
+	Exchanges     Exchanges `json:"exchanges"`
+}
+
+// Exchanges is the list of exchanges enabled for a user.
+type Exchanges []Exchange
+
+// Products is the list of products enabled for a user.
+type Products []string
+
+// UnmarshalJSON parses the exchanges returned as a list, as a string holding a
+// list or as a comma delimited string.
+func (exchanges *Exchanges) UnmarshalJSON(data []byte) error {
+	values, err := parseList(data)
+	if err != nil {
+		return err
+	}
+	*exchanges = make(Exchanges, len(values))
+	for i, value := range values {
+		(*exchanges)[i] = Exchange(value)
+	}
+	return nil
+}
+
+// UnmarshalJSON parses the products returned as a list, as a string holding a
+// list or as a comma delimited string.
+func (products *Products) UnmarshalJSON(data []byte) error {
+	values, err := parseList(data)
+	if err != nil {
+		return err
+	}
+	*products = values
+	return nil
+}
+
+// parseList parses a list of strings returned as a list, as a string holding a
+// list or as a comma delimited string.
+func parseList(data []byte) ([]string, error) {
+	var values []string
+	if err := json.Unmarshal(data, &values); err == nil {
+		return values, nil
+	}
+
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+	for _, item := range strings.Split(trimmed, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values, nil
+}
+
+// HasExchange reports whether the exchange is enabled for the user.
+func (userProfile UserProfile) HasExchange(exchange Exchange) bool {
+	for _, e := range userProfile.Exchanges {
+		if e == exchange {
+			return true
+		}
+	}
+	return false
+}
+
+// HasProduct reports whether the product is enabled for the user.
+func (userProfile UserProfile) HasProduct(product ProductType) bool {
+	for _, p := range userProfile.Products {
+		if ProductType(p) == product {
+			return true
+		}
+	}
+	return false
 }
 
 // GenerateSession gets a user session details in exchange of username and password.
@@ -34,8 +120,20 @@ type UserProfile struct {
 // Do the token exchange with the `requestToken` obtained after the login flow,
 // and retrieve the `accessToken` required for all subsequent requests. The
 // response contains not just the `accessToken`, but metadata for the user who has authenticated.
-//totp used is required for 2 factor authentication
+//totp used is required for 2 factor authentication, it is generated from the
+//secret set with SetTOTPSecret when empty.
 func (c *Client) GenerateSession(totp string) (UserSession, error) {
+	return c.GenerateSessionCtx(context.Background(), totp)
+}
+
+// GenerateSessionCtx is GenerateSession with a context for the request.
+func (c *Client) GenerateSessionCtx(ctx context.Context, totp string) (UserSession, error) {
+	if totp == "" && c.totpSecret != "" {
+		var err error
+		if totp, err = GenerateTOTP(c.totpSecret, time.Now()); err != nil {
+			return UserSession{}, err
+		}
+	}
 
 	// construct url values
 	params := make(map[string]interface{})
@@ -44,26 +142,31 @@ func (c *Client) GenerateSession(totp string) (UserSession, error) {
 	params["totp"] = totp
 
 	var session UserSession
-	err := c.doEnvelope(http.MethodPost, URILogin, params, nil, &session)
-	// Set accessToken on successful session retrieve
+	err := c.doEnvelope(ctx, http.MethodPost, URILogin, params, nil, &session)
+	// Set session tokens on successful session retrieve
 	if err == nil && session.AccessToken != "" {
-		c.SetAccessToken(session.AccessToken)
+		c.setSessionTokens(session.UserSessionTokens)
 	}
 	return session, err
 }
 
 // RenewAccessToken renews expired access token using valid refresh token.
 func (c *Client) RenewAccessToken(refreshToken string) (UserSessionTokens, error) {
+	return c.RenewAccessTokenCtx(context.Background(), refreshToken)
+}
+
+// RenewAccessTokenCtx is RenewAccessToken with a context for the request.
+func (c *Client) RenewAccessTokenCtx(ctx context.Context, refreshToken string) (UserSessionTokens, error) {
 
 	params := map[string]interface{}{}
 	params["refreshToken"] = refreshToken
 
 	var session UserSessionTokens
-	err := c.doEnvelope(http.MethodPost, URIUserSessionRenew, params, nil, &session, true)
+	err := c.doEnvelope(ctx, http.MethodPost, URIUserSessionRenew, params, nil, &session, true)
 
-	// Set accessToken on successful session retrieve
+	// Set session tokens on successful session retrieve
 	if err == nil && session.AccessToken != "" {
-		c.SetAccessToken(session.AccessToken)
+		c.triggerSessionRefreshed(c.setSessionTokens(session))
 	}
 
 	return session, err
@@ -71,19 +174,33 @@ func (c *Client) RenewAccessToken(refreshToken string) (UserSessionTokens, error
 
 // GetUserProfile gets user profile.
 func (c *Client) GetUserProfile() (UserProfile, error) {
+	return c.GetUserProfileCtx(context.Background())
+}
+
+// GetUserProfileCtx is GetUserProfile with a context for the request.
+func (c *Client) GetUserProfileCtx(ctx context.Context) (UserProfile, error) {
 	var userProfile UserProfile
-	err := c.doEnvelope(http.MethodGet, URIUserProfile, nil, nil, &userProfile, true)
+	err := c.doEnvelope(ctx, http.MethodGet, URIUserProfile, nil, nil, &userProfile, true)
 	return userProfile, err
 }
 
-// Logout from User Session.
+// Logout from User Session. The session is invalidated with InvalidateSession
+// once logged out, or when it has already expired.
 func (c *Client) Logout() (bool, error) {
+	return c.LogoutCtx(context.Background())
+}
+
+// LogoutCtx is Logout with a context for the request.
+func (c *Client) LogoutCtx(ctx context.Context) (bool, error) {
 	var status bool
 	params := map[string]interface{}{}
 	params["clientcode"] = c.clientCode
-	err := c.doEnvelope(http.MethodPost, URILogout, params, nil, nil, true)
+	err := c.doEnvelope(ctx, http.MethodPost, URILogout, params, nil, nil, true)
 	if err == nil {
 		status = true
 	}
+	if err == nil || errors.Is(err, ErrTokenExpired) {
+		c.InvalidateSession()
+	}
 	return status, err
 }
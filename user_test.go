@@ -1,12 +1,14 @@
 package smartapigo
 
 import (
+"encoding/json"
+"reflect"
 "testing"
 )
 
 func (ts *TestSuite) TestGenerateSession(t *testing.T) {
 	t.Parallel()
-	session, err := ts.TestConnect.GenerateSession()
+	session, err := ts.TestConnect.GenerateSession("test")
 	if err != nil {
 		t.Errorf("Error while generating session. %v", err)
 	}
@@ -15,6 +17,10 @@ func (ts *TestSuite) TestGenerateSession(t *testing.T) {
 		t.Errorf("Error while fetching access token. %v", err)
 	}
 
+	if ts.TestConnect.FeedToken() != session.FeedToken || ts.TestConnect.RefreshToken() != session.RefreshToken {
+		t.Errorf("Session tokens are not stored in the client.")
+	}
+
 }
 
 func (ts *TestSuite) TestRenewAccessToken(t *testing.T) {
@@ -41,6 +47,31 @@ func (ts *TestSuite) TestUserProfile(t *testing.T) {
 		t.Errorf("Error while fetching client code. %v", err)
 	}
 
+	if !session.HasExchange(NFO) || session.HasExchange(BFO) || !session.HasProduct(ProductIntraday) || session.HasProduct(ProductCarryForward) {
+		t.Errorf("Unexpected exchanges %v and products %v", session.Exchanges, session.Products)
+	}
+
+}
+
+func TestUserProfileLists(t *testing.T) {
+	for _, data := range []string{
+		`{"exchanges":["NSE","NFO"],"products":["DELIVERY","INTRADAY"]}`,
+		`{"exchanges":"[\"NSE\",\"NFO\"]","products":"[\"DELIVERY\",\"INTRADAY\"]"}`,
+		`{"exchanges":"NSE, NFO","products":"DELIVERY,INTRADAY"}`,
+	} {
+		var profile UserProfile
+		if err := json.Unmarshal([]byte(data), &profile); err != nil {
+			t.Fatalf("Error while parsing profile %s. %v", data, err)
+		}
+		if !reflect.DeepEqual(profile.Exchanges, Exchanges{NSE, NFO}) || !reflect.DeepEqual(profile.Products, Products{"DELIVERY", "INTRADAY"}) {
+			t.Errorf("Unexpected exchanges %v and products %v of %s", profile.Exchanges, profile.Products, data)
+		}
+	}
+
+	var profile UserProfile
+	if err := json.Unmarshal([]byte(`{"exchanges":null,"products":""}`), &profile); err != nil || len(profile.Exchanges) != 0 || len(profile.Products) != 0 {
+		t.Errorf("Unexpected exchanges %v and products %v. %v", profile.Exchanges, profile.Products, err)
+	}
 }
 
 func (ts *TestSuite) TestLogout(t *testing.T) {
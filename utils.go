@@ -1,6 +1,7 @@
 package smartapigo
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"net"
@@ -14,8 +15,40 @@ type Time struct {
 	time.Time
 }
 
+// timeLayouts are the layouts of the timestamps returned by the API.
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "02-Jan-2006 15:04:05", "2006-01-02"}
+
+// UnmarshalJSON parses a timestamp in any of the layouts used by the API.
+// Empty and null timestamps leave the zero time.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "" || value == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	var err error
+	for _, layout := range timeLayouts {
+		var parsed time.Time
+		if parsed, err = time.Parse(layout, value); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return err
+}
+
 type Exchange string
 
+// Valid reports whether the exchange is one of the known exchanges.
+func (e Exchange) Valid() bool {
+	switch e {
+	case NSE, NFO, BSE, BFO, MCX, CDS, NCDEX:
+		return true
+	}
+	return false
+}
+
 type TimeInterval string
 
 const (
@@ -28,6 +61,21 @@ const (
 	// NFO Exchange constant for Only available for NSE Futures as defined in documentation at: https://smartapi.angelbroking.com/docs/Historical
 	NFO Exchange = "NFO"
 
+	// BSE Exchange constant for BSE Equity
+	BSE Exchange = "BSE"
+
+	// BFO Exchange constant for BSE Futures and Options
+	BFO Exchange = "BFO"
+
+	// MCX Exchange constant for MCX Commodities
+	MCX Exchange = "MCX"
+
+	// CDS Exchange constant for NSE Currency Derivatives
+	CDS Exchange = "CDS"
+
+	// NCDEX Exchange constant for NCDEX Commodities
+	NCDEX Exchange = "NCDEX"
+
 	// ONE_MINUTE interval constant for "1 Minute" as defined in documentation at: https://smartapi.angelbroking.com/docs/Historical
 	ONE_MINUTE TimeInterval = "ONE_MINUTE"
 
@@ -64,51 +112,121 @@ const (
 	URIModifyOrder          string = "rest/secure/angelbroking/order/v1/modifyOrder"
 	URICancelOrder          string = "rest/secure/angelbroking/order/v1/cancelOrder"
 	URIGetHoldings          string = "rest/secure/angelbroking/portfolio/v1/getHolding"
+	URIGetAllHoldings       string = "rest/secure/angelbroking/portfolio/v1/getAllHolding"
 	URIGetPositions         string = "rest/secure/angelbroking/order/v1/getPosition"
 	URIGetTradeBook         string = "rest/secure/angelbroking/order/v1/getTradeBook"
 	URILTP                  string = "rest/secure/angelbroking/order/v1/getLtpData"
 	URIRMS                  string = "rest/secure/angelbroking/user/v1/getRMS"
 	URIConvertPosition      string = "rest/secure/angelbroking/order/v1/convertPosition"
 	URIGetCandleData        string = "rest/secure/angelbroking/historical/v1/getCandleData"
+	URICreateGTTRule        string = "rest/secure/angelbroking/gtt/v1/createRule"
+	URIModifyGTTRule        string = "rest/secure/angelbroking/gtt/v1/modifyRule"
+	URICancelGTTRule        string = "rest/secure/angelbroking/gtt/v1/cancelRule"
+	URIGTTRuleDetails       string = "rest/secure/angelbroking/gtt/v1/ruleDetails"
+	URIGTTRuleList          string = "rest/secure/angelbroking/gtt/v1/ruleList"
+	URIMargin               string = "rest/secure/angelbroking/margin/v1/batch"
+	URIOIBuildup            string = "rest/secure/angelbroking/marketData/v1/OIBuildup"
+	URISearchScrip          string = "rest/secure/angelbroking/order/v1/searchScrip"
+	URIMarketData           string = "rest/secure/angelbroking/market/v1/quote"
+	URIVerifyDIS            string = "rest/secure/angelbroking/edis/v1/verifyDis"
+	URIGenerateTPIN         string = "rest/secure/angelbroking/edis/v1/generateTPIN"
+	URIGetDISStatus         string = "rest/secure/angelbroking/edis/v1/getTranStatus"
 	URLHistoryDocumentation string = "https://smartapi.angelbroking.com/docs/Historical"
 )
 
+// structToMap returns the exported fields of a params struct, or of the struct
+// a pointer points to, keyed by their names in the tag. Fields tagged "-" are
+// skipped, as are zero fields tagged omitempty, and untagged fields are keyed
+// by their field name.
 func structToMap(obj interface{}, tagName string) map[string]interface{} {
-	var values reflect.Value
-	switch obj.(type) {
-	case OrderParams:
-		{
-			con := obj.(OrderParams)
-			values = reflect.ValueOf(&con).Elem()
+	params := make(map[string]interface{})
+
+	values := reflect.ValueOf(obj)
+	for values.Kind() == reflect.Ptr {
+		if values.IsNil() {
+			return params
 		}
+		values = values.Elem()
+	}
+	if values.Kind() != reflect.Struct {
+		return params
+	}
 
-	case ModifyOrderParams:
-		{
-			con := obj.(ModifyOrderParams)
-			values = reflect.ValueOf(&con).Elem()
+	tags := values.Type()
+	for i := 0; i < values.NumField(); i++ {
+		field := tags.Field(i)
+		if !field.IsExported() {
+			continue
 		}
-	case LTPParams:
-		{
-			con := obj.(LTPParams)
-			values = reflect.ValueOf(&con).Elem()
+		name, options, _ := strings.Cut(field.Tag.Get(tagName), ",")
+		if name == "-" && options == "" {
+			continue
 		}
-	case ConvertPositionParams:
-		{
-			con := obj.(ConvertPositionParams)
-			values = reflect.ValueOf(&con).Elem()
+		if name == "" {
+			name = field.Name
 		}
+		if options == "omitempty" && values.Field(i).IsZero() {
+			continue
+		}
+		params[name] = values.Field(i).Interface()
 	}
 
-	tags := reflect.TypeOf(obj)
-	params := make(map[string]interface{})
-	for i := 0; i < values.NumField(); i++ {
-		params[tags.Field(i).Tag.Get(tagName)] = values.Field(i).Interface()
+	return params
+}
+
+// ClientInfo is the local and public ip and the mac address of the machine sent
+// with every request.
+type ClientInfo struct {
+	LocalIP    string
+	PublicIP   string
+	MACAddress string
+}
+
+const (
+	// publicIPTimeout is the timeout of the public ip lookup.
+	publicIPTimeout = 2 * time.Second
+	// publicIPRetryInterval is how long the local ip is sent as the public ip
+	// after a failed lookup before the public ip is looked up again.
+	publicIPRetryInterval = time.Minute
+)
+
+// clientInfo returns the client info set with SetClientInfo, or else looks it up
+// and caches it. When the public ip lookup fails, the local ip is sent as the
+// public ip until the lookup is retried after publicIPRetryInterval. The lookup
+// is made without holding the lock, so it doesn't hold back concurrent requests.
+func (c *Client) clientInfo(ctx context.Context) (ClientInfo, error) {
+	c.clientInfoMu.Lock()
+	if c.info != nil && (c.infoRetry.IsZero() || time.Now().Before(c.infoRetry)) {
+		info := *c.info
+		c.clientInfoMu.Unlock()
+		return info, nil
 	}
+	c.clientInfoMu.Unlock()
 
-	return params
+	localIp, publicIp, mac, err := getIpAndMac(ctx, c.httpClient.GetClient().client)
+	if err != nil {
+		return ClientInfo{}, err
+	}
+	info := ClientInfo{LocalIP: localIp, PublicIP: publicIp, MACAddress: mac}
+	var retry time.Time
+	if publicIp == "" {
+		info.PublicIP = localIp
+		retry = time.Now().Add(publicIPRetryInterval)
+	}
+
+	c.clientInfoMu.Lock()
+	defer c.clientInfoMu.Unlock()
+	// Keep client info set or resolved by a concurrent request in the meantime.
+	if c.info != nil && c.infoRetry.IsZero() {
+		return *c.info, nil
+	}
+	c.info, c.infoRetry = &info, retry
+	return info, nil
 }
 
-func getIpAndMac() (string, string, string, error) {
+// getIpAndMac looks up the local ip and mac address of the machine and its public
+// ip, which is empty when the lookup service is unreachable.
+func getIpAndMac(ctx context.Context, h *http.Client) (string, string, string, error) {
 
 	//----------------------
 	// Get the local machine IP address
@@ -155,9 +273,11 @@ func getIpAndMac() (string, string, string, error) {
 		return "", "", "", err
 	}
 
-	publicIp, err := getPublicIp()
-	if err != nil {
-		return "", "", "", err
+	ctx, cancel := context.WithTimeout(ctx, publicIPTimeout)
+	defer cancel()
+	publicIp, err := getPublicIp(ctx, h)
+	if err != nil || net.ParseIP(publicIp) == nil {
+		publicIp = ""
 	}
 
 	return localIp, publicIp, macAddress.String(), nil
@@ -201,8 +321,13 @@ func getLocalIP() (string, error) {
 	return "", errors.New("please check your network connection")
 }
 
-func getPublicIp() (string, error) {
-	resp, err := http.Get("https://myexternalip.com/raw")
+func getPublicIp(ctx context.Context, h *http.Client) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://myexternalip.com/raw", nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := h.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -212,7 +337,7 @@ func getPublicIp() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return string(content), nil
+	return strings.TrimSpace(string(content)), nil
 }
 
 func secondsToDays(seconds int64) int64 {
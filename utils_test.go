@@ -0,0 +1,48 @@
+package smartapigo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStructToMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   interface{}
+		expected map[string]interface{}
+	}{
+		{"OrderParams", OrderParams{Variety: VarietyNormal, TradingSymbol: "SBIN-EQ", SymbolToken: "3045", TransactionType: TransactionBuy, Exchange: NSE, OrderType: OrderTypeMarket, ProductType: ProductIntraday, Duration: DurationDay, Price: "0", SquareOff: "0", StopLoss: "0", Quantity: "1", OrderTag: "strat-1"},
+			map[string]interface{}{"variety": VarietyNormal, "tradingsymbol": "SBIN-EQ", "symboltoken": "3045", "transactiontype": TransactionBuy, "exchange": NSE, "ordertype": OrderTypeMarket, "producttype": ProductIntraday, "duration": DurationDay, "price": "0", "squareoff": "0", "stoploss": "0", "quantity": "1", "ordertag": "strat-1"}},
+		{"ModifyOrderParams", ModifyOrderParams{Variety: VarietyNormal, OrderID: "1", OrderType: OrderTypeLimit, ProductType: ProductIntraday, Duration: DurationDay, Price: "455", Quantity: "1", TradingSymbol: "SBIN-EQ", SymbolToken: "3045", Exchange: NSE},
+			map[string]interface{}{"variety": VarietyNormal, "orderid": "1", "ordertype": OrderTypeLimit, "producttype": ProductIntraday, "duration": DurationDay, "price": "455", "quantity": "1", "tradingsymbol": "SBIN-EQ", "symboltoken": "3045", "exchange": NSE}},
+		{"LTPParams", LTPParams{Exchange: "NSE", TradingSymbol: "SBIN-EQ", SymbolToken: "3045"},
+			map[string]interface{}{"exchange": "NSE", "tradingsymbol": "SBIN-EQ", "symboltoken": "3045"}},
+		{"ConvertPositionParams", ConvertPositionParams{"NSE", "SBIN-EQ", "DELIVERY", "MARGIN", "BUY", 1, "DAY"},
+			map[string]interface{}{"exchange": "NSE", "tradingsymbol": "SBIN-EQ", "oldproducttype": "DELIVERY", "newproducttype": "MARGIN", "transactiontype": "BUY", "quantity": 1, "type": "DAY"}},
+		{"GTTParams", GTTParams{TradingSymbol: "SBIN-EQ", SymbolToken: "3045", Exchange: "NSE", TransactionType: "BUY", ProductType: "DELIVERY", Price: 455, Qty: 1, TriggerPrice: 450, DisclosedQty: 0, TimePeriod: 365},
+			map[string]interface{}{"tradingsymbol": "SBIN-EQ", "symboltoken": "3045", "exchange": "NSE", "transactiontype": "BUY", "producttype": "DELIVERY", "price": 455.0, "qty": 1, "triggerprice": 450.0, "disclosedqty": 0, "timeperiod": 365}},
+		{"GTTParamsDefaultTimePeriod", GTTParams{TradingSymbol: "SBIN-EQ", SymbolToken: "3045", Exchange: "NSE", TransactionType: "BUY", ProductType: "DELIVERY", Price: 455, Qty: 1, TriggerPrice: 450},
+			map[string]interface{}{"tradingsymbol": "SBIN-EQ", "symboltoken": "3045", "exchange": "NSE", "transactiontype": "BUY", "producttype": "DELIVERY", "price": 455.0, "qty": 1, "triggerprice": 450.0, "disclosedqty": 0}},
+		{"ModifyGTTParams", &ModifyGTTParams{ID: "1", SymbolToken: "3045", Exchange: "NSE", Price: 455, Qty: 1, TriggerPrice: 450, TimePeriod: 365},
+			map[string]interface{}{"id": GTTRuleID("1"), "symboltoken": "3045", "exchange": "NSE", "price": 455.0, "qty": 1, "triggerprice": 450.0, "disclosedqty": 0, "timeperiod": 365}},
+		{"CancelGTTParams", CancelGTTParams{ID: "1", SymbolToken: "3045", Exchange: "NSE"},
+			map[string]interface{}{"id": GTTRuleID("1"), "symboltoken": "3045", "exchange": "NSE"}},
+		{"VerifyDISParams", VerifyDISParams{ISIN: "INE062A01020", Quantity: "1"},
+			map[string]interface{}{"isin": "INE062A01020", "quantity": "1"}},
+		{"GenerateTPINParams", GenerateTPINParams{DPID: "33200", ReqID: "1", BOID: "2", PAN: "ABCDE1234F"},
+			map[string]interface{}{"dpId": "33200", "ReqId": "1", "boid": "2", "pan": "ABCDE1234F"}},
+		{"Untagged", struct {
+			Name    string
+			Skipped string `json:"-"`
+			hidden  string
+		}{"a", "b", "c"},
+			map[string]interface{}{"Name": "a"}},
+		{"NotStruct", "params", map[string]interface{}{}},
+		{"NilPointer", (*OrderParams)(nil), map[string]interface{}{}},
+	}
+	for _, test := range tests {
+		if params := structToMap(test.params, "json"); !reflect.DeepEqual(params, test.expected) {
+			t.Errorf("%s: unexpected params %v", test.name, params)
+		}
+	}
+}
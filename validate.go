@@ -0,0 +1,133 @@
+package smartapigo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/shammishailaj/smartapigo/instruments"
+)
+
+// Validate checks the order params before placing the order: the mandatory fields
+// of the variety and order type, the price against the tick size and the quantity
+// against the lot size of the instrument. All the problems found are returned
+// joined in a single error whose Unwrap returns one Error per problem.
+func (orderParams OrderParams) Validate(instrument instruments.Instrument) error {
+	return errors.Join(orderParams.validate(instrument)...)
+}
+
+// ValidateWithQuote is Validate which also checks that the limit price is within
+// the circuit limits of the quote of the instrument.
+func (orderParams OrderParams) ValidateWithQuote(instrument instruments.Instrument, quote Quote) error {
+	errs := orderParams.validate(instrument)
+	if price, ok := parsePositive(orderParams.Price); ok && quote.LowerCircuit > 0 && quote.UpperCircuit > 0 &&
+		(price < quote.LowerCircuit || price > quote.UpperCircuit) {
+		errs = append(errs, invalidOrder("price %v outside the circuit limits %v to %v", price, quote.LowerCircuit, quote.UpperCircuit))
+	}
+	return errors.Join(errs...)
+}
+
+func (orderParams OrderParams) validate(instrument instruments.Instrument) []error {
+	var errs []error
+
+	enums := []struct {
+		name, value string
+		valid       bool
+	}{
+		{"variety", string(orderParams.Variety), orderParams.Variety.Valid()},
+		{"transactiontype", string(orderParams.TransactionType), orderParams.TransactionType.Valid()},
+		{"exchange", string(orderParams.Exchange), orderParams.Exchange.Valid()},
+		{"ordertype", string(orderParams.OrderType), orderParams.OrderType.Valid()},
+		{"producttype", string(orderParams.ProductType), orderParams.ProductType.Valid()},
+		{"duration", string(orderParams.Duration), orderParams.Duration.Valid()},
+	}
+	for _, field := range enums {
+		switch {
+		case field.value == "":
+			errs = append(errs, invalidOrder("%s is required", field.name))
+		case !field.valid:
+			errs = append(errs, invalidOrder("invalid %s %q", field.name, field.value))
+		}
+	}
+	for _, field := range []struct{ name, value string }{{"tradingsymbol", orderParams.TradingSymbol}, {"symboltoken", orderParams.SymbolToken}} {
+		if field.value == "" {
+			errs = append(errs, invalidOrder("%s is required", field.name))
+		}
+	}
+
+	qty, err := strconv.Atoi(orderParams.Quantity)
+	switch {
+	case err != nil || qty <= 0:
+		errs = append(errs, invalidOrder("quantity %q must be a positive number", orderParams.Quantity))
+	case instrument.LotSize > 1 && qty%instrument.LotSize != 0:
+		errs = append(errs, invalidOrder("quantity %d must be a multiple of the lot size %d", qty, instrument.LotSize))
+	}
+
+	limit := orderParams.OrderType == OrderTypeLimit || orderParams.OrderType == OrderTypeSLL
+	trigger := orderParams.OrderType == OrderTypeSLL || orderParams.OrderType == OrderTypeSLM || orderParams.Variety == VarietyStopLoss
+	prices := []struct {
+		name, value string
+		required    bool
+	}{
+		{"price", orderParams.Price, limit},
+		{"triggerprice", orderParams.TriggerPrice, trigger},
+	}
+	for _, field := range prices {
+		price, ok := parsePositive(field.value)
+		if !ok {
+			if field.required {
+				errs = append(errs, invalidOrder("%s is required for %s %s orders", field.name, orderParams.Variety, orderParams.OrderType))
+			}
+			continue
+		}
+		if !onTick(price, instrument.TickSize) {
+			errs = append(errs, invalidOrder("%s %v must be a multiple of the tick size %v", field.name, price, instrument.TickSize))
+		}
+	}
+
+	if orderParams.Variety == VarietyRobo {
+		if orderParams.ProductType != ProductBracket {
+			errs = append(errs, invalidOrder("producttype must be %s for ROBO orders", ProductBracket))
+		}
+		bracket := []struct {
+			name, value string
+			required    bool
+		}{
+			{"squareoff", orderParams.SquareOff, true},
+			{"stoploss", orderParams.StopLoss, true},
+			{"trailingstoploss", orderParams.TrailingStopLoss, false},
+		}
+		for _, field := range bracket {
+			points, ok := parsePositive(field.value)
+			switch {
+			case !ok && field.required:
+				errs = append(errs, invalidOrder("%s is required for ROBO orders", field.name))
+			case !ok && field.value != "":
+				errs = append(errs, invalidOrder("%s %q must be a positive number", field.name, field.value))
+			case ok && !onTick(points, instrument.TickSize):
+				errs = append(errs, invalidOrder("%s %v must be a multiple of the tick size %v", field.name, points, instrument.TickSize))
+			}
+		}
+	}
+	return errs
+}
+
+func invalidOrder(format string, a ...interface{}) error {
+	return NewError(InputError, fmt.Sprintf(format, a...), nil)
+}
+
+// parsePositive parses a positive price.
+func parsePositive(value string) (float64, bool) {
+	price, err := strconv.ParseFloat(value, 64)
+	return price, err == nil && price > 0
+}
+
+// onTick reports whether price is a multiple of the tick size.
+func onTick(price, tickSize float64) bool {
+	if tickSize <= 0 {
+		return true
+	}
+	ticks := price / tickSize
+	return math.Abs(ticks-math.Round(ticks)) < 1e-6
+}
@@ -0,0 +1,138 @@
+package smartapigo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shammishailaj/smartapigo/instruments"
+)
+
+var nifty = instruments.Instrument{Token: "35001", Symbol: "NIFTY25JAN24FUT", LotSize: 50, TickSize: 0.05, Segment: "NFO"}
+
+func validOrder() OrderParams {
+	return OrderParams{
+		Variety:         VarietyNormal,
+		TradingSymbol:   "NIFTY25JAN24FUT",
+		SymbolToken:     "35001",
+		TransactionType: TransactionBuy,
+		Exchange:        NFO,
+		OrderType:       OrderTypeLimit,
+		ProductType:     ProductCarryForward,
+		Duration:        DurationDay,
+		Price:           "21500.05",
+		Quantity:        "100",
+	}
+}
+
+// problems returns the messages of the problems of a validation error.
+func problems(err error) []string {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil
+	}
+	var messages []string
+	for _, err := range joined.Unwrap() {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func TestValidate(t *testing.T) {
+	if err := validOrder().Validate(nifty); err != nil {
+		t.Errorf("Unexpected validation error %v", err)
+	}
+
+	order := validOrder()
+	order.Price = "21500.02"
+	order.Quantity = "75"
+	order.Duration = ""
+	err := order.Validate(nifty)
+	got := problems(err)
+	if len(got) != 3 || !strings.Contains(got[0], "duration") || !strings.Contains(got[1], "lot size") || !strings.Contains(got[2], "tick size") {
+		t.Errorf("Unexpected problems %q", got)
+	}
+
+	var apiErr Error
+	if !errors.As(err, &apiErr) || apiErr.Code != InputError {
+		t.Errorf("Expected input errors, got %v", err)
+	}
+}
+
+func TestValidateStopLoss(t *testing.T) {
+	order := validOrder()
+	order.Variety = "STOPLOSS"
+	order.OrderType = "STOPLOSS_LIMIT"
+	if got := problems(order.Validate(nifty)); len(got) != 1 || !strings.Contains(got[0], "triggerprice is required") {
+		t.Errorf("Unexpected problems %q", got)
+	}
+
+	order.TriggerPrice = "21490"
+	if err := order.Validate(nifty); err != nil {
+		t.Errorf("Unexpected validation error %v", err)
+	}
+
+	order = validOrder()
+	order.Variety = "ROBO"
+	if got := problems(order.Validate(nifty)); len(got) != 3 {
+		t.Errorf("Expected the producttype, squareoff and stoploss problems, got %q", got)
+	}
+}
+
+func TestValidateRobo(t *testing.T) {
+	order := validOrder()
+	order.Variety = VarietyRobo
+	order.ProductType = ProductBracket
+	order.SquareOff = "20"
+	order.StopLoss = "10"
+	order.TrailingStopLoss = "2.5"
+	if err := order.Validate(nifty); err != nil {
+		t.Errorf("Unexpected validation error %v", err)
+	}
+
+	order.StopLoss = "10.02"
+	order.TrailingStopLoss = "-1"
+	got := problems(order.Validate(nifty))
+	if len(got) != 2 || !strings.Contains(got[0], "stoploss 10.02 must be a multiple") || !strings.Contains(got[1], `trailingstoploss "-1" must be a positive number`) {
+		t.Errorf("Unexpected problems %q", got)
+	}
+}
+
+func TestValidateWithQuote(t *testing.T) {
+	quote := Quote{LowerCircuit: 19350, UpperCircuit: 23650}
+	if err := validOrder().ValidateWithQuote(nifty, quote); err != nil {
+		t.Errorf("Unexpected validation error %v", err)
+	}
+
+	order := validOrder()
+	order.Price = "24000"
+	if got := problems(order.ValidateWithQuote(nifty, quote)); len(got) != 1 || !strings.Contains(got[0], "circuit") {
+		t.Errorf("Unexpected problems %q", got)
+	}
+
+	// Unknown circuit limits are not checked.
+	if err := order.ValidateWithQuote(nifty, Quote{}); err != nil {
+		t.Errorf("Unexpected validation error %v", err)
+	}
+}
+
+func TestStructToMapOmitEmpty(t *testing.T) {
+	params := structToMap(validOrder(), "json")
+	if _, ok := params["ordertag"]; ok {
+		t.Errorf("Unexpected empty ordertag in %v", params)
+	}
+	if params["price"] != "21500.05" {
+		t.Errorf("Unexpected params %v", params)
+	}
+}
+
+func TestValidateEnums(t *testing.T) {
+	order := validOrder()
+	order.Variety = "normal"
+	order.OrderType = "SL"
+	order.Exchange = "NYSE"
+	got := problems(order.Validate(nifty))
+	if len(got) != 3 || !strings.Contains(got[0], `invalid variety "normal"`) || !strings.Contains(got[1], `invalid exchange "NYSE"`) || !strings.Contains(got[2], `invalid ordertype "SL"`) {
+		t.Errorf("Unexpected problems %q", got)
+	}
+}
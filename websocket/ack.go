@@ -0,0 +1,101 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriptionWaiter waits for the first tick of each of the pending scrips.
+type subscriptionWaiter struct {
+	pending map[string]struct{}
+	done    chan struct{}
+	err     error
+}
+
+// waiters keeps track of the subscriptions waiting for confirmation.
+type waiters struct {
+	list map[*subscriptionWaiter]struct{}
+	mu   sync.Mutex
+}
+
+func (w *waiters) add(scrips []string) *subscriptionWaiter {
+	waiter := &subscriptionWaiter{
+		pending: make(map[string]struct{}, len(scrips)),
+		done:    make(chan struct{}),
+	}
+	for _, scrip := range scrips {
+		waiter.pending[scrip] = struct{}{}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.list == nil {
+		w.list = make(map[*subscriptionWaiter]struct{})
+	}
+	w.list[waiter] = struct{}{}
+	return waiter
+}
+
+// remove stops the waiter and returns its unconfirmed scrips.
+func (w *waiters) remove(waiter *subscriptionWaiter) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.list, waiter)
+
+	var pending []string
+	for scrip := range waiter.pending {
+		pending = append(pending, scrip)
+	}
+	return pending
+}
+
+// confirm marks the scrip as confirmed for all the waiters.
+func (w *waiters) confirm(scrip string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for waiter := range w.list {
+		if _, ok := waiter.pending[scrip]; !ok {
+			continue
+		}
+		delete(waiter.pending, scrip)
+		if len(waiter.pending) == 0 {
+			delete(w.list, waiter)
+			close(waiter.done)
+		}
+	}
+}
+
+// fail finishes all the waiters with the error.
+func (w *waiters) fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for waiter := range w.list {
+		waiter.err = err
+		delete(w.list, waiter)
+		close(waiter.done)
+	}
+}
+
+// SubscribeCtx subscribes the scrips like SubscribeScrips and waits until the server confirms them.
+// A scrip is confirmed by its first tick, scrips which are not confirmed before ctx is done are
+// returned as rejected. If the server rejects the request, all the unconfirmed scrips are returned
+// along with the *StreamError.
+func (s *SocketClient) SubscribeCtx(ctx context.Context, scrips ...string) ([]string, error) {
+	if len(scrips) == 0 {
+		return nil, nil
+	}
+
+	waiter := s.waiters.add(scrips)
+	if err := s.SubscribeScrips(scrips...); err != nil {
+		s.waiters.remove(waiter)
+		return nil, err
+	}
+
+	select {
+	case <-waiter.done:
+	case <-ctx.Done():
+	}
+
+	rejected := s.waiters.remove(waiter)
+	return rejected, waiter.err
+}
@@ -0,0 +1,76 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestSubscribeCtx(t *testing.T) {
+	server, u := newMockServer(`[{"name":"sf","e":"nse_cm","tk":"1"}]`)
+	defer server.Close()
+
+	client := New("test", "test_token", "")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+	<-connected
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rejected, err := client.SubscribeCtx(ctx, "nse_cm|1")
+	if err != nil || len(rejected) != 0 {
+		t.Errorf("Unexpected subscription result. %v, %v", rejected, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	rejected, err = client.SubscribeCtx(ctx, "nse_cm|1", "nse_cm|2")
+	if err != nil || len(rejected) != 1 || rejected[0] != "nse_cm|2" {
+		t.Errorf("Unexpected subscription result. %v, %v", rejected, err)
+	}
+}
+
+func TestSubscribeCtxRejected(t *testing.T) {
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"nk","name":"mw","msg":"invalid scrip"}]`))
+		_, _, _ = conn.ReadMessage()
+	})
+	defer server.Close()
+
+	client := New("test", "test_token", "")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+	<-connected
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rejected, err := client.SubscribeCtx(ctx, "nse_cm|1")
+	var streamErr *StreamError
+	if !errors.As(err, &streamErr) || len(rejected) != 1 {
+		t.Errorf("Unexpected subscription result. %v, %v", rejected, err)
+	}
+}
@@ -0,0 +1,288 @@
+// Package candles builds OHLCV candles from the ticker stream.
+package candles
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Candle represents an OHLCV bar of a token.
+type Candle struct {
+	// Exchange is the exchange segment of the token, e.g. "nse_cm".
+	Exchange string
+	Token    string
+	Start    time.Time
+	Interval time.Duration
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Volume   float64
+}
+
+// End returns the time the candle ends.
+func (c Candle) End() time.Time {
+	return c.Start.Add(c.Interval)
+}
+
+// Tick is a single trade update of a token.
+type Tick struct {
+	// Exchange is the exchange segment of the token, e.g. "nse_cm". Tokens are
+	// unique within an exchange only, so ticks are aggregated per exchange and token.
+	Exchange string
+	Token    string
+	Price    float64
+	// Volume is the cumulative traded volume of the day, candle volume is computed from its changes.
+	Volume float64
+	Time   time.Time
+}
+
+const (
+	// Layout of the last traded time sent by the ticker.
+	lastTradedTimeLayout = "02/01/2006 15:04:05"
+)
+
+var (
+	// Location of the exchange, candles are aligned to its midnight.
+	exchangeLocation = time.FixedZone("IST", 5*60*60+30*60)
+)
+
+// Builder aggregates ticks into candles of a fixed interval. Candles are aligned to
+// midnight in exchange time, plus an optional origin offset such as the market open.
+// A candle is emitted when the first tick of the next candle arrives or when it is
+// flushed once its interval is over. It is safe for concurrent use.
+type Builder struct {
+	interval  time.Duration
+	origin    time.Duration
+	open      map[string]*Candle
+	emitted   map[string]time.Time
+	volumes   map[string]float64
+	callbacks callbacks
+	channels  map[string][]chan Candle
+	mu        sync.Mutex
+}
+
+// callbacks represents callbacks available in the builder.
+type callbacks struct {
+	onCandle      func(Candle)
+	onTokenCandle map[string]func(Candle)
+}
+
+// NewBuilder creates a candle builder for the interval, e.g. time.Minute.
+func NewBuilder(interval time.Duration) *Builder {
+	return &Builder{
+		interval: interval,
+		open:     make(map[string]*Candle),
+		emitted:  make(map[string]time.Time),
+		volumes:  make(map[string]float64),
+		channels: make(map[string][]chan Candle),
+		callbacks: callbacks{
+			onTokenCandle: make(map[string]func(Candle)),
+		},
+	}
+}
+
+// SetOrigin offsets the alignment of candles from midnight in exchange time,
+// e.g. 9h15m aligns hourly candles to the market open at 09:15.
+func (b *Builder) SetOrigin(offset time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.origin = offset
+}
+
+// OnCandle callback invoked with every completed candle.
+func (b *Builder) OnCandle(f func(candle Candle)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.callbacks.onCandle = f
+}
+
+// OnTokenCandle callback invoked with every completed candle of the token. The
+// token is either a scrip in "exchange|token" format or a bare token, which
+// matches the candles of the token on every exchange.
+func (b *Builder) OnTokenCandle(token string, f func(candle Candle)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.callbacks.onTokenCandle[token] = f
+}
+
+// Candles returns a channel receiving the completed candles of the token, given as
+// in OnTokenCandle. Candles are dropped if the channel buffer of the given size is full.
+func (b *Builder) Candles(token string, size int) <-chan Candle {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Candle, size)
+	b.channels[token] = append(b.channels[token], ch)
+	return ch
+}
+
+// AddMessage adds the ticks of a ticker message. It can be used as the ticker message callback.
+// Ticks without a token or last traded price are ignored and ticks without a valid
+// last traded time are stamped with the current time.
+func (b *Builder) AddMessage(message []map[string]interface{}) {
+	for _, m := range message {
+		exchange, _ := m["e"].(string)
+		token, _ := m["tk"].(string)
+		price, ok := parseFloat(m["ltp"])
+		if token == "" || !ok {
+			continue
+		}
+		volume, _ := parseFloat(m["v"])
+
+		ts := time.Now()
+		if ltt, ok := m["ltt"].(string); ok {
+			if t, err := time.ParseInLocation(lastTradedTimeLayout, ltt, exchangeLocation); err == nil {
+				ts = t
+			}
+		}
+
+		b.AddTick(Tick{Exchange: exchange, Token: token, Price: price, Volume: volume, Time: ts})
+	}
+}
+
+// AddTick adds a tick to the candle of its exchange and token.
+func (b *Builder) AddTick(tick Tick) {
+	b.mu.Lock()
+	var completed []Candle
+
+	key := scrip(tick.Exchange, tick.Token)
+	start := b.bucket(tick.Time)
+	candle, ok := b.open[key]
+	if emitted, done := b.emitted[key]; (ok && start.Before(candle.Start)) || (done && !start.After(emitted)) {
+		// Late tick of an already emitted candle, its volume is counted with the next tick.
+		b.mu.Unlock()
+		return
+	}
+
+	// Volume traded since the previous tick of the token.
+	var traded float64
+	if prev, ok := b.volumes[key]; ok && tick.Volume >= prev {
+		traded = tick.Volume - prev
+	}
+	b.volumes[key] = tick.Volume
+
+	if !ok || !start.Equal(candle.Start) {
+		if ok {
+			completed = append(completed, *candle)
+			b.emitted[key] = candle.Start
+		}
+		candle = &Candle{
+			Exchange: tick.Exchange,
+			Token:    tick.Token,
+			Start:    start,
+			Interval: b.interval,
+			Open:     tick.Price,
+			High:     tick.Price,
+			Low:      tick.Price,
+		}
+		b.open[key] = candle
+	}
+
+	if tick.Price > candle.High {
+		candle.High = tick.Price
+	}
+	if tick.Price < candle.Low {
+		candle.Low = tick.Price
+	}
+	candle.Close = tick.Price
+	candle.Volume += traded
+	b.mu.Unlock()
+
+	b.emit(completed)
+}
+
+// Flush emits the candles whose interval is over at the given time.
+func (b *Builder) Flush(now time.Time) {
+	b.mu.Lock()
+	var completed []Candle
+	for key, candle := range b.open {
+		if !candle.End().After(now) {
+			completed = append(completed, *candle)
+			b.emitted[key] = candle.Start
+			delete(b.open, key)
+		}
+	}
+	b.mu.Unlock()
+
+	b.emit(completed)
+}
+
+// Run flushes completed candles every second until ctx is done, so that candles are
+// emitted on time even when no tick of the next candle arrives.
+func (b *Builder) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			b.Flush(now)
+		}
+	}
+}
+
+// bucket returns the start of the candle the time belongs to. It must be called with the lock held.
+func (b *Builder) bucket(t time.Time) time.Time {
+	t = t.In(exchangeLocation)
+	origin := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, exchangeLocation).Add(b.origin)
+	if t.Before(origin) {
+		origin = origin.AddDate(0, 0, -1)
+	}
+	return origin.Add(t.Sub(origin) / b.interval * b.interval)
+}
+
+func (b *Builder) emit(completed []Candle) {
+	if len(completed) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	onCandle := b.callbacks.onCandle
+	b.mu.Unlock()
+
+	for _, candle := range completed {
+		key := scrip(candle.Exchange, candle.Token)
+		b.mu.Lock()
+		onTokenCandles := []func(Candle){b.callbacks.onTokenCandle[key], b.callbacks.onTokenCandle[candle.Token]}
+		channels := append(append([]chan Candle(nil), b.channels[key]...), b.channels[candle.Token]...)
+		b.mu.Unlock()
+
+		if onCandle != nil {
+			onCandle(candle)
+		}
+		for _, onTokenCandle := range onTokenCandles {
+			if onTokenCandle != nil {
+				onTokenCandle(candle)
+			}
+		}
+		for _, ch := range channels {
+			select {
+			case ch <- candle:
+			default:
+			}
+		}
+	}
+}
+
+// scrip returns the key of a token in "exchange|token" format.
+func scrip(exchange string, token string) string {
+	return exchange + "|" + token
+}
+
+// parseFloat parses the numeric values sent by the ticker as strings or numbers.
+func parseFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(val, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
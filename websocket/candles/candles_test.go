@@ -0,0 +1,145 @@
+package candles
+
+import (
+	"testing"
+	"time"
+)
+
+func at(clock string) time.Time {
+	t, _ := time.ParseInLocation("2006-01-02 15:04:05", "2024-05-02 "+clock, exchangeLocation)
+	return t
+}
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder(time.Minute)
+
+	var candles []Candle
+	b.OnCandle(func(candle Candle) {
+		candles = append(candles, candle)
+	})
+	tokenCandles := b.Candles("3045", 10)
+
+	b.AddTick(Tick{Token: "3045", Price: 100, Volume: 1000, Time: at("09:15:01")})
+	b.AddTick(Tick{Token: "3045", Price: 102, Volume: 1010, Time: at("09:15:20")})
+	b.AddTick(Tick{Token: "3045", Price: 99, Volume: 1030, Time: at("09:15:40")})
+	b.AddTick(Tick{Token: "3045", Price: 101, Volume: 1035, Time: at("09:15:59")})
+	b.AddTick(Tick{Token: "3045", Price: 103, Volume: 1040, Time: at("09:16:00")})
+
+	if len(candles) != 1 {
+		t.Fatalf("Expected one completed candle, got %d.", len(candles))
+	}
+
+	c := candles[0]
+	if !c.Start.Equal(at("09:15:00")) || c.Open != 100 || c.High != 102 || c.Low != 99 || c.Close != 101 || c.Volume != 35 {
+		t.Errorf("Candle is not built properly. %+v", c)
+	}
+
+	select {
+	case tc := <-tokenCandles:
+		if tc != c {
+			t.Errorf("Token channel received a different candle. %+v", tc)
+		}
+	default:
+		t.Errorf("Candle is not sent to the token channel.")
+	}
+
+	// Late tick of the emitted candle is ignored.
+	b.AddTick(Tick{Token: "3045", Price: 50, Volume: 1041, Time: at("09:15:30")})
+
+	b.Flush(at("09:17:00"))
+	if len(candles) != 2 || candles[1].Open != 103 || candles[1].Low != 103 || candles[1].Volume != 5 {
+		t.Errorf("Open candle is not flushed properly. %+v", candles)
+	}
+}
+
+func TestBuilderOrigin(t *testing.T) {
+	b := NewBuilder(time.Hour)
+	b.SetOrigin(9*time.Hour + 15*time.Minute)
+
+	var candles []Candle
+	b.OnTokenCandle("3045", func(candle Candle) {
+		candles = append(candles, candle)
+	})
+
+	b.AddTick(Tick{Token: "3045", Price: 100, Time: at("10:14:59")})
+	b.AddTick(Tick{Token: "3045", Price: 100, Time: at("10:15:00")})
+
+	if len(candles) != 1 || !candles[0].Start.Equal(at("09:15:00")) {
+		t.Errorf("Candles are not aligned to the origin. %+v", candles)
+	}
+}
+
+func TestAddMessage(t *testing.T) {
+	b := NewBuilder(time.Minute)
+
+	var candles []Candle
+	b.OnCandle(func(candle Candle) {
+		candles = append(candles, candle)
+	})
+
+	b.AddMessage([]map[string]interface{}{
+		{"name": "sf", "tk": "3045", "ltp": "500.50", "v": "100", "ltt": "02/05/2024 09:15:10"},
+		{"name": "sf", "tk": "3045", "ltp": "501.00", "v": "150", "ltt": "02/05/2024 09:15:50"},
+		{"name": "tm", "tvalue": "02/05/2024 09:15:55"},
+		{"name": "sf", "tk": "3045", "ltp": "502.00", "v": "160", "ltt": "02/05/2024 09:16:05"},
+	})
+
+	if len(candles) != 1 || candles[0].Open != 500.5 || candles[0].Close != 501 || candles[0].Volume != 50 {
+		t.Errorf("Ticker message is not aggregated properly. %+v", candles)
+	}
+}
+
+func TestBuilderExchanges(t *testing.T) {
+	b := NewBuilder(time.Minute)
+
+	var candles []Candle
+	b.OnCandle(func(candle Candle) {
+		candles = append(candles, candle)
+	})
+	nse := b.Candles("nse_cm|3045", 10)
+
+	// The same token on different exchanges is a different instrument.
+	b.AddTick(Tick{Exchange: "nse_cm", Token: "3045", Price: 500, Volume: 100, Time: at("09:15:01")})
+	b.AddTick(Tick{Exchange: "bse_cm", Token: "3045", Price: 70, Volume: 10, Time: at("09:15:02")})
+	b.Flush(at("09:16:00"))
+
+	if len(candles) != 2 {
+		t.Fatalf("Expected a candle per exchange, got %+v", candles)
+	}
+	select {
+	case c := <-nse:
+		if c.Exchange != "nse_cm" || c.Open != 500 {
+			t.Errorf("Scrip channel received a candle of another exchange. %+v", c)
+		}
+	default:
+		t.Errorf("Candle is not sent to the scrip channel.")
+	}
+	if len(nse) != 0 {
+		t.Errorf("Scrip channel received the candle of another exchange.")
+	}
+}
+
+func TestBuilderLateTickAfterFlush(t *testing.T) {
+	b := NewBuilder(time.Minute)
+
+	var candles []Candle
+	b.OnCandle(func(candle Candle) {
+		candles = append(candles, candle)
+	})
+
+	b.AddTick(Tick{Exchange: "nse_cm", Token: "3045", Price: 100, Time: at("09:15:10")})
+	b.Flush(at("09:16:00"))
+
+	// Late tick of the flushed candle doesn't emit it again.
+	b.AddTick(Tick{Exchange: "nse_cm", Token: "3045", Price: 50, Time: at("09:15:50")})
+	b.Flush(at("09:17:00"))
+	if len(candles) != 1 {
+		t.Errorf("Flushed candle is emitted again. %+v", candles)
+	}
+
+	b.AddTick(Tick{Exchange: "nse_cm", Token: "3045", Price: 101, Time: at("09:16:10")})
+	b.Flush(at("09:17:00"))
+	if len(candles) != 2 || candles[1].Open != 101 {
+		t.Errorf("Candle after the flushed one is not built. %+v", candles)
+	}
+}
@@ -0,0 +1,130 @@
+package websocket
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// newCompressionServer starts a ticker server which negotiates compression and
+// sends count ticks once a subscription is received.
+func newCompressionServer(count int, tick string, negotiated chan<- bool) (*httptest.Server, url.URL) {
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	payload := encodeMessage(tick)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if negotiated != nil {
+			negotiated <- strings.Contains(r.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		for i := 0; i < count; i++ {
+			if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		}
+		_, _, _ = conn.ReadMessage()
+	}))
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+	return server, *u
+}
+
+// countingConn counts the bytes read from the network.
+type countingConn struct {
+	net.Conn
+	read *atomic.Int64
+}
+
+func (c countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+func TestCompression(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		negotiated := make(chan bool, 1)
+		server, u := newCompressionServer(1, `[{"name":"sf","tk":"3045"}]`, negotiated)
+
+		client := New("test", "test_token", "nse_cm|3045", WithURL(u), WithAutoReconnect(false), WithCompression(enabled))
+		messages := make(chan []map[string]interface{}, 1)
+		client.OnMessage(func(message []map[string]interface{}) {
+			messages <- message
+		})
+		client.OnConnect(func() {
+			_ = client.Subscribe()
+		})
+		go client.Serve()
+
+		if got := <-negotiated; got != enabled {
+			t.Errorf("Compression requested %v, expected %v", got, enabled)
+		}
+		<-messages
+		client.Close(context.Background())
+		server.Close()
+	}
+}
+
+func benchmarkCompression(b *testing.B, enabled bool) {
+	tick := `[` + strings.Repeat(`{"name":"sf","e":"nse_cm","tk":"3045","ltp":"512.35","v":"1234567","bp":"512.30","sp":"512.40"},`, 20) + `{"name":"sf"}]`
+	server, u := newCompressionServer(b.N, tick, nil)
+	defer server.Close()
+
+	var read atomic.Int64
+	dialer := &websocket.Dialer{
+		NetDial: func(network, addr string) (net.Conn, error) {
+			conn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return countingConn{Conn: conn, read: &read}, nil
+		},
+	}
+
+	client := New("test", "test_token", "nse_cm|3045", WithURL(u), WithAutoReconnect(false), WithDialer(dialer), WithCompression(enabled))
+	done := make(chan struct{})
+	received := 0
+	client.OnMessage(func(message []map[string]interface{}) {
+		if received++; received == b.N {
+			close(done)
+		}
+	})
+	client.OnConnect(func() {
+		b.ResetTimer()
+		read.Store(0)
+		_ = client.Subscribe()
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	<-done
+	b.StopTimer()
+	b.ReportMetric(float64(read.Load())/float64(b.N), "wire-B/op")
+}
+
+func BenchmarkUncompressed(b *testing.B) {
+	benchmarkCompression(b, false)
+}
+
+func BenchmarkCompressed(b *testing.B) {
+	benchmarkCompression(b, true)
+}
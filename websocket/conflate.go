@@ -0,0 +1,92 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Conflater coalesces ticker messages per scrip and delivers the latest state of every
+// updated scrip at most once per interval. Fields of the updates received for a scrip
+// within an interval are merged, newer values replacing older ones. Messages without
+// a token, such as time messages, are delivered right away.
+type Conflater struct {
+	interval time.Duration
+	handler  func([]map[string]interface{})
+	pending  map[string]map[string]interface{}
+	order    []string
+	mu       sync.Mutex
+}
+
+// NewConflater creates a conflater delivering to handler at most once per interval.
+func NewConflater(interval time.Duration, handler func(message []map[string]interface{})) *Conflater {
+	return &Conflater{
+		interval: interval,
+		handler:  handler,
+		pending:  make(map[string]map[string]interface{}),
+	}
+}
+
+// Add adds a ticker message. It can be used as the ticker message callback.
+func (c *Conflater) Add(message []map[string]interface{}) {
+	var passthrough []map[string]interface{}
+
+	c.mu.Lock()
+	for _, m := range message {
+		if token, _ := m["tk"].(string); token == "" {
+			passthrough = append(passthrough, m)
+			continue
+		}
+
+		// Tokens are unique within an exchange only.
+		scrip := tickScrip(m)
+		merged, ok := c.pending[scrip]
+		if !ok {
+			merged = make(map[string]interface{}, len(m))
+			c.pending[scrip] = merged
+			c.order = append(c.order, scrip)
+		}
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	c.mu.Unlock()
+
+	if len(passthrough) > 0 {
+		c.handler(passthrough)
+	}
+}
+
+// Flush delivers the pending updates right away.
+func (c *Conflater) Flush() {
+	c.mu.Lock()
+	if len(c.order) == 0 {
+		c.mu.Unlock()
+		return
+	}
+
+	message := make([]map[string]interface{}, 0, len(c.order))
+	for _, scrip := range c.order {
+		message = append(message, c.pending[scrip])
+	}
+	c.pending = make(map[string]map[string]interface{})
+	c.order = nil
+	c.mu.Unlock()
+
+	c.handler(message)
+}
+
+// Run delivers the pending updates every interval until ctx is done.
+func (c *Conflater) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Flush()
+		}
+	}
+}
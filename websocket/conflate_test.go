@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConflater(t *testing.T) {
+	var delivered [][]map[string]interface{}
+	c := NewConflater(time.Hour, func(message []map[string]interface{}) {
+		delivered = append(delivered, message)
+	})
+
+	c.Add([]map[string]interface{}{{"name": "sf", "tk": "1", "ltp": "100", "v": "10"}})
+	c.Add([]map[string]interface{}{{"name": "sf", "tk": "2", "ltp": "200"}})
+	c.Add([]map[string]interface{}{{"name": "sf", "tk": "1", "ltp": "101"}, {"name": "tm", "tvalue": "09:15:00"}})
+
+	// Time message is passed through right away.
+	if len(delivered) != 1 || delivered[0][0]["name"] != "tm" {
+		t.Fatalf("Messages without token are not passed through. %v", delivered)
+	}
+
+	c.Flush()
+	if len(delivered) != 2 || len(delivered[1]) != 2 {
+		t.Fatalf("Pending updates are not conflated. %v", delivered)
+	}
+
+	first := delivered[1][0]
+	if first["tk"] != "1" || first["ltp"] != "101" || first["v"] != "10" {
+		t.Errorf("Updates of a token are not merged. %v", first)
+	}
+
+	// Nothing pending, nothing delivered.
+	c.Flush()
+	if len(delivered) != 2 {
+		t.Errorf("Empty flush delivered a message. %v", delivered)
+	}
+}
+
+func TestConflaterExchanges(t *testing.T) {
+	var delivered []map[string]interface{}
+	c := NewConflater(time.Hour, func(message []map[string]interface{}) {
+		delivered = append(delivered, message...)
+	})
+
+	// The same token on different exchanges is a different instrument.
+	c.Add([]map[string]interface{}{{"name": "sf", "e": "nse_cm", "tk": "3045", "ltp": "500"}})
+	c.Add([]map[string]interface{}{{"name": "sf", "e": "bse_cm", "tk": "3045", "ltp": "70"}})
+	c.Flush()
+	if len(delivered) != 2 || delivered[0]["ltp"] != "500" || delivered[1]["ltp"] != "70" {
+		t.Errorf("Updates of different exchanges are merged. %v", delivered)
+	}
+}
+
+func TestConflaterRun(t *testing.T) {
+	delivered := make(chan []map[string]interface{}, 10)
+	c := NewConflater(10*time.Millisecond, func(message []map[string]interface{}) {
+		delivered <- message
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	for i := 0; i < 100; i++ {
+		c.Add([]map[string]interface{}{{"name": "sf", "tk": "1"}})
+	}
+
+	select {
+	case message := <-delivered:
+		if len(message) != 1 {
+			t.Errorf("Updates are not coalesced. %v", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for conflated message.")
+	}
+}
@@ -0,0 +1,85 @@
+package websocket
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// frameDecoder holds the buffers used to decode a frame, they are reused across frames
+// since the decoded messages don't reference them.
+type frameDecoder struct {
+	decoded  []byte
+	source   bytes.Reader
+	zr       io.ReadCloser
+	inflated bytes.Buffer
+}
+
+var decoderPool = sync.Pool{
+	New: func() interface{} {
+		return &frameDecoder{}
+	},
+}
+
+// DecodeFrame decodes a base64 encoded, zlib compressed ticker frame into its messages.
+// The messages are owned by the caller.
+func DecodeFrame(payload []byte) ([]map[string]interface{}, error) {
+	d := decoderPool.Get().(*frameDecoder)
+	defer decoderPool.Put(d)
+
+	val, err := d.inflate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []map[string]interface{}
+	if err := json.Unmarshal(val, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// inflate decodes and decompresses the payload. The returned slice is valid until the next call.
+func (d *frameDecoder) inflate(payload []byte) ([]byte, error) {
+	size := base64.StdEncoding.DecodedLen(len(payload))
+	if cap(d.decoded) < size {
+		d.decoded = make([]byte, size)
+	}
+	n, err := base64.StdEncoding.Decode(d.decoded[:size], payload)
+	if err != nil {
+		return nil, err
+	}
+	d.source.Reset(d.decoded[:n])
+
+	if d.zr == nil {
+		d.zr, err = zlib.NewReader(&d.source)
+	} else {
+		err = d.zr.(zlib.Resetter).Reset(&d.source, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer d.zr.Close()
+
+	d.inflated.Reset()
+	if _, err := d.inflated.ReadFrom(d.zr); err != nil {
+		return nil, err
+	}
+	return d.inflated.Bytes(), nil
+}
+
+// AppendFrame appends the decompressed JSON of a ticker frame to dst and returns the extended buffer.
+// Reusing dst across frames lets callers parsing the JSON themselves decode without allocations.
+func AppendFrame(dst []byte, payload []byte) ([]byte, error) {
+	d := decoderPool.Get().(*frameDecoder)
+	defer decoderPool.Put(d)
+
+	val, err := d.inflate(payload)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, val...), nil
+}
@@ -0,0 +1,56 @@
+package websocket
+
+import (
+	"strings"
+	"testing"
+)
+
+// benchmarkFrame is a frame with twenty ticks.
+var benchmarkFrame = encodeMessage(`[` + strings.Repeat(`{"name":"sf","e":"nse_cm","tk":"3045","ltp":"512.35","v":"1234567","bp":"512.30","sp":"512.40"},`, 20) + `{"name":"sf"}]`)
+
+func TestDecodeFrame(t *testing.T) {
+	// Decoders are reused, so decode frames of different sizes back to back.
+	for _, message := range []string{`[{"name":"sf","tk":"1"},{"name":"sf","tk":"2"}]`, `[{"name":"tm"}]`, `[{"name":"sf","tk":"3"}]`} {
+		decoded, err := DecodeFrame(encodeMessage(message))
+		if err != nil {
+			t.Fatalf("Error while decoding frame. %v", err)
+		}
+		if decoded[0]["name"] == nil || len(decoded) != strings.Count(message, "{") {
+			t.Errorf("Unexpected messages %v for %s", decoded, message)
+		}
+	}
+
+	if _, err := DecodeFrame([]byte("not base64")); err == nil {
+		t.Errorf("Expected error for invalid frame.")
+	}
+}
+
+func TestAppendFrame(t *testing.T) {
+	buf, err := AppendFrame([]byte("prefix:"), encodeMessage(`[{"name":"tm"}]`))
+	if err != nil {
+		t.Fatalf("Error while decoding frame. %v", err)
+	}
+	if string(buf) != `prefix:[{"name":"tm"}]` {
+		t.Errorf("Unexpected buffer %s", buf)
+	}
+}
+
+func BenchmarkDecodeFrame(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeFrame(benchmarkFrame); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAppendFrame(b *testing.B) {
+	var buf []byte
+	var err error
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if buf, err = AppendFrame(buf[:0], benchmarkFrame); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
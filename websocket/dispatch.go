@@ -0,0 +1,148 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+// OverflowPolicy decides what happens to a message when the dispatch queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the read loop until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued message to make room for the new one.
+	OverflowDropOldest
+	// OverflowDropNewest discards the new message.
+	OverflowDropNewest
+)
+
+const (
+	// Default number of workers invoking the message callback.
+	defaultDispatchWorkers = 1
+	// Default number of messages buffered between the read loop and the workers.
+	defaultDispatchQueueSize = 4096
+)
+
+// dispatcher hands messages from the read loop over to a pool of workers
+// through a bounded queue so that slow callbacks don't stall reading.
+type dispatcher struct {
+	queue   chan queuedMessage
+	policy  OverflowPolicy
+	handler func([]map[string]interface{})
+	onDrop  func([]map[string]interface{})
+	metrics Metrics
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// queuedMessage is a message waiting in the dispatch queue along with the time it was received.
+type queuedMessage struct {
+	message  []map[string]interface{}
+	received time.Time
+}
+
+func newDispatcher(workers int, size int, policy OverflowPolicy, handler func([]map[string]interface{}), onDrop func([]map[string]interface{}), metrics Metrics) *dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	d := &dispatcher{
+		queue:   make(chan queuedMessage, size),
+		policy:  policy,
+		handler: handler,
+		onDrop:  onDrop,
+		metrics: metrics,
+	}
+
+	d.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go d.work()
+	}
+	return d
+}
+
+func (d *dispatcher) work() {
+	defer d.wg.Done()
+	for q := range d.queue {
+		d.metrics.CallbackLatency(time.Since(q.received))
+		d.handler(q.message)
+	}
+}
+
+// dispatch queues the message received at the given time according to the overflow policy.
+func (d *dispatcher) dispatch(m []map[string]interface{}, received time.Time) {
+	defer func() {
+		d.metrics.DispatchQueueDepth(d.depth())
+	}()
+
+	message := queuedMessage{message: m, received: received}
+	switch d.policy {
+	case OverflowDropNewest:
+		select {
+		case d.queue <- message:
+		default:
+			d.drop(message)
+		}
+	case OverflowDropOldest:
+		// Serialise producers so the evicted slot can't be taken by another one.
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		for {
+			select {
+			case d.queue <- message:
+				return
+			default:
+			}
+
+			select {
+			case old := <-d.queue:
+				d.drop(old)
+			default:
+			}
+		}
+	default:
+		d.queue <- message
+	}
+}
+
+func (d *dispatcher) drop(q queuedMessage) {
+	if d.onDrop != nil {
+		d.onDrop(q.message)
+	}
+}
+
+// depth returns the number of messages waiting in the queue.
+func (d *dispatcher) depth() int {
+	return len(d.queue)
+}
+
+// stop waits for the queued messages to be handled and stops the workers.
+func (d *dispatcher) stop() {
+	close(d.queue)
+	d.wg.Wait()
+}
+
+// SetDispatchWorkers sets the number of workers invoking the message callback.
+// Messages are delivered in order only when a single worker is used.
+func (s *SocketClient) SetDispatchWorkers(val int) {
+	s.dispatchWorkers = val
+}
+
+// SetDispatchQueueSize sets the number of messages buffered for the workers.
+func (s *SocketClient) SetDispatchQueueSize(val int) {
+	s.dispatchQueueSize = val
+}
+
+// SetOverflowPolicy sets what happens to messages when the dispatch queue is full.
+func (s *SocketClient) SetOverflowPolicy(policy OverflowPolicy) {
+	s.overflowPolicy = policy
+}
+
+// OnDrop callback invoked with every message discarded by the overflow policy.
+func (s *SocketClient) OnDrop(f func(message []map[string]interface{})) {
+	s.callbacks.onDrop = f
+}
@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDispatcherOverflow(t *testing.T) {
+	for _, tc := range []struct {
+		policy   OverflowPolicy
+		expected string
+	}{
+		{OverflowDropNewest, "1"},
+		{OverflowDropOldest, "2"},
+	} {
+		release := make(chan struct{})
+		handled := make(chan string, 10)
+		var dropped []string
+
+		d := newDispatcher(1, 1, tc.policy, func(message []map[string]interface{}) {
+			<-release
+			handled <- message[0]["tk"].(string)
+		}, func(message []map[string]interface{}) {
+			dropped = append(dropped, message[0]["tk"].(string))
+		}, NoopMetrics{})
+
+		// First message is picked by the worker which blocks on release.
+		d.dispatch([]map[string]interface{}{{"tk": "0"}}, time.Now())
+		for len(d.queue) != 0 {
+		}
+		d.dispatch([]map[string]interface{}{{"tk": "1"}}, time.Now())
+		d.dispatch([]map[string]interface{}{{"tk": "2"}}, time.Now())
+
+		if len(dropped) != 1 {
+			t.Fatalf("Expected one dropped message, got %v.", dropped)
+		}
+
+		close(release)
+		d.stop()
+		close(handled)
+
+		var last string
+		for tk := range handled {
+			last = tk
+		}
+		if last != tc.expected {
+			t.Errorf("Policy %d: expected %s to be handled last, got %s.", tc.policy, tc.expected, last)
+		}
+	}
+}
+
+func TestDispatchMessages(t *testing.T) {
+	server, u := newMockServer(`[{"name":"sf","tk":"3045","ltp":"500.00"}]`)
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetDispatchWorkers(2)
+	client.SetDispatchQueueSize(16)
+
+	messages := make(chan []map[string]interface{}, 1)
+	client.OnConnect(func() {
+		_ = client.Subscribe()
+	})
+	client.OnMessage(func(message []map[string]interface{}) {
+		messages <- message
+	})
+	go client.Serve()
+
+	select {
+	case message := <-messages:
+		if message[0]["tk"] != "3045" {
+			t.Errorf("Unexpected message dispatched. %v", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for message.")
+	}
+}
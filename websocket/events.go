@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"fmt"
+
+	"github.com/gorilla/websocket"
+)
+
+// StreamError is an error reported by the ticker server in a message.
+type StreamError struct {
+	// Code is the acknowledgement code sent by the server, e.g. "nk".
+	Code string
+	// Message describes the error.
+	Message string
+	// Raw is the message as received from the server.
+	Raw map[string]interface{}
+}
+
+// Error implements the error interface.
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// CloseEvent describes the closing of the connection by the server.
+type CloseEvent struct {
+	// Code is the websocket close code, e.g. websocket.CloseNormalClosure.
+	Code int
+	// Reason is the close reason sent by the server.
+	Reason string
+}
+
+// IsNormal returns true if the connection was closed normally.
+func (e CloseEvent) IsNormal() bool {
+	return e.Code == websocket.CloseNormalClosure
+}
+
+// SetOnStreamError sets the callback invoked with the errors reported by the server.
+// They are delivered to the error callback as well.
+func (s *SocketClient) SetOnStreamError(f func(err *StreamError)) {
+	s.callbacks.onStreamError = f
+}
+
+// SetOnCloseEvent sets the callback invoked when the server closes the connection.
+func (s *SocketClient) SetOnCloseEvent(f func(event CloseEvent)) {
+	s.callbacks.onCloseEvent = f
+}
+
+// parseStreamError returns the error reported in the acknowledgement message, if any.
+func parseStreamError(message map[string]interface{}) *StreamError {
+	code, _ := message["ak"].(string)
+	if code != "nk" {
+		return nil
+	}
+
+	return &StreamError{
+		Code:    code,
+		Message: "Invalid feed token or client code",
+		Raw:     message,
+	}
+}
+
+func (s *SocketClient) triggerStreamError(err *StreamError) {
+	if s.callbacks.onStreamError != nil {
+		s.callbacks.onStreamError(err)
+	}
+	s.triggerError(err)
+}
+
+func (s *SocketClient) triggerCloseEvent(event CloseEvent) {
+	if s.callbacks.onCloseEvent != nil {
+		s.callbacks.onCloseEvent(event)
+	}
+}
@@ -0,0 +1,200 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newScriptedServer starts a ticker server which runs script after upgrading the connection.
+func newScriptedServer(script func(conn *websocket.Conn)) (*httptest.Server, url.URL) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		script(conn)
+	}))
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+	return server, *u
+}
+
+func TestStreamError(t *testing.T) {
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"nk","name":"cn"}]`))
+	})
+	defer server.Close()
+
+	client := New("test", "invalid_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	var streamErr *StreamError
+	client.SetOnStreamError(func(err *StreamError) {
+		streamErr = err
+	})
+	var genericErr error
+	client.OnError(func(err error) {
+		genericErr = err
+	})
+	client.Serve()
+
+	if streamErr == nil || streamErr.Code != "nk" {
+		t.Fatalf("Stream error is not delivered. %v", streamErr)
+	}
+	var target *StreamError
+	if !errors.As(genericErr, &target) {
+		t.Errorf("Stream error is not delivered to the error callback. %v", genericErr)
+	}
+}
+
+func TestCloseEvent(t *testing.T) {
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "maintenance"))
+		_, _, _ = conn.ReadMessage()
+	})
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	events := make(chan CloseEvent, 1)
+	client.SetOnCloseEvent(func(event CloseEvent) {
+		events <- event
+	})
+	go client.Serve()
+
+	select {
+	case event := <-events:
+		if event.Code != websocket.CloseGoingAway || event.Reason != "maintenance" || event.IsNormal() {
+			t.Errorf("Unexpected close event. %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for close event.")
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	received := make(chan string, 10)
+	server, u := newRecordingServer(received)
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetHeartbeatInterval(20 * time.Millisecond)
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	select {
+	case msg := <-received:
+		if !strings.Contains(msg, `"task":"hb"`) {
+			t.Errorf("Unexpected request instead of heartbeat. %s", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for heartbeat.")
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	// Server acknowledges the connection and goes silent without reading pings.
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		time.Sleep(5 * time.Second)
+	})
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetHeartbeatInterval(0)
+	client.SetIdleTimeout(100 * time.Millisecond)
+
+	served := make(chan struct{})
+	go func() {
+		client.Serve()
+		close(served)
+	}()
+
+	select {
+	case <-served:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Idle connection is not closed.")
+	}
+}
+
+func TestServeContext(t *testing.T) {
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"nk","name":"cn"}]`))
+	})
+
+	client := New("test", "invalid_token", "", WithURL(u))
+	var streamErr *StreamError
+	if err := client.ServeContext(context.Background()); !errors.As(err, &streamErr) {
+		t.Errorf("Expected stream error, got %v", err)
+	}
+	select {
+	case err := <-client.Err():
+		if !errors.As(err, &streamErr) {
+			t.Errorf("Unexpected error on the error channel. %v", err)
+		}
+	default:
+		t.Errorf("Stream error is not delivered on the error channel.")
+	}
+
+	// Reconnect attempts are exhausted once the server is gone.
+	server.Close()
+	client = New("test", "test_token", "", WithURL(u), WithBackoff(reconnectMinDelay, 1))
+	client.reconnectMaxDelay = 10 * time.Millisecond
+	if err := client.ServeContext(context.Background()); !errors.Is(err, ErrReconnectAttemptsExceeded) {
+		t.Errorf("Expected ErrReconnectAttemptsExceeded, got %v", err)
+	}
+}
+
+func TestServeContextCancel(t *testing.T) {
+	server, u := newMockServer()
+	defer server.Close()
+
+	client := New("test", "test_token", "", WithURL(u))
+	ctx, cancel := context.WithCancel(context.Background())
+	client.OnConnect(cancel)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- client.ServeContext(ctx)
+	}()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ServeContext did not return after cancel.")
+	}
+}
@@ -0,0 +1,24 @@
+package websocket
+
+// SetTickFilter sets a predicate which is applied to every tick before it is dispatched,
+// ticks for which it returns false are dropped. Messages which aren't ticks are not filtered.
+// The filter runs on the read loop, so it should be cheap.
+func (s *SocketClient) SetTickFilter(f func(tick map[string]interface{}) bool) {
+	s.tickFilter = f
+}
+
+// filterTicks removes the ticks rejected by the tick filter from the message.
+func (s *SocketClient) filterTicks(message []map[string]interface{}) []map[string]interface{} {
+	if s.tickFilter == nil {
+		return message
+	}
+
+	filtered := message[:0]
+	for _, m := range message {
+		if _, ok := m["tk"]; ok && !s.tickFilter(m) {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+	return filtered
+}
@@ -0,0 +1,48 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTickFilter(t *testing.T) {
+	server, u := newMockServer(`[{"name":"sf","tk":"1"},{"name":"sf","tk":"2"},{"name":"tm"}]`, `[{"name":"sf","tk":"1"}]`, `[{"name":"sf","tk":"2"}]`)
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|1", WithURL(u), WithAutoReconnect(false))
+	client.SetTickFilter(func(tick map[string]interface{}) bool {
+		return tick["tk"] != "1"
+	})
+	messages := make(chan []map[string]interface{}, 10)
+	client.OnMessage(func(message []map[string]interface{}) {
+		messages <- message
+	})
+	client.OnConnect(func() {
+		_ = client.Subscribe()
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	for _, expected := range []int{2, 1} {
+		select {
+		case message := <-messages:
+			if len(message) != expected {
+				t.Errorf("Expected %d messages, got %v", expected, message)
+			}
+			for _, m := range message {
+				if m["tk"] == "1" {
+					t.Errorf("Filtered tick delivered. %v", m)
+				}
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for message.")
+		}
+	}
+
+	select {
+	case message := <-messages:
+		t.Errorf("Unexpected message %v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
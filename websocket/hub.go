@@ -0,0 +1,133 @@
+package websocket
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Hub shares a single ticker connection between several in-process subscribers. It subscribes
+// the union of the scrips the subscribers are interested in and fans every tick out to the
+// channels of the interested subscribers. The hub routes the ticks of those scrips through
+// AddHandler, so they aren't delivered to the message callback of the ticker.
+type Hub struct {
+	client   *SocketClient
+	interest map[string]map[*Subscriber]struct{}
+	mu       sync.RWMutex
+}
+
+// Subscriber receives the ticks of the scrips it added on C. Ticks are shared between
+// subscribers and must not be modified.
+type Subscriber struct {
+	C       <-chan map[string]interface{}
+	ticks   chan map[string]interface{}
+	hub     *Hub
+	scrips  map[string]struct{}
+	dropped atomic.Int64
+	closed  bool
+}
+
+// NewHub creates a hub on top of the ticker.
+func NewHub(client *SocketClient) *Hub {
+	return &Hub{
+		client:   client,
+		interest: make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber whose channel buffers up to bufferSize ticks.
+// Ticks are dropped for a subscriber whose channel is full, so a slow subscriber
+// doesn't hold up the others.
+func (h *Hub) Subscribe(bufferSize int) *Subscriber {
+	ticks := make(chan map[string]interface{}, bufferSize)
+	return &Subscriber{
+		C:      ticks,
+		ticks:  ticks,
+		hub:    h,
+		scrips: make(map[string]struct{}),
+	}
+}
+
+// Add registers the interest of the subscriber in the scrips in "exchange|token" format.
+// Scrips nobody was interested in yet are subscribed on the ticker.
+func (sub *Subscriber) Add(scrips ...string) error {
+	h := sub.hub
+	var added []string
+
+	h.mu.Lock()
+	if sub.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	for _, scrip := range scrips {
+		sub.scrips[scrip] = struct{}{}
+		subscribers, ok := h.interest[scrip]
+		if !ok {
+			subscribers = make(map[*Subscriber]struct{})
+			h.interest[scrip] = subscribers
+			added = append(added, scrip)
+		}
+		subscribers[sub] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	for _, scrip := range added {
+		scrip := scrip
+		h.client.AddHandler(scrip, func(tick map[string]interface{}) {
+			h.fanOut(scrip, tick)
+		})
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	return h.client.SubscribeScrips(added...)
+}
+
+// Remove drops the interest of the subscriber in the scrips. Since the feed has no unsubscribe
+// request, scrips nobody is interested in anymore stay subscribed but their ticks are discarded.
+func (sub *Subscriber) Remove(scrips ...string) {
+	h := sub.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, scrip := range scrips {
+		delete(sub.scrips, scrip)
+		if subscribers, ok := h.interest[scrip]; ok {
+			delete(subscribers, sub)
+		}
+	}
+}
+
+// Close removes all the interests of the subscriber and closes its channel.
+func (sub *Subscriber) Close() {
+	h := sub.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if sub.closed {
+		return
+	}
+	for scrip := range sub.scrips {
+		if subscribers, ok := h.interest[scrip]; ok {
+			delete(subscribers, sub)
+		}
+	}
+	sub.scrips = make(map[string]struct{})
+	sub.closed = true
+	close(sub.ticks)
+}
+
+// Dropped returns the number of ticks dropped because the channel of the subscriber was full.
+func (sub *Subscriber) Dropped() int64 {
+	return sub.dropped.Load()
+}
+
+// fanOut sends the tick to every subscriber interested in the scrip.
+func (h *Hub) fanOut(scrip string, tick map[string]interface{}) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.interest[scrip] {
+		select {
+		case sub.ticks <- tick:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHub(t *testing.T) {
+	received := make(chan string, 10)
+	server, u := newRecordingServer(received, `[{"name":"sf","e":"nse_cm","tk":"1"},{"name":"sf","e":"nse_cm","tk":"2"}]`)
+	defer server.Close()
+
+	client := New("test", "test_token", "", WithURL(u), WithAutoReconnect(false))
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+	<-connected
+
+	hub := NewHub(client)
+	first := hub.Subscribe(10)
+	second := hub.Subscribe(10)
+	if err := first.Add("nse_cm|1"); err != nil {
+		t.Fatalf("Error while adding scrips. %v", err)
+	}
+	<-received
+	if err := second.Add("nse_cm|1", "nse_cm|2"); err != nil {
+		t.Fatalf("Error while adding scrips. %v", err)
+	}
+
+	// Only the scrip nobody was interested in is subscribed.
+	if msg := <-received; !strings.Contains(msg, `"channel":"nse_cm|2"`) {
+		t.Errorf("Unexpected subscription %s", msg)
+	}
+
+	expect := func(sub *Subscriber, tokens ...string) {
+		for _, token := range tokens {
+			select {
+			case tick := <-sub.C:
+				if tick["tk"] != token {
+					t.Errorf("Expected tick of %s, got %v", token, tick)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Timed out waiting for tick of %s.", token)
+			}
+		}
+	}
+	// The first subscription sent ticks before the second subscriber was interested.
+	expect(first, "1", "1")
+	expect(second, "1", "2")
+
+	first.Close()
+	if _, ok := <-first.C; ok {
+		t.Errorf("Channel is not closed.")
+	}
+	first.Close()
+}
@@ -0,0 +1,26 @@
+package websocket
+
+// Logger is a leveled, structured logger. keyvals are alternating keys and values,
+// which makes *slog.Logger satisfy it.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// noopLogger discards all the logs.
+type noopLogger struct{}
+
+func (noopLogger) Debug(msg string, keyvals ...interface{}) {}
+func (noopLogger) Info(msg string, keyvals ...interface{})  {}
+func (noopLogger) Warn(msg string, keyvals ...interface{})  {}
+func (noopLogger) Error(msg string, keyvals ...interface{}) {}
+
+// SetLogger sets the logger, nothing is logged by default.
+func (s *SocketClient) SetLogger(l Logger) {
+	if l == nil {
+		l = noopLogger{}
+	}
+	s.logger = l
+}
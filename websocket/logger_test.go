@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingLogger records the logged messages prefixed with their level.
+type recordingLogger struct {
+	messages []string
+	mu       sync.Mutex
+}
+
+func (l *recordingLogger) log(level string, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf("%s %s", level, msg))
+}
+
+func (l *recordingLogger) Debug(msg string, keyvals ...interface{}) { l.log("DEBUG", msg) }
+func (l *recordingLogger) Info(msg string, keyvals ...interface{})  { l.log("INFO", msg) }
+func (l *recordingLogger) Warn(msg string, keyvals ...interface{})  { l.log("WARN", msg) }
+func (l *recordingLogger) Error(msg string, keyvals ...interface{}) { l.log("ERROR", msg) }
+
+func (l *recordingLogger) contains(message string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, m := range l.messages {
+		if m == message {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLogger(t *testing.T) {
+	server, u := newMockServer(`[{"name":"sf","tk":"3045"}]`, `not a frame`)
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := New("test", "test_token", "nse_cm|3045", WithURL(u), WithAutoReconnect(false), WithLogger(logger))
+	errs := make(chan error, 1)
+	client.OnError(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	client.OnConnect(func() {
+		_ = client.Subscribe()
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the parse error.")
+	}
+
+	for _, message := range []string{"INFO connected", "DEBUG subscribed", "WARN error decoding frame", "ERROR ticker error"} {
+		if !logger.contains(message) {
+			t.Errorf("%q is not logged. %v", message, logger.messages)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package websocket
+
+import (
+	"expvar"
+	"time"
+)
+
+// Metrics receives instrumentation events from the ticker. Implementations
+// must be safe for concurrent use as events are reported from several goroutines.
+type Metrics interface {
+	// MessageReceived is called for every message in a frame with the message name, e.g. "sf".
+	MessageReceived(name string)
+	// BytesReceived is called with the size of every frame read.
+	BytesReceived(n int)
+	// ParseError is called for every frame which can't be decoded.
+	ParseError()
+	// ReconnectAttempt is called before every reconnect attempt.
+	ReconnectAttempt()
+	// DispatchQueueDepth is called with the number of queued messages after every dispatch.
+	DispatchQueueDepth(depth int)
+	// CallbackLatency is called with the time between reading a frame and invoking the message callback.
+	CallbackLatency(d time.Duration)
+}
+
+// TickMetrics can be implemented by a Metrics implementation to be notified of every tick.
+type TickMetrics interface {
+	// TickReceived is called for every tick with its exchange segment, e.g. "nse_cm", and the time its frame was read.
+	TickReceived(exchange string, received time.Time)
+}
+
+// NoopMetrics is a Metrics implementation which discards all events.
+type NoopMetrics struct{}
+
+// MessageReceived implements Metrics.
+func (NoopMetrics) MessageReceived(string) {}
+
+// BytesReceived implements Metrics.
+func (NoopMetrics) BytesReceived(int) {}
+
+// ParseError implements Metrics.
+func (NoopMetrics) ParseError() {}
+
+// ReconnectAttempt implements Metrics.
+func (NoopMetrics) ReconnectAttempt() {}
+
+// DispatchQueueDepth implements Metrics.
+func (NoopMetrics) DispatchQueueDepth(int) {}
+
+// CallbackLatency implements Metrics.
+func (NoopMetrics) CallbackLatency(time.Duration) {}
+
+// ExpvarMetrics is a Metrics implementation which publishes the events as expvar variables.
+type ExpvarMetrics struct {
+	messages        *expvar.Map
+	bytes           *expvar.Int
+	parseErrors     *expvar.Int
+	reconnects      *expvar.Int
+	queueDepth      *expvar.Int
+	callbackLatency *expvar.Int
+}
+
+// NewExpvarMetrics creates the metrics and publishes them as an expvar map with the given name.
+// Like expvar.Publish it panics if the name is already registered.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		messages:        new(expvar.Map).Init(),
+		bytes:           new(expvar.Int),
+		parseErrors:     new(expvar.Int),
+		reconnects:      new(expvar.Int),
+		queueDepth:      new(expvar.Int),
+		callbackLatency: new(expvar.Int),
+	}
+
+	vars := expvar.NewMap(name)
+	vars.Set("messages_received", m.messages)
+	vars.Set("bytes_received", m.bytes)
+	vars.Set("parse_errors", m.parseErrors)
+	vars.Set("reconnect_attempts", m.reconnects)
+	vars.Set("dispatch_queue_depth", m.queueDepth)
+	vars.Set("callback_latency_ns", m.callbackLatency)
+	return m
+}
+
+// MessageReceived implements Metrics.
+func (m *ExpvarMetrics) MessageReceived(name string) {
+	m.messages.Add(name, 1)
+}
+
+// BytesReceived implements Metrics.
+func (m *ExpvarMetrics) BytesReceived(n int) {
+	m.bytes.Add(int64(n))
+}
+
+// ParseError implements Metrics.
+func (m *ExpvarMetrics) ParseError() {
+	m.parseErrors.Add(1)
+}
+
+// ReconnectAttempt implements Metrics.
+func (m *ExpvarMetrics) ReconnectAttempt() {
+	m.reconnects.Add(1)
+}
+
+// DispatchQueueDepth implements Metrics.
+func (m *ExpvarMetrics) DispatchQueueDepth(depth int) {
+	m.queueDepth.Set(int64(depth))
+}
+
+// CallbackLatency implements Metrics. Only the latest latency is kept.
+func (m *ExpvarMetrics) CallbackLatency(d time.Duration) {
+	m.callbackLatency.Set(int64(d))
+}
+
+// SetMetrics sets the instrumentation receiving ticker events.
+func (s *SocketClient) SetMetrics(m Metrics) {
+	if m == nil {
+		m = NoopMetrics{}
+	}
+	s.metrics = m
+	s.tickMetrics, _ = m.(TickMetrics)
+}
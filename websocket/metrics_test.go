@@ -0,0 +1,43 @@
+package websocket
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExpvarMetrics(t *testing.T) {
+	server, u := newMockServer(`[{"name":"sf","tk":"3045","ltp":"500.00"}]`)
+	defer server.Close()
+
+	metrics := NewExpvarMetrics(fmt.Sprintf("ticker_test_%d", time.Now().UnixNano()))
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetMetrics(metrics)
+
+	messages := make(chan struct{}, 1)
+	client.OnConnect(func() {
+		_ = client.Subscribe()
+	})
+	client.OnMessage(func(message []map[string]interface{}) {
+		messages <- struct{}{}
+	})
+	go client.Serve()
+
+	select {
+	case <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for message.")
+	}
+
+	if metrics.messages.Get("sf").String() != "1" {
+		t.Errorf("Messages received per name are not counted. %v", metrics.messages)
+	}
+	if metrics.bytes.Value() == 0 {
+		t.Errorf("Bytes received are not counted.")
+	}
+	if metrics.parseErrors.Value() != 0 {
+		t.Errorf("Unexpected parse errors counted.")
+	}
+}
@@ -0,0 +1,123 @@
+package websocket
+
+import (
+	"crypto/tls"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Option configures a ticker instance created with New.
+type Option func(*SocketClient)
+
+// WithURL sets the ticker root url.
+func WithURL(u url.URL) Option {
+	return func(s *SocketClient) {
+		s.SetRootURL(u)
+	}
+}
+
+// WithAutoReconnect enables or disables auto reconnect.
+func WithAutoReconnect(val bool) Option {
+	return func(s *SocketClient) {
+		s.SetAutoReconnect(val)
+	}
+}
+
+// WithBackoff sets the maximum auto reconnect delay and attempts.
+// A delay below the minimum reconnect delay is raised to the minimum.
+func WithBackoff(maxDelay time.Duration, maxRetries int) Option {
+	return func(s *SocketClient) {
+		if maxDelay < reconnectMinDelay {
+			maxDelay = reconnectMinDelay
+		}
+		s.reconnectMaxDelay = maxDelay
+		s.SetReconnectMaxRetries(maxRetries)
+	}
+}
+
+// WithConnectTimeout sets the default timeout for establishing the connection.
+func WithConnectTimeout(val time.Duration) Option {
+	return func(s *SocketClient) {
+		s.SetConnectTimeout(val)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used when dialing the ticker.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(s *SocketClient) {
+		s.SetTLSConfig(config)
+	}
+}
+
+// WithDialer sets the dialer used to establish the connection.
+func WithDialer(d *websocket.Dialer) Option {
+	return func(s *SocketClient) {
+		s.SetDialer(d)
+	}
+}
+
+// WithCompression enables or disables permessage-deflate negotiation.
+func WithCompression(val bool) Option {
+	return func(s *SocketClient) {
+		s.SetCompression(val)
+	}
+}
+
+// WithTCPKeepAlive sets the keep-alive period of the TCP connection.
+func WithTCPKeepAlive(val time.Duration) Option {
+	return func(s *SocketClient) {
+		s.SetTCPKeepAlive(val)
+	}
+}
+
+// WithBufferSizes sets the sizes of the read and write buffers of the connection.
+func WithBufferSizes(read int, write int) Option {
+	return func(s *SocketClient) {
+		s.SetBufferSizes(read, write)
+	}
+}
+
+// WithHeartbeat sets the heartbeat interval and the idle timeout.
+func WithHeartbeat(interval time.Duration, idleTimeout time.Duration) Option {
+	return func(s *SocketClient) {
+		s.SetHeartbeatInterval(interval)
+		s.SetIdleTimeout(idleTimeout)
+	}
+}
+
+// WithTextPing enables sending a text "ping" along with every heartbeat.
+func WithTextPing(val bool) Option {
+	return func(s *SocketClient) {
+		s.SetTextPing(val)
+	}
+}
+
+// WithReadTimeout sets the read timeout of the connection.
+func WithReadTimeout(val time.Duration) Option {
+	return func(s *SocketClient) {
+		s.SetReadTimeout(val)
+	}
+}
+
+// WithMetrics sets the metrics sink.
+func WithMetrics(m Metrics) Option {
+	return func(s *SocketClient) {
+		s.SetMetrics(m)
+	}
+}
+
+// WithTokenProvider sets the provider of the client code and feed token.
+func WithTokenProvider(p TokenProvider) Option {
+	return func(s *SocketClient) {
+		s.SetTokenProvider(p)
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(l Logger) Option {
+	return func(s *SocketClient) {
+		s.SetLogger(l)
+	}
+}
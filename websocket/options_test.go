@@ -0,0 +1,44 @@
+package websocket
+
+import (
+	"crypto/tls"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	u := url.URL{Scheme: "ws", Host: "localhost:8080", Path: "/stream"}
+	config := &tls.Config{ServerName: "example.com"}
+	client := New("test", "test_token", "", WithURL(u), WithAutoReconnect(false), WithBackoff(time.Second, 5),
+		WithConnectTimeout(3*time.Second), WithTLSConfig(config), WithHeartbeat(time.Second, 5*time.Second))
+
+	if client.url != u {
+		t.Errorf("Unexpected url %v", client.url)
+	}
+	if client.autoReconnect {
+		t.Errorf("Auto reconnect is enabled.")
+	}
+	if client.reconnectMaxDelay != reconnectMinDelay || client.reconnectMaxRetries != 5 {
+		t.Errorf("Unexpected backoff %v, %d", client.reconnectMaxDelay, client.reconnectMaxRetries)
+	}
+	if client.connectTimeout != 3*time.Second {
+		t.Errorf("Unexpected connect timeout %v", client.connectTimeout)
+	}
+	if client.tlsConfig != config {
+		t.Errorf("TLS config is not set.")
+	}
+	if client.heartbeatInterval != time.Second || client.idleTimeout != 5*time.Second {
+		t.Errorf("Unexpected heartbeat %v, %v", client.heartbeatInterval, client.idleTimeout)
+	}
+}
+
+func TestSetReconnectMaxDelay(t *testing.T) {
+	client := New("test", "test_token", "")
+	if err := client.SetReconnectMaxDelay(time.Second); err == nil {
+		t.Errorf("Expected error for a delay below the minimum.")
+	}
+	if err := client.SetReconnectMaxDelay(30 * time.Second); err != nil || client.reconnectMaxDelay != 30*time.Second {
+		t.Errorf("Error while setting reconnect max delay. %v", err)
+	}
+}
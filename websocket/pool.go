@@ -0,0 +1,217 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Pool shards scrip subscriptions across several ticker connections so that
+// more scrips can be subscribed than a single connection allows. Messages and
+// errors of all the connections are delivered through the pool callbacks.
+type Pool struct {
+	clients   []*SocketClient
+	assigned  [][]string
+	shardOf   map[string]int
+	ready     []bool
+	dead      []bool
+	callbacks poolCallbacks
+	mu        sync.Mutex
+}
+
+// poolCallbacks represents callbacks available in the pool.
+type poolCallbacks struct {
+	onMessage func([]map[string]interface{})
+	onError   func(error)
+}
+
+// NewPool creates a pool of size ticker connections.
+func NewPool(clientCode string, feedToken string, size int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+
+	p := &Pool{
+		clients:  make([]*SocketClient, size),
+		assigned: make([][]string, size),
+		shardOf:  make(map[string]int),
+		ready:    make([]bool, size),
+		dead:     make([]bool, size),
+	}
+
+	for i := range p.clients {
+		shard := i
+		c := New(clientCode, feedToken, "")
+		c.OnMessage(p.triggerMessage)
+		c.OnError(p.triggerError)
+		c.OnConnect(func() {
+			p.handleConnect(shard)
+		})
+		c.SetOnStateChange(func(from ConnectionState, to ConnectionState) {
+			if to != Connected {
+				p.mu.Lock()
+				p.ready[shard] = false
+				p.mu.Unlock()
+			}
+		})
+		c.OnNoReconnect(func(attempt int) {
+			p.rebalance(shard)
+		})
+		p.clients[i] = c
+	}
+
+	return p
+}
+
+// Configure applies f to every connection of the pool, e.g. to set the root url or
+// TLS configuration. Callbacks must be set on the pool and not on the connections.
+func (p *Pool) Configure(f func(c *SocketClient)) {
+	for _, c := range p.clients {
+		f(c)
+	}
+}
+
+// OnMessage callback.
+func (p *Pool) OnMessage(f func(message []map[string]interface{})) {
+	p.callbacks.onMessage = f
+}
+
+// OnError callback.
+func (p *Pool) OnError(f func(err error)) {
+	p.callbacks.onError = f
+}
+
+// Serve starts all the connections of the pool and blocks until all of them are done.
+func (p *Pool) Serve() {
+	var wg sync.WaitGroup
+	wg.Add(len(p.clients))
+	for _, c := range p.clients {
+		go func(c *SocketClient) {
+			defer wg.Done()
+			c.Serve()
+		}(c)
+	}
+	wg.Wait()
+}
+
+// Close closes all the connections of the pool.
+func (p *Pool) Close(ctx context.Context) error {
+	var firstErr error
+	for _, c := range p.clients {
+		if err := c.Close(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Subscribe assigns the scrips to the least loaded connections and subscribes them.
+// Scrips assigned to a connection which isn't connected yet are subscribed once it connects.
+// Scrips which are already assigned to a connection are skipped.
+func (p *Pool) Subscribe(scrips ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.assign(scrips)
+}
+
+// Subscriptions returns the number of scrips assigned to every connection of the pool.
+func (p *Pool) Subscriptions() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	counts := make([]int, len(p.assigned))
+	for i, scrips := range p.assigned {
+		counts[i] = len(scrips)
+	}
+	return counts
+}
+
+// assign distributes the scrips across the live connections. It must be called with the lock held.
+func (p *Pool) assign(scrips []string) error {
+	batches := make(map[int][]string)
+	for n, scrip := range scrips {
+		if _, ok := p.shardOf[scrip]; ok {
+			continue
+		}
+		shard := p.leastLoaded()
+		if shard < 0 {
+			p.sendBatches(batches)
+			return fmt.Errorf("%w: no connection in the pool can take %d more scrips", ErrSubscriptionLimitExceeded, len(scrips)-n)
+		}
+		p.assigned[shard] = append(p.assigned[shard], scrip)
+		p.shardOf[scrip] = shard
+		batches[shard] = append(batches[shard], scrip)
+	}
+
+	return p.sendBatches(batches)
+}
+
+// sendBatches subscribes the scrips on the connections which are ready.
+func (p *Pool) sendBatches(batches map[int][]string) error {
+	var firstErr error
+	for shard, batch := range batches {
+		if !p.ready[shard] {
+			continue
+		}
+		if err := p.clients[shard].sendSubscription(batch); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// leastLoaded returns the live connection with the least scrips which can take one more, or -1.
+func (p *Pool) leastLoaded() int {
+	shard := -1
+	for i, c := range p.clients {
+		if p.dead[i] || len(p.assigned[i]) >= c.scripLimit() {
+			continue
+		}
+		if shard < 0 || len(p.assigned[i]) < len(p.assigned[shard]) {
+			shard = i
+		}
+	}
+	return shard
+}
+
+// handleConnect subscribes all the scrips assigned to the connection.
+func (p *Pool) handleConnect(shard int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.ready[shard] = true
+	if len(p.assigned[shard]) > 0 {
+		_ = p.clients[shard].sendSubscription(p.assigned[shard])
+	}
+}
+
+// rebalance moves the scrips of a connection which gave up reconnecting to the other connections.
+func (p *Pool) rebalance(shard int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.dead[shard] = true
+	p.ready[shard] = false
+	scrips := p.assigned[shard]
+	p.assigned[shard] = nil
+	for _, scrip := range scrips {
+		delete(p.shardOf, scrip)
+	}
+
+	if err := p.assign(scrips); err != nil {
+		p.triggerError(err)
+	}
+}
+
+func (p *Pool) triggerMessage(message []map[string]interface{}) {
+	if p.callbacks.onMessage != nil {
+		p.callbacks.onMessage(message)
+	}
+}
+
+func (p *Pool) triggerError(err error) {
+	if p.callbacks.onError != nil {
+		p.callbacks.onError(err)
+	}
+}
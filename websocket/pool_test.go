@@ -0,0 +1,112 @@
+package websocket
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPoolSharding(t *testing.T) {
+	pool := NewPool("test", "test_token", 2)
+	pool.Configure(func(c *SocketClient) {
+		c.SetSubscriptionLimits(50, 2)
+	})
+
+	if err := pool.Subscribe("nse_cm|1", "nse_cm|2", "nse_cm|3"); err != nil {
+		t.Fatalf("Error while subscribing. %v", err)
+	}
+	if counts := pool.Subscriptions(); !reflect.DeepEqual(counts, []int{2, 1}) {
+		t.Errorf("Scrips are not sharded evenly. %v", counts)
+	}
+
+	err := pool.Subscribe("nse_cm|4", "nse_cm|5")
+	if !errors.Is(err, ErrSubscriptionLimitExceeded) {
+		t.Errorf("Expected ErrSubscriptionLimitExceeded, got %v.", err)
+	}
+	if counts := pool.Subscriptions(); !reflect.DeepEqual(counts, []int{2, 2}) {
+		t.Errorf("Scrips within the limit are not assigned. %v", counts)
+	}
+}
+
+func TestPoolRebalance(t *testing.T) {
+	pool := NewPool("test", "test_token", 3)
+	pool.Configure(func(c *SocketClient) {
+		c.SetSubscriptionLimits(50, 2)
+	})
+	_ = pool.Subscribe("nse_cm|1", "nse_cm|2", "nse_cm|3")
+
+	errs := make(chan error, 1)
+	pool.OnError(func(err error) {
+		errs <- err
+	})
+
+	// First connection gives up, its only scrip must move to the second connection.
+	pool.rebalance(0)
+	if counts := pool.Subscriptions(); !reflect.DeepEqual(counts, []int{0, 2, 1}) {
+		t.Errorf("Scrips are not rebalanced. %v", counts)
+	}
+
+	// Second connection gives up, its scrip doesn't fit anywhere.
+	pool.rebalance(1)
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrSubscriptionLimitExceeded) {
+			t.Errorf("Expected ErrSubscriptionLimitExceeded, got %v.", err)
+		}
+	default:
+		t.Errorf("Expected error when scrips don't fit in the pool.")
+	}
+}
+
+func TestPoolServe(t *testing.T) {
+	received := make(chan string, 10)
+	server, u := newRecordingServer(received, `[{"name":"sf","tk":"3045"}]`)
+	defer server.Close()
+
+	pool := NewPool("test", "test_token", 2)
+	pool.Configure(func(c *SocketClient) {
+		c.SetRootURL(u)
+		c.SetAutoReconnect(false)
+	})
+
+	messages := make(chan []map[string]interface{}, 10)
+	pool.OnMessage(func(message []map[string]interface{}) {
+		messages <- message
+	})
+	_ = pool.Subscribe("nse_cm|1", "nse_cm|2")
+	go pool.Serve()
+
+	var channels []string
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-received:
+			channels = append(channels, msg)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for subscriptions.")
+		}
+	}
+	joined := strings.Join(channels, " ")
+	if !strings.Contains(joined, `"channel":"nse_cm|1"`) || !strings.Contains(joined, `"channel":"nse_cm|2"`) {
+		t.Errorf("Scrips are not subscribed on separate connections. %v", channels)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-messages:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for messages.")
+		}
+	}
+}
+
+func TestPoolDeduplicate(t *testing.T) {
+	pool := NewPool("test", "test_token", 2)
+	_ = pool.Subscribe("nse_cm|1", "nse_cm|2", "nse_cm|1")
+	_ = pool.Subscribe("nse_cm|2")
+
+	if counts := pool.Subscriptions(); !reflect.DeepEqual(counts, []int{1, 1}) {
+		t.Errorf("Duplicate scrips are assigned. %v", counts)
+	}
+}
@@ -0,0 +1,143 @@
+// Package prometheus exposes the health of a ticker as Prometheus metrics.
+//
+//	collector := prometheus.NewCollector("smartapi")
+//	registry.MustRegister(collector)
+//	ticker.SetMetrics(collector)
+package prometheus
+
+import (
+	"sync"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector which receives the ticker events as websocket.Metrics.
+type Collector struct {
+	messages        *prom.CounterVec
+	bytes           prom.Counter
+	parseErrors     prom.Counter
+	reconnects      prom.Counter
+	queueDepth      prom.Gauge
+	callbackLatency prom.Histogram
+	lastTickAge     *prom.Desc
+
+	lastTick map[string]time.Time
+	mu       sync.Mutex
+	now      func() time.Time
+}
+
+// NewCollector creates a collector whose metrics are prefixed with namespace.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		messages: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ticker",
+			Name:      "messages_total",
+			Help:      "Number of messages received by message name.",
+		}, []string{"name"}),
+		bytes: prom.NewCounter(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ticker",
+			Name:      "received_bytes_total",
+			Help:      "Number of frame bytes received.",
+		}),
+		parseErrors: prom.NewCounter(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ticker",
+			Name:      "parse_errors_total",
+			Help:      "Number of frames which couldn't be decoded.",
+		}),
+		reconnects: prom.NewCounter(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ticker",
+			Name:      "reconnects_total",
+			Help:      "Number of reconnect attempts.",
+		}),
+		queueDepth: prom.NewGauge(prom.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "ticker",
+			Name:      "dispatch_queue_depth",
+			Help:      "Number of messages waiting for the message callback.",
+		}),
+		callbackLatency: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "ticker",
+			Name:      "callback_latency_seconds",
+			Help:      "Time between reading a frame and invoking the message callback.",
+			Buckets:   prom.ExponentialBuckets(0.0001, 4, 8),
+		}),
+		lastTickAge: prom.NewDesc(
+			prom.BuildFQName(namespace, "ticker", "last_tick_age_seconds"),
+			"Time since the last tick by exchange segment.",
+			[]string{"exchange"}, nil,
+		),
+		lastTick: make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	c.messages.Describe(ch)
+	c.bytes.Describe(ch)
+	c.parseErrors.Describe(ch)
+	c.reconnects.Describe(ch)
+	c.queueDepth.Describe(ch)
+	c.callbackLatency.Describe(ch)
+	ch <- c.lastTickAge
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.messages.Collect(ch)
+	c.bytes.Collect(ch)
+	c.parseErrors.Collect(ch)
+	c.reconnects.Collect(ch)
+	c.queueDepth.Collect(ch)
+	c.callbackLatency.Collect(ch)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for exchange, last := range c.lastTick {
+		ch <- prom.MustNewConstMetric(c.lastTickAge, prom.GaugeValue, now.Sub(last).Seconds(), exchange)
+	}
+}
+
+// MessageReceived implements websocket.Metrics.
+func (c *Collector) MessageReceived(name string) {
+	c.messages.WithLabelValues(name).Inc()
+}
+
+// BytesReceived implements websocket.Metrics.
+func (c *Collector) BytesReceived(n int) {
+	c.bytes.Add(float64(n))
+}
+
+// ParseError implements websocket.Metrics.
+func (c *Collector) ParseError() {
+	c.parseErrors.Inc()
+}
+
+// ReconnectAttempt implements websocket.Metrics.
+func (c *Collector) ReconnectAttempt() {
+	c.reconnects.Inc()
+}
+
+// DispatchQueueDepth implements websocket.Metrics.
+func (c *Collector) DispatchQueueDepth(depth int) {
+	c.queueDepth.Set(float64(depth))
+}
+
+// CallbackLatency implements websocket.Metrics.
+func (c *Collector) CallbackLatency(d time.Duration) {
+	c.callbackLatency.Observe(d.Seconds())
+}
+
+// TickReceived implements websocket.TickMetrics.
+func (c *Collector) TickReceived(exchange string, received time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastTick[exchange] = received
+}
@@ -0,0 +1,72 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shammishailaj/smartapigo/websocket"
+)
+
+// The collector must be usable as ticker metrics.
+var (
+	_ websocket.Metrics     = (*Collector)(nil)
+	_ websocket.TickMetrics = (*Collector)(nil)
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("smartapi")
+	now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	registry := prom.NewRegistry()
+	registry.MustRegister(c)
+
+	c.MessageReceived("sf")
+	c.MessageReceived("sf")
+	c.MessageReceived("tm")
+	c.BytesReceived(100)
+	c.ParseError()
+	c.ReconnectAttempt()
+	c.DispatchQueueDepth(3)
+	c.CallbackLatency(time.Millisecond)
+	c.TickReceived("nse_cm", now.Add(-2*time.Second))
+
+	expected := `
+# HELP smartapi_ticker_last_tick_age_seconds Time since the last tick by exchange segment.
+# TYPE smartapi_ticker_last_tick_age_seconds gauge
+smartapi_ticker_last_tick_age_seconds{exchange="nse_cm"} 2
+# HELP smartapi_ticker_messages_total Number of messages received by message name.
+# TYPE smartapi_ticker_messages_total counter
+smartapi_ticker_messages_total{name="sf"} 2
+smartapi_ticker_messages_total{name="tm"} 1
+# HELP smartapi_ticker_dispatch_queue_depth Number of messages waiting for the message callback.
+# TYPE smartapi_ticker_dispatch_queue_depth gauge
+smartapi_ticker_dispatch_queue_depth 3
+# HELP smartapi_ticker_reconnects_total Number of reconnect attempts.
+# TYPE smartapi_ticker_reconnects_total counter
+smartapi_ticker_reconnects_total 1
+# HELP smartapi_ticker_parse_errors_total Number of frames which couldn't be decoded.
+# TYPE smartapi_ticker_parse_errors_total counter
+smartapi_ticker_parse_errors_total 1
+# HELP smartapi_ticker_received_bytes_total Number of frame bytes received.
+# TYPE smartapi_ticker_received_bytes_total counter
+smartapi_ticker_received_bytes_total 100
+`
+	names := []string{
+		"smartapi_ticker_last_tick_age_seconds",
+		"smartapi_ticker_messages_total",
+		"smartapi_ticker_dispatch_queue_depth",
+		"smartapi_ticker_reconnects_total",
+		"smartapi_ticker_parse_errors_total",
+		"smartapi_ticker_received_bytes_total",
+	}
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), names...); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c, "smartapi_ticker_callback_latency_seconds"); n != 1 {
+		t.Errorf("Unexpected callback latency metrics %d", n)
+	}
+}
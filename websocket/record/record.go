@@ -0,0 +1,192 @@
+// Package record records raw ticker frames to a compact file format and
+// replays them through the ticker parser for backtesting.
+//
+// A recording starts with a header made of the magic bytes "SATR" and a
+// version byte, followed by one entry per frame:
+//
+//	received time  int64 unix nanoseconds, big endian
+//	message type   uint8 websocket message type
+//	length         uint32 payload length, big endian
+//	payload        raw frame as read from the connection
+package record
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shammishailaj/smartapigo/websocket"
+)
+
+const (
+	// Version of the recording format.
+	version byte = 1
+	// Size of the header of every frame entry.
+	entryHeaderSize = 8 + 1 + 4
+)
+
+var (
+	magic = []byte("SATR")
+
+	// ErrInvalidRecording is returned when a recording doesn't start with a valid header.
+	ErrInvalidRecording = errors.New("record: invalid recording header")
+)
+
+// Frame is a single recorded ticker frame.
+type Frame struct {
+	Received    time.Time
+	MessageType int
+	Payload     []byte
+}
+
+// Recorder writes ticker frames to an io.Writer. It is safe for concurrent use.
+type Recorder struct {
+	w      *bufio.Writer
+	closer io.Closer
+	mu     sync.Mutex
+}
+
+// NewRecorder creates a recorder writing to w and writes the recording header.
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	r := &Recorder{w: bufio.NewWriter(w)}
+	if c, ok := w.(io.Closer); ok {
+		r.closer = c
+	}
+
+	if _, err := r.w.Write(append(append([]byte{}, magic...), version)); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Create creates or truncates the named file and returns a recorder writing to it.
+func Create(name string) (*Recorder, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewRecorder(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Attach records every frame read by the ticker. Recording errors are passed to onError if it is not nil.
+func (r *Recorder) Attach(s *websocket.SocketClient, onError func(error)) {
+	s.OnFrame(func(messageType int, payload []byte, received time.Time) {
+		if err := r.Record(messageType, payload, received); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
+
+// Record writes a single frame.
+func (r *Recorder) Record(messageType int, payload []byte, received time.Time) error {
+	var header [entryHeaderSize]byte
+	binary.BigEndian.PutUint64(header[0:8], uint64(received.UnixNano()))
+	header[8] = byte(messageType)
+	binary.BigEndian.PutUint32(header[9:13], uint32(len(payload)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := r.w.Write(payload)
+	return err
+}
+
+// Flush writes any buffered frames to the underlying writer.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.w.Flush()
+}
+
+// Close flushes the buffered frames and closes the underlying writer if it is an io.Closer.
+func (r *Recorder) Close() error {
+	if err := r.Flush(); err != nil {
+		return err
+	}
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
+
+// Reader reads frames from a recording.
+type Reader struct {
+	r      *bufio.Reader
+	closer io.Closer
+}
+
+// NewReader creates a reader for the recording in r and validates its header.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: bufio.NewReader(r)}
+	if c, ok := r.(io.Closer); ok {
+		rd.closer = c
+	}
+
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(rd.r, header); err != nil {
+		return nil, ErrInvalidRecording
+	}
+	if !bytes.Equal(header[:len(magic)], magic) || header[len(magic)] != version {
+		return nil, ErrInvalidRecording
+	}
+	return rd, nil
+}
+
+// Open opens the named recording for reading.
+func Open(name string) (*Reader, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Next returns the next frame of the recording or io.EOF at the end of it.
+func (r *Reader) Next() (Frame, error) {
+	var header [entryHeaderSize]byte
+	if _, err := io.ReadFull(r.r, header[:]); err != nil {
+		return Frame{}, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[9:13]))
+	if _, err := io.ReadFull(r.r, payload); err != nil {
+		if err == io.EOF {
+			return Frame{}, io.ErrUnexpectedEOF
+		}
+		return Frame{}, err
+	}
+
+	return Frame{
+		Received:    time.Unix(0, int64(binary.BigEndian.Uint64(header[0:8]))),
+		MessageType: int(header[8]),
+		Payload:     payload,
+	}, nil
+}
+
+// Close closes the underlying reader if it is an io.Closer.
+func (r *Reader) Close() error {
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package record
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// encodeMessage encodes a JSON message the way the ticker server does.
+func encodeMessage(msg string) []byte {
+	var b bytes.Buffer
+	z := zlib.NewWriter(&b)
+	_, _ = z.Write([]byte(msg))
+	_ = z.Close()
+	return []byte(base64.StdEncoding.EncodeToString(b.Bytes()))
+}
+
+func TestRecordReplay(t *testing.T) {
+	var buf bytes.Buffer
+	recorder, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatalf("Error while creating recorder. %v", err)
+	}
+
+	start := time.Now()
+	frames := []string{
+		`[{"ak":"ok","name":"cn"}]`,
+		`[{"name":"sf","tk":"1"}]`,
+		`[{"name":"sf","tk":"2"}]`,
+	}
+	for i, frame := range frames {
+		if err := recorder.Record(websocket.TextMessage, encodeMessage(frame), start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("Error while recording frame. %v", err)
+		}
+	}
+	_ = recorder.Record(websocket.BinaryMessage, []byte{0x01}, start.Add(3*time.Second))
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Error while closing recorder. %v", err)
+	}
+
+	reader, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Error while opening recording. %v", err)
+	}
+
+	replayer := NewReplayer(reader)
+	// 3 seconds of recording replayed in 30ms.
+	replayer.SetSpeed(100)
+
+	var tokens []string
+	replayer.OnMessage(func(message []map[string]interface{}) {
+		tokens = append(tokens, message[0]["tk"].(string))
+	})
+	var raw [][]byte
+	replayer.OnRawMessage(func(messageType int, payload []byte) {
+		raw = append(raw, payload)
+	})
+
+	replayStart := time.Now()
+	if err := replayer.Replay(context.Background()); err != nil {
+		t.Fatalf("Error while replaying. %v", err)
+	}
+	if elapsed := time.Since(replayStart); elapsed < 30*time.Millisecond {
+		t.Errorf("Recording replayed faster than the requested speed. %v", elapsed)
+	}
+
+	if len(tokens) != 2 || tokens[0] != "1" || tokens[1] != "2" {
+		t.Errorf("Unexpected replayed messages. %v", tokens)
+	}
+	if len(raw) != 1 || !bytes.Equal(raw[0], []byte{0x01}) {
+		t.Errorf("Undecodable frame is not replayed as raw message. %v", raw)
+	}
+}
+
+func TestInvalidRecording(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("junk data"))); err != ErrInvalidRecording {
+		t.Errorf("Expected ErrInvalidRecording, got %v.", err)
+	}
+}
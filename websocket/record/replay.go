@@ -0,0 +1,130 @@
+package record
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shammishailaj/smartapigo/websocket"
+)
+
+// Replayer feeds recorded frames through the ticker parser and invokes the same
+// callbacks as the ticker, at real-time or accelerated speed.
+type Replayer struct {
+	reader    *Reader
+	speed     float64
+	callbacks callbacks
+}
+
+// callbacks represents callbacks available in the replayer.
+type callbacks struct {
+	onMessage    func([]map[string]interface{})
+	onRawMessage func(int, []byte)
+	onError      func(error)
+}
+
+// NewReplayer creates a replayer for the recording. Frames are replayed at real-time speed by default.
+func NewReplayer(r *Reader) *Replayer {
+	return &Replayer{
+		reader: r,
+		speed:  1,
+	}
+}
+
+// SetSpeed sets the replay speed relative to real-time, e.g. 10 replays ten times faster.
+// Zero or a negative speed replays the frames without any delay.
+func (r *Replayer) SetSpeed(speed float64) {
+	r.speed = speed
+}
+
+// OnMessage callback.
+func (r *Replayer) OnMessage(f func(message []map[string]interface{})) {
+	r.callbacks.onMessage = f
+}
+
+// OnRawMessage callback invoked with frames which can't be decoded.
+func (r *Replayer) OnRawMessage(f func(messageType int, payload []byte)) {
+	r.callbacks.onRawMessage = f
+}
+
+// OnError callback.
+func (r *Replayer) OnError(f func(err error)) {
+	r.callbacks.onError = f
+}
+
+// Replay replays the recording until its end or until ctx is done.
+func (r *Replayer) Replay(ctx context.Context) error {
+	var (
+		first     time.Time
+		startedAt time.Time
+	)
+
+	for {
+		frame, err := r.reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("record: reading frame: %w", err)
+		}
+
+		if first.IsZero() {
+			first = frame.Received
+			startedAt = time.Now()
+		}
+
+		// Wait until the frame is due relative to the first frame.
+		if r.speed > 0 {
+			due := startedAt.Add(time.Duration(float64(frame.Received.Sub(first)) / r.speed))
+			if wait := time.Until(due); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		messages, err := websocket.DecodeFrame(frame.Payload)
+		if err != nil {
+			r.triggerError(err)
+			r.triggerRawMessage(frame.MessageType, frame.Payload)
+			continue
+		}
+
+		// Skip acknowledgements just like the ticker does.
+		if len(messages) == 0 {
+			continue
+		}
+		if _, ok := messages[0]["ak"]; ok {
+			continue
+		}
+
+		r.triggerMessage(messages)
+	}
+}
+
+func (r *Replayer) triggerMessage(message []map[string]interface{}) {
+	if r.callbacks.onMessage != nil {
+		r.callbacks.onMessage(message)
+	}
+}
+
+func (r *Replayer) triggerRawMessage(messageType int, payload []byte) {
+	if r.callbacks.onRawMessage != nil {
+		r.callbacks.onRawMessage(messageType, payload)
+	}
+}
+
+func (r *Replayer) triggerError(err error) {
+	if r.callbacks.onError != nil {
+		r.callbacks.onError(err)
+	}
+}
@@ -0,0 +1,90 @@
+package websocket
+
+import "sync"
+
+// router routes the ticks of a scrip to the handler registered for it.
+type router struct {
+	handlers map[string]func(map[string]interface{})
+	mu       sync.RWMutex
+}
+
+func newRouter() *router {
+	return &router{handlers: make(map[string]func(map[string]interface{}))}
+}
+
+func (r *router) add(scrip string, handler func(map[string]interface{})) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[scrip] = handler
+}
+
+func (r *router) remove(scrip string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.handlers, scrip)
+}
+
+// route invokes the handlers of the routed ticks and returns the ticks without a handler.
+func (r *router) route(message []map[string]interface{}) []map[string]interface{} {
+	r.mu.RLock()
+	if len(r.handlers) == 0 {
+		r.mu.RUnlock()
+		return message
+	}
+
+	var rest []map[string]interface{}
+	type routed struct {
+		handler func(map[string]interface{})
+		tick    map[string]interface{}
+	}
+	var calls []routed
+	for _, tick := range message {
+		if handler, ok := r.handlers[tickScrip(tick)]; ok {
+			calls = append(calls, routed{handler, tick})
+			continue
+		}
+		rest = append(rest, tick)
+	}
+	r.mu.RUnlock()
+
+	for _, c := range calls {
+		c.handler(c.tick)
+	}
+	return rest
+}
+
+// tickScrip returns the scrip of a tick in "exchange|token" format.
+func tickScrip(tick map[string]interface{}) string {
+	exchange, _ := tick["e"].(string)
+	token, _ := tick["tk"].(string)
+	return exchange + "|" + token
+}
+
+// SubscribeWithHandler subscribes ticks for the scrip in "exchange|token" format and
+// delivers them to handler instead of the message callback.
+func (s *SocketClient) SubscribeWithHandler(scrip string, handler func(tick map[string]interface{})) error {
+	s.router.add(scrip, handler)
+	if err := s.SubscribeScrips(scrip); err != nil {
+		s.router.remove(scrip)
+		return err
+	}
+	return nil
+}
+
+// AddHandler delivers the ticks of the scrip in "exchange|token" format to handler
+// instead of the message callback. It replaces any handler already added for the scrip.
+func (s *SocketClient) AddHandler(scrip string, handler func(tick map[string]interface{})) {
+	s.router.add(scrip, handler)
+}
+
+// RemoveHandler removes the handler of the scrip, its ticks are delivered to the message callback again.
+func (s *SocketClient) RemoveHandler(scrip string) {
+	s.router.remove(scrip)
+}
+
+// deliver routes the message to the scrip handlers and the rest to the message callback.
+func (s *SocketClient) deliver(message []map[string]interface{}) {
+	if rest := s.router.route(message); len(rest) > 0 {
+		s.triggerMessage(rest)
+	}
+}
@@ -0,0 +1,68 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeWithHandler(t *testing.T) {
+	server, u := newMockServer(`[{"name":"sf","e":"nse_cm","tk":"1"},{"name":"sf","e":"nse_cm","tk":"2"}]`)
+	defer server.Close()
+
+	client := New("test", "test_token", "")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	routed := make(chan map[string]interface{}, 10)
+	messages := make(chan []map[string]interface{}, 10)
+	client.OnMessage(func(message []map[string]interface{}) {
+		messages <- message
+	})
+	client.OnConnect(func() {
+		if err := client.SubscribeWithHandler("nse_cm|1", func(tick map[string]interface{}) {
+			routed <- tick
+		}); err != nil {
+			t.Errorf("Error while subscribing with handler. %v", err)
+		}
+	})
+	go client.Serve()
+
+	select {
+	case tick := <-routed:
+		if tick["tk"] != "1" {
+			t.Errorf("Unexpected tick routed to handler. %v", tick)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for routed tick.")
+	}
+
+	select {
+	case message := <-messages:
+		if len(message) != 1 || message[0]["tk"] != "2" {
+			t.Errorf("Unexpected message delivered to message callback. %v", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for message.")
+	}
+}
+
+func TestRemoveHandler(t *testing.T) {
+	client := New("test", "test_token", "")
+
+	var routed int
+	client.AddHandler("nse_cm|1", func(tick map[string]interface{}) {
+		routed++
+	})
+	var delivered int
+	client.OnMessage(func(message []map[string]interface{}) {
+		delivered += len(message)
+	})
+
+	client.deliver([]map[string]interface{}{{"e": "nse_cm", "tk": "1"}})
+	client.RemoveHandler("nse_cm|1")
+	client.deliver([]map[string]interface{}{{"e": "nse_cm", "tk": "1"}})
+
+	if routed != 1 || delivered != 1 {
+		t.Errorf("Handler is not removed. routed %d, delivered %d", routed, delivered)
+	}
+}
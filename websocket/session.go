@@ -0,0 +1,51 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	smartapi "github.com/shammishailaj/smartapigo"
+)
+
+// ErrNoSession is returned when the REST client has no authenticated session.
+var ErrNoSession = errors.New("client has no session")
+
+// NewFromClient creates a ticker which takes the client code and feed token from the session
+// of an authenticated REST client. Before a reconnect the session is renewed through the
+// client once the server rejects the feed token or the access token has expired, so the
+// ticker keeps working after the feed token expires. The ticker connects to the
+// environment of the client unless set otherwise with WithURL, and traces with the
+// tracer of the client when it traces tickers too unless set with WithTracer.
+func NewFromClient(c *smartapi.Client, scrips string, opts ...Option) *SocketClient {
+	opts = append([]Option{WithURL(c.Environment().FeedURL)}, opts...)
+	if tracer, ok := c.Tracer().(Tracer); ok {
+		opts = append([]Option{WithTracer(tracer)}, opts...)
+	}
+	s := New(c.ClientCode(), c.FeedToken(), scrips, opts...)
+	s.SetTokenProvider(sessionTokenProvider(c))
+	return s
+}
+
+// sessionTokenProvider returns the session tokens of the client, renewing the session
+// when the server rejected the feed token or the access token has expired.
+func sessionTokenProvider(c *smartapi.Client) TokenProvider {
+	return func(ctx context.Context) (string, string, error) {
+		if c.RefreshToken() != "" && (CredentialsRejected(ctx) || sessionExpired(c, time.Now())) {
+			if _, err := c.RenewAccessTokenCtx(ctx, c.RefreshToken()); err != nil {
+				return "", "", err
+			}
+		}
+
+		if c.FeedToken() == "" {
+			return "", "", ErrNoSession
+		}
+		return c.ClientCode(), c.FeedToken(), nil
+	}
+}
+
+// sessionExpired reports whether the access token of the client has expired at now.
+func sessionExpired(c *smartapi.Client, now time.Time) bool {
+	expiry, ok := c.SessionExpiry()
+	return ok && !now.Before(expiry)
+}
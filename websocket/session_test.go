@@ -0,0 +1,181 @@
+package websocket
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jarcoal/httpmock"
+	smartapi "github.com/shammishailaj/smartapigo"
+)
+
+func TestNewFromClient(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewStringResponder(200, "127.0.0.1"))
+	transport.RegisterNoResponder(httpmock.InitialTransport.RoundTrip)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		feedToken := "expired"
+		if strings.HasSuffix(r.URL.Path, smartapi.URIUserSessionRenew) {
+			feedToken = "renewed"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"message":"SUCCESS","errorcode":"","data":{"jwtToken":"jwt","refreshToken":"refresh","feedToken":"` + feedToken + `"}}`))
+	}))
+	defer api.Close()
+
+	client := smartapi.NewWithHTTPClient("test", "test", "test", &http.Client{Transport: transport})
+	client.SetBaseURI(api.URL + "/")
+	if _, err := client.GenerateSession("test"); err != nil {
+		t.Fatalf("Error while generating session. %v", err)
+	}
+
+	tokens := make(chan string, 10)
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		tokens <- string(msg)
+		if !strings.Contains(string(msg), `"token":"renewed"`) {
+			_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"nk","name":"cn"}]`))
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		_, _, _ = conn.ReadMessage()
+	})
+	defer server.Close()
+
+	ticker := NewFromClient(client, "", WithURL(u))
+	ticker.reconnectMaxDelay = 10 * time.Millisecond
+	connected := make(chan struct{})
+	ticker.OnConnect(func() {
+		close(connected)
+	})
+	go ticker.Serve()
+	defer ticker.Close(context.Background())
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for connection with the renewed session.")
+	}
+	if first := <-tokens; !strings.Contains(first, `"token":"expired","user": "test"`) {
+		t.Errorf("Unexpected first connection request %s", first)
+	}
+	if client.FeedToken() != "renewed" {
+		t.Errorf("Session is not renewed through the client.")
+	}
+}
+
+func TestNewFromClientRenewal(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewStringResponder(200, "127.0.0.1"))
+	transport.RegisterNoResponder(httpmock.InitialTransport.RoundTrip)
+
+	// The access token of the session expired at the unix epoch.
+	expired := "e30." + base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1}`)) + ".sig"
+	var renewals atomic.Int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwt, feedToken := expired, "feed"
+		if strings.HasSuffix(r.URL.Path, smartapi.URIUserSessionRenew) {
+			renewals.Add(1)
+			jwt, feedToken = "jwt", "renewed"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"message":"SUCCESS","errorcode":"","data":{"jwtToken":"` + jwt + `","refreshToken":"refresh","feedToken":"` + feedToken + `"}}`))
+	}))
+	defer api.Close()
+
+	client := smartapi.NewWithHTTPClient("test", "test", "test", &http.Client{Transport: transport})
+	client.SetBaseURI(api.URL + "/")
+	if _, err := client.GenerateSession("test"); err != nil {
+		t.Fatalf("Error while generating session. %v", err)
+	}
+
+	// The connection drops twice right after it is established.
+	var dials atomic.Int32
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		if dials.Add(1) <= 2 {
+			return
+		}
+		_, _, _ = conn.ReadMessage()
+	})
+	defer server.Close()
+
+	ticker := NewFromClient(client, "", WithURL(u))
+	ticker.reconnectMaxDelay = 10 * time.Millisecond
+	connected := make(chan struct{})
+	var connects atomic.Int32
+	ticker.OnConnect(func() {
+		if connects.Add(1) == 3 {
+			close(connected)
+		}
+	})
+	go ticker.Serve()
+	defer ticker.Close(context.Background())
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for connection.")
+	}
+	if renewals.Load() != 1 || client.FeedToken() != "renewed" {
+		t.Errorf("Expected the expired session to be renewed once across reconnects, renewed %d times", renewals.Load())
+	}
+}
+
+func TestNewFromClientInvalidated(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, "https://myexternalip.com/raw", httpmock.NewStringResponder(200, "127.0.0.1"))
+	client := smartapi.NewWithHTTPClient("test", "test", "test", &http.Client{Transport: transport})
+	client.InvalidateSession()
+
+	ticker := NewFromClient(client, "", WithURL(url.URL{Scheme: "ws", Host: "127.0.0.1:1"}))
+	reconnects := 0
+	ticker.OnReconnect(func(attempt int, delay time.Duration) {
+		reconnects++
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ticker.ServeContext(context.Background())
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrNoSession) || reconnects != 0 {
+			t.Errorf("Expected the ticker to stop without reconnecting, got %v after %d reconnects", err, reconnects)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the ticker to stop.")
+	}
+}
+
+func TestNewFromClientEnvironment(t *testing.T) {
+	env, err := smartapi.Sandbox("http://localhost:8080")
+	if err != nil {
+		t.Fatalf("Error while creating sandbox. %v", err)
+	}
+	client := smartapi.New("test", "test", "test")
+	client.SetEnvironment(env)
+
+	if ticker := NewFromClient(client, ""); ticker.url != env.FeedURL {
+		t.Errorf("Expected the ticker to connect to %s, got %s", env.FeedURL.String(), ticker.url.String())
+	}
+	u := url.URL{Scheme: "ws", Host: "localhost:9090"}
+	if ticker := NewFromClient(client, "", WithURL(u)); ticker.url != u {
+		t.Errorf("Expected the ticker to connect to %s, got %s", u.String(), ticker.url.String())
+	}
+}
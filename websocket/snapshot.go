@@ -0,0 +1,42 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// snapshotVersion is the version of the subscription snapshot format.
+const snapshotVersion = 1
+
+// Snapshot represents the subscribed scrips of a ticker.
+type Snapshot struct {
+	Version int      `json:"version"`
+	Scrips  []string `json:"scrips"`
+}
+
+// Export returns the subscribed scrips as a JSON snapshot.
+func (s *SocketClient) Export() ([]byte, error) {
+	return json.Marshal(Snapshot{
+		Version: snapshotVersion,
+		Scrips:  s.subscribedScrips(),
+	})
+}
+
+// Import subscribes the scrips of a JSON snapshot created with Export.
+// If the ticker isn't connected yet, the scrips are subscribed once it connects.
+func (s *SocketClient) Import(data []byte) error {
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("Error decoding snapshot: %w", err)
+	}
+	if snapshot.Version != snapshotVersion {
+		return fmt.Errorf("Unsupported snapshot version %d", snapshot.Version)
+	}
+
+	err := s.SubscribeScrips(snapshot.Scrips...)
+	if errors.Is(err, ErrNotConnected) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package websocket
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImport(t *testing.T) {
+	client := New("test", "test_token", "")
+	_ = client.SubscribeScrips("nse_cm|1", "nse_cm|2")
+
+	data, err := client.Export()
+	if err != nil {
+		t.Fatalf("Error while exporting. %v", err)
+	}
+	if string(data) != `{"version":1,"scrips":["nse_cm|1","nse_cm|2"]}` {
+		t.Errorf("Unexpected snapshot %s", data)
+	}
+
+	received := make(chan string, 10)
+	server, u := newRecordingServer(received)
+	defer server.Close()
+
+	restored := New("test", "test_token", "", WithURL(u), WithAutoReconnect(false))
+	if err := restored.Import(data); err != nil {
+		t.Fatalf("Error while importing. %v", err)
+	}
+	go restored.Serve()
+	defer restored.Close(context.Background())
+
+	select {
+	case msg := <-received:
+		if !strings.Contains(msg, `"channel":"nse_cm|1&nse_cm|2"`) {
+			t.Errorf("Unexpected subscription %s", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for subscription.")
+	}
+
+	if err := restored.Import([]byte(`{"version":2}`)); err == nil {
+		t.Errorf("Expected error for unsupported version.")
+	}
+	if err := restored.Import([]byte(`{`)); err == nil {
+		t.Errorf("Expected error for invalid snapshot.")
+	}
+}
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// Location of the exchange, market hours are in its time.
+	exchangeLocation = time.FixedZone("IST", 5*60*60+30*60)
+)
+
+const (
+	// Default market open, as an offset from midnight in exchange time.
+	defaultMarketOpen time.Duration = 9*time.Hour + 15*time.Minute
+	// Default market close, as an offset from midnight in exchange time.
+	defaultMarketClose time.Duration = 15*time.Hour + 30*time.Minute
+	// Minimum interval in which the staleness check is performed.
+	minStaleCheckInterval time.Duration = 100 * time.Millisecond
+)
+
+// staleness keeps track of the last tick time of every subscribed scrip.
+type staleness struct {
+	lastSeen map[string]time.Time
+	stale    map[string]bool
+	mu       sync.Mutex
+}
+
+func newStaleness() *staleness {
+	return &staleness{
+		lastSeen: make(map[string]time.Time),
+		stale:    make(map[string]bool),
+	}
+}
+
+// track starts tracking the scrips as if a tick was received at the given time.
+func (st *staleness) track(scrips []string, at time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for _, scrip := range scrips {
+		st.lastSeen[scrip] = at
+		delete(st.stale, scrip)
+	}
+}
+
+// seen records a tick of the scrip, if it is tracked.
+func (st *staleness) seen(scrip string, at time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.lastSeen[scrip]; ok {
+		st.lastSeen[scrip] = at
+		delete(st.stale, scrip)
+	}
+}
+
+// reset stops tracking all the scrips.
+func (st *staleness) reset() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.lastSeen = make(map[string]time.Time)
+	st.stale = make(map[string]bool)
+}
+
+// check returns the scrips which became stale since the last check along with their last tick time.
+func (st *staleness) check(now time.Time, timeout time.Duration) map[string]time.Time {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stale := make(map[string]time.Time)
+	for scrip, lastSeen := range st.lastSeen {
+		if !st.stale[scrip] && now.Sub(lastSeen) > timeout {
+			st.stale[scrip] = true
+			stale[scrip] = lastSeen
+		}
+	}
+	return stale
+}
+
+// SetStaleTimeout sets the duration without ticks during market hours after which a
+// subscribed scrip is reported stale through the stale token callback. Zero disables the check.
+func (s *SocketClient) SetStaleTimeout(val time.Duration) {
+	s.staleTimeout = val
+}
+
+// SetMarketHours sets the market hours as offsets from midnight in exchange time,
+// the staleness check only runs on weekdays within them. Defaults to 09:15 to 15:30.
+func (s *SocketClient) SetMarketHours(open time.Duration, close time.Duration) {
+	s.marketOpen = open
+	s.marketClose = close
+}
+
+// SetOnStaleToken sets the callback invoked once when a subscribed scrip goes stale.
+// It is invoked again only after the scrip receives a tick and goes stale again.
+func (s *SocketClient) SetOnStaleToken(f func(scrip string, lastSeen time.Time)) {
+	s.callbacks.onStaleToken = f
+}
+
+// isMarketOpen returns true if the time is within market hours on a weekday.
+func (s *SocketClient) isMarketOpen(t time.Time) bool {
+	t = t.In(exchangeLocation)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, exchangeLocation)
+	offset := t.Sub(midnight)
+	return offset >= s.marketOpen && offset < s.marketClose
+}
+
+// watchStaleness periodically reports the scrips which went stale.
+func (s *SocketClient) watchStaleness(wg *sync.WaitGroup, done chan struct{}) {
+	defer wg.Done()
+	if s.staleTimeout <= 0 {
+		return
+	}
+
+	interval := s.staleTimeout / 2
+	if interval < minStaleCheckInterval {
+		interval = minStaleCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			if !s.isMarketOpen(now) {
+				// Ticks are not expected outside market hours.
+				s.staleness.track(s.subscribedScrips(), now)
+				continue
+			}
+			for scrip, lastSeen := range s.staleness.check(now, s.staleTimeout) {
+				s.triggerStaleToken(scrip, lastSeen)
+			}
+		}
+	}
+}
+
+func (s *SocketClient) triggerStaleToken(scrip string, lastSeen time.Time) {
+	if s.callbacks.onStaleToken != nil {
+		s.callbacks.onStaleToken(scrip, lastSeen)
+	}
+}
@@ -0,0 +1,54 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStalenessCheck(t *testing.T) {
+	st := newStaleness()
+	start := time.Date(2024, 1, 2, 10, 0, 0, 0, exchangeLocation)
+	st.track([]string{"nse_cm|1", "nse_cm|2"}, start)
+	st.seen("nse_cm|2", start.Add(4*time.Second))
+	// Untracked scrips are ignored.
+	st.seen("nse_cm|3", start.Add(4*time.Second))
+
+	stale := st.check(start.Add(6*time.Second), 5*time.Second)
+	if len(stale) != 1 || !stale["nse_cm|1"].Equal(start) {
+		t.Errorf("Unexpected stale scrips. %v", stale)
+	}
+
+	// Stale scrips are reported only once.
+	if stale := st.check(start.Add(7*time.Second), 5*time.Second); len(stale) != 0 {
+		t.Errorf("Stale scrip reported again. %v", stale)
+	}
+
+	// A tick resets the staleness.
+	st.seen("nse_cm|1", start.Add(8*time.Second))
+	stale = st.check(start.Add(14*time.Second), 5*time.Second)
+	if len(stale) != 2 || !stale["nse_cm|1"].Equal(start.Add(8*time.Second)) {
+		t.Errorf("Unexpected stale scrips. %v", stale)
+	}
+}
+
+func TestIsMarketOpen(t *testing.T) {
+	client := New("test", "test_token", "")
+	tests := []struct {
+		at   time.Time
+		open bool
+	}{
+		{time.Date(2024, 1, 2, 9, 14, 0, 0, exchangeLocation), false},
+		{time.Date(2024, 1, 2, 9, 15, 0, 0, exchangeLocation), true},
+		{time.Date(2024, 1, 2, 15, 29, 0, 0, exchangeLocation), true},
+		{time.Date(2024, 1, 2, 15, 30, 0, 0, exchangeLocation), false},
+		// Market hours are in exchange time.
+		{time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC), true},
+		// Saturday.
+		{time.Date(2024, 1, 6, 10, 0, 0, 0, exchangeLocation), false},
+	}
+	for _, tt := range tests {
+		if got := client.isMarketOpen(tt.at); got != tt.open {
+			t.Errorf("isMarketOpen(%v) = %v, expected %v", tt.at, got, tt.open)
+		}
+	}
+}
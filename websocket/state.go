@@ -0,0 +1,59 @@
+package websocket
+
+// ConnectionState represents the state of the ticker connection.
+type ConnectionState int32
+
+const (
+	// Connecting is the state while the initial connection is being established.
+	Connecting ConnectionState = iota
+	// Connected is the state once the connection is established and authorised.
+	Connected
+	// Reconnecting is the state while the connection is being re-established.
+	Reconnecting
+	// Closed is the state before Serve is called and after it returns.
+	Closed
+)
+
+// String returns the name of the connection state.
+func (c ConnectionState) String() string {
+	switch c {
+	case Connecting:
+		return "Connecting"
+	case Connected:
+		return "Connected"
+	case Reconnecting:
+		return "Reconnecting"
+	case Closed:
+		return "Closed"
+	default:
+		return "Unknown"
+	}
+}
+
+// ConnectionState returns the current state of the connection.
+func (s *SocketClient) ConnectionState() ConnectionState {
+	return ConnectionState(s.state.Load())
+}
+
+// IsConnected returns true if the connection is currently established.
+func (s *SocketClient) IsConnected() bool {
+	return s.ConnectionState() == Connected
+}
+
+// SetOnStateChange sets the callback invoked on every connection state transition.
+func (s *SocketClient) SetOnStateChange(f func(from ConnectionState, to ConnectionState)) {
+	s.callbacks.onStateChange = f
+}
+
+func (s *SocketClient) setState(state ConnectionState) {
+	prev := ConnectionState(s.state.Swap(int32(state)))
+	if prev != state {
+		s.triggerStateChange(prev, state)
+	}
+}
+
+func (s *SocketClient) triggerStateChange(from ConnectionState, to ConnectionState) {
+	if s.callbacks.onStateChange != nil {
+		s.callbacks.onStateChange(from, to)
+	}
+}
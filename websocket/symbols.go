@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoSymbolResolver is returned when symbols are subscribed without a symbol resolver.
+var ErrNoSymbolResolver = errors.New("symbol resolver is not set")
+
+// SymbolResolver returns the token of the trading symbol on the exchange, e.g. "NSE" and "SBIN-EQ".
+type SymbolResolver func(exchange string, symbol string) (token string, err error)
+
+// tickerExchanges maps the exchanges to their ticker segments.
+var tickerExchanges = map[string]string{
+	"NSE":   "nse_cm",
+	"NFO":   "nse_fo",
+	"BSE":   "bse_cm",
+	"BFO":   "bse_fo",
+	"MCX":   "mcx_fo",
+	"CDS":   "cde_fo",
+	"NCDEX": "ncx_fo",
+}
+
+// SetSymbolResolver sets the resolver used by SubscribeSymbols, typically backed by the instrument master.
+func (s *SocketClient) SetSymbolResolver(r SymbolResolver) {
+	s.symbolResolver = r
+}
+
+// SubscribeSymbols subscribes ticks for symbols in "EXCHANGE:SYMBOL" format, e.g. "NSE:SBIN-EQ".
+// Nothing is subscribed if any of the symbols can't be resolved.
+func (s *SocketClient) SubscribeSymbols(symbols ...string) error {
+	scrips, err := s.resolveSymbols(symbols)
+	if err != nil {
+		return err
+	}
+	return s.SubscribeScrips(scrips...)
+}
+
+// resolveSymbols converts the symbols into scrips in "exchange|token" format.
+func (s *SocketClient) resolveSymbols(symbols []string) ([]string, error) {
+	if s.symbolResolver == nil {
+		return nil, ErrNoSymbolResolver
+	}
+
+	scrips := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		parts := strings.SplitN(symbol, ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("Invalid symbol %q, expected EXCHANGE:SYMBOL", symbol)
+		}
+
+		exchange := strings.ToUpper(parts[0])
+		segment, ok := tickerExchanges[exchange]
+		if !ok {
+			return nil, fmt.Errorf("Unknown exchange %q of symbol %q", parts[0], symbol)
+		}
+
+		token, err := s.symbolResolver(exchange, parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("Error resolving symbol %q: %w", symbol, err)
+		}
+		scrips = append(scrips, segment+"|"+token)
+	}
+	return scrips, nil
+}
@@ -0,0 +1,42 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSubscribeSymbols(t *testing.T) {
+	client := New("test", "test_token", "")
+	if err := client.SubscribeSymbols("NSE:SBIN-EQ"); !errors.Is(err, ErrNoSymbolResolver) {
+		t.Errorf("Expected ErrNoSymbolResolver, got %v", err)
+	}
+
+	errUnknown := errors.New("unknown symbol")
+	client.SetSymbolResolver(func(exchange string, symbol string) (string, error) {
+		switch exchange + ":" + symbol {
+		case "NSE:SBIN-EQ":
+			return "3045", nil
+		case "NFO:NIFTY24MAYFUT":
+			return "35006", nil
+		}
+		return "", errUnknown
+	})
+
+	for _, symbols := range [][]string{{"NSE:SBIN-EQ", "NSE:UNKNOWN"}, {"SBIN-EQ"}, {"XYZ:SBIN-EQ"}} {
+		if err := client.SubscribeSymbols(symbols...); err == nil {
+			t.Errorf("Expected error for %v", symbols)
+		}
+	}
+	if len(client.subscribedScrips()) != 0 {
+		t.Errorf("Scrips subscribed although resolution failed. %v", client.subscribedScrips())
+	}
+
+	// The ticker isn't connected, the scrips are subscribed once it connects.
+	if err := client.SubscribeSymbols("NSE:SBIN-EQ", "nfo:NIFTY24MAYFUT"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Expected ErrNotConnected, got %v", err)
+	}
+	scrips := client.subscribedScrips()
+	if len(scrips) != 2 || scrips[0] != "nse_cm|3045" || scrips[1] != "nse_fo|35006" {
+		t.Errorf("Unexpected scrips %v", scrips)
+	}
+}
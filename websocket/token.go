@@ -0,0 +1,48 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+)
+
+// TokenProvider returns the current client code and feed token. CredentialsRejected
+// reports on ctx whether the server rejected the credentials of the previous attempt.
+type TokenProvider func(ctx context.Context) (clientCode string, feedToken string, err error)
+
+type credentialsRejectedKey struct{}
+
+// CredentialsRejected reports whether the server rejected the credentials of the
+// previous connection attempt, for a TokenProvider to tell when the session
+// needs to be renewed rather than the connection just retried.
+func CredentialsRejected(ctx context.Context) bool {
+	rejected, _ := ctx.Value(credentialsRejectedKey{}).(bool)
+	return rejected
+}
+
+// SetTokenProvider sets the provider which is called before every connection attempt,
+// so that a long running ticker picks up a renewed session once the feed token expires.
+// With a provider set, a connection rejected by the server is retried when auto reconnect is enabled.
+func (s *SocketClient) SetTokenProvider(p TokenProvider) {
+	s.tokenProvider = p
+}
+
+// refreshCredentials updates the client code and feed token from the token provider.
+// rejected tells whether the server rejected the credentials of the previous attempt.
+func (s *SocketClient) refreshCredentials(rejected bool) error {
+	if s.tokenProvider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), credentialsRejectedKey{}, rejected), s.connectTimeout)
+	defer cancel()
+	clientCode, feedToken, err := s.tokenProvider(ctx)
+	if err != nil {
+		return fmt.Errorf("Error refreshing feed token: %w", err)
+	}
+
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	s.clientCode = clientCode
+	s.feedToken = feedToken
+	return nil
+}
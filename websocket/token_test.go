@@ -0,0 +1,57 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestTokenProvider(t *testing.T) {
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if !strings.Contains(string(msg), `"token":"renewed"`) {
+			_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"nk","name":"cn"}]`))
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		_, _, _ = conn.ReadMessage()
+	})
+	defer server.Close()
+
+	var calls atomic.Int32
+	client := New("test", "expired", "", WithURL(u), WithTokenProvider(func(ctx context.Context) (string, string, error) {
+		switch calls.Add(1) {
+		case 1:
+			return "test", "expired", nil
+		case 2:
+			return "", "", errors.New("session unavailable")
+		default:
+			return "test", "renewed", nil
+		}
+	}))
+	client.reconnectMaxDelay = 10 * time.Millisecond
+
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for connection with the renewed token.")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Token provider called %d times, expected 3", calls.Load())
+	}
+}
@@ -0,0 +1,91 @@
+package websocket
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer traces the runs of ServeContext, e.g. with OpenTelemetry through the
+// otel package of the module.
+type Tracer interface {
+	// StartTicker is called when ServeContext starts with the address of the
+	// server. It returns the context of the run and the span of the run.
+	StartTicker(ctx context.Context, address string) (context.Context, Span)
+}
+
+// Span is the span of a run of ServeContext.
+type Span interface {
+	// Connect is called for every connection established.
+	Connect()
+	// Reconnect is called before every reconnect attempt with its delay.
+	Reconnect(attempt int, delay time.Duration)
+	// Subscribe is called when scrips are subscribed with the number of scrips
+	// added and the number of scrips subscribed in total.
+	Subscribe(scrips, subscribed int)
+	// End is called once the run stops with its error, if any.
+	End(err error)
+}
+
+// NoopTracer is a Tracer implementation which traces nothing.
+type NoopTracer struct{}
+
+// StartTicker implements Tracer.
+func (NoopTracer) StartTicker(ctx context.Context, address string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) Connect()                     {}
+func (noopSpan) Reconnect(int, time.Duration) {}
+func (noopSpan) Subscribe(int, int)           {}
+func (noopSpan) End(error)                    {}
+
+// SetTracer sets the tracer of the ticker, which traces every run of
+// ServeContext with its connects, reconnects and subscription changes. Nothing
+// is traced by default.
+func (s *SocketClient) SetTracer(t Tracer) {
+	if t == nil {
+		t = NoopTracer{}
+	}
+	s.tracer = t
+}
+
+// WithTracer sets the tracer.
+func WithTracer(t Tracer) Option {
+	return func(s *SocketClient) {
+		s.SetTracer(t)
+	}
+}
+
+// startSpan starts the span of a run of ServeContext.
+func (s *SocketClient) startSpan(ctx context.Context) context.Context {
+	ctx, span := s.tracer.StartTicker(ctx, s.url.Host)
+
+	s.traceMu.Lock()
+	s.span = span
+	s.traceMu.Unlock()
+	return ctx
+}
+
+// endSpan ends the span of a run of ServeContext with its error, if any.
+func (s *SocketClient) endSpan(err error) {
+	s.traceMu.Lock()
+	span := s.span
+	s.span = nil
+	s.traceMu.Unlock()
+
+	if span != nil {
+		span.End(err)
+	}
+}
+
+// currentSpan returns the span of the current run, if any.
+func (s *SocketClient) currentSpan() Span {
+	s.traceMu.Lock()
+	defer s.traceMu.Unlock()
+	if s.span == nil {
+		return noopSpan{}
+	}
+	return s.span
+}
@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingTracer struct {
+	mu     sync.Mutex
+	events []string
+	ended  bool
+}
+
+func (t *recordingTracer) StartTicker(ctx context.Context, address string) (context.Context, Span) {
+	return ctx, t
+}
+
+func (t *recordingTracer) record(event string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *recordingTracer) Connect() { t.record("connect") }
+
+func (t *recordingTracer) Reconnect(attempt int, delay time.Duration) {
+	t.record(fmt.Sprintf("reconnect %d", attempt))
+}
+
+func (t *recordingTracer) Subscribe(scrips, subscribed int) {
+	t.record(fmt.Sprintf("subscribe %d/%d", scrips, subscribed))
+}
+
+func (t *recordingTracer) End(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ended = true
+}
+
+func TestTracing(t *testing.T) {
+	server, u := newMockServer(`[{"name":"sf","tk":"3045","ltp":"500.00"}]`)
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := New("test", "test_token", "nse_cm|3045", WithTracer(tracer))
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	messages := make(chan struct{}, 1)
+	client.OnConnect(func() {
+		_ = client.Subscribe()
+	})
+	client.OnMessage(func(message []map[string]interface{}) {
+		select {
+		case messages <- struct{}{}:
+		default:
+		}
+	})
+	go client.Serve()
+
+	select {
+	case <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for message.")
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Error while closing ticker. %v", err)
+	}
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	if !tracer.ended || len(tracer.events) != 2 || tracer.events[0] != "connect" || tracer.events[1] != "subscribe 1/1" {
+		t.Errorf("Unexpected events %v, ended %v", tracer.events, tracer.ended)
+	}
+}
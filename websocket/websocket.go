@@ -1,32 +1,81 @@
 package websocket
 
 import (
-	"bytes"
-	"compress/zlib"
+	"context"
 	"crypto/tls"
-	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/websocket"
-	"io/ioutil"
 	"math"
+	"net"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type SocketClient struct {
-	Conn                *websocket.Conn
-	url                 url.URL
-	callbacks           callbacks
-	autoReconnect       bool
-	reconnectMaxRetries int
-	reconnectMaxDelay   time.Duration
-	connectTimeout      time.Duration
-	reconnectAttempt    int
-	scrips              string
-	feedToken           string
-	clientCode          string
+	Conn                   *websocket.Conn
+	url                    url.URL
+	callbacks              callbacks
+	autoReconnect          bool
+	reconnectMaxRetries    int
+	reconnectMaxDelay      time.Duration
+	connectTimeout         time.Duration
+	reconnectAttempt       int
+	scrips                 string
+	feedToken              string
+	clientCode             string
+	state                  atomic.Int32
+	tlsConfig              *tls.Config
+	dialer                 *websocket.Dialer
+	compression            bool
+	tcpKeepAlive           time.Duration
+	readBufferSize         int
+	writeBufferSize        int
+	tokenProvider          TokenProvider
+	readErr                error
+	errs                   chan error
+	logger                 Logger
+	tickFilter             func(tick map[string]interface{}) bool
+	symbolResolver         SymbolResolver
+	readTimeout            time.Duration
+	tickMetrics            TickMetrics
+	dispatchWorkers        int
+	dispatchQueueSize      int
+	overflowPolicy         OverflowPolicy
+	dispatcher             *dispatcher
+	subscriptions          []string
+	subscribed             map[string]struct{}
+	maxScripsPerRequest    int
+	maxScripsPerConnection int
+	subsMu                 sync.Mutex
+	closing                bool
+	closed                 chan struct{}
+	serving                bool
+	serveDone              chan struct{}
+	connMu                 sync.Mutex
+	writeMu                sync.Mutex
+	metrics                Metrics
+	paused                 atomic.Bool
+	heartbeatInterval      time.Duration
+	idleTimeout            time.Duration
+	lastActivity           atomic.Int64
+	lastPong               atomic.Int64
+	lastTextPing           atomic.Int64
+	textPing               bool
+	router                 *router
+	staleTimeout           time.Duration
+	marketOpen             time.Duration
+	marketClose            time.Duration
+	staleness              *staleness
+	waiters                waiters
+	tracer                 Tracer
+	span                   Span
+	traceMu                sync.Mutex
 }
 
 // callbacks represents callbacks available in ticker.
@@ -37,6 +86,14 @@ type callbacks struct {
 	onConnect     func()
 	onClose       func(int, string)
 	onError       func(error)
+	onStateChange func(ConnectionState, ConnectionState)
+	onDrop        func([]map[string]interface{})
+	onRawMessage  func(int, []byte)
+	onFrame       func(int, []byte, time.Time)
+	onStreamError func(*StreamError)
+	onCloseEvent  func(CloseEvent)
+	onStaleToken  func(string, time.Time)
+	onPong        func(time.Duration)
 }
 
 const (
@@ -49,26 +106,70 @@ const (
 	defaultReconnectMaxDelay time.Duration = 60000 * time.Millisecond
 	// Connect timeout for initial server handshake.
 	defaultConnectTimeout time.Duration = 7000 * time.Millisecond
+	// Default maximum number of scrips sent in a single subscription request.
+	defaultMaxScripsPerRequest = 50
+	// Default maximum number of scrips subscribed on a single connection.
+	defaultMaxScripsPerConnection = 1000
+	// Default interval in which the heartbeat is sent.
+	defaultHeartbeatInterval time.Duration = 30000 * time.Millisecond
+	// Default duration without any data after which the connection is re-established.
+	defaultIdleTimeout time.Duration = 90000 * time.Millisecond
 	// Interval in which the connection check is performed periodically.
 	connectionCheckInterval time.Duration = 10000 * time.Millisecond
+	// Application level heartbeat request and its reply.
+	textPing = "ping"
+	textPong = "pong"
+	// Number of errors buffered for Err.
+	errorBufferSize = 64
 )
 
 var (
+	// ErrSubscriptionLimitExceeded is returned when a subscription would exceed the scrips allowed per connection.
+	ErrSubscriptionLimitExceeded = errors.New("subscription limit exceeded")
+
+	// ErrNotConnected is returned when a frame is written without an established connection.
+	ErrNotConnected = errors.New("not connected")
+
+	// ErrReconnectAttemptsExceeded is returned by ServeContext once the reconnect attempts are exhausted.
+	ErrReconnectAttemptsExceeded = errors.New("maximum reconnect attempts exceeded")
+
 	// Default ticker url.
 	tickerURL = url.URL{Scheme: "wss", Host: "wsfeeds.angelbroking.com", Path: "/NestHtml5Mobile/socket/stream"}
 )
 
-// New creates a new ticker instance.
-func New(clientCode string, feedToken string, scrips string) *SocketClient {
+// New creates a new ticker instance configured with the given options.
+func New(clientCode string, feedToken string, scrips string, opts ...Option) *SocketClient {
 	sc := &SocketClient{
-		clientCode:          clientCode,
-		feedToken:           feedToken,
-		url:                 tickerURL,
-		autoReconnect:       true,
-		reconnectMaxDelay:   defaultReconnectMaxDelay,
-		reconnectMaxRetries: defaultReconnectMaxAttempts,
-		connectTimeout:      defaultConnectTimeout,
-		scrips:              scrips,
+		clientCode:             clientCode,
+		feedToken:              feedToken,
+		url:                    tickerURL,
+		autoReconnect:          true,
+		reconnectMaxDelay:      defaultReconnectMaxDelay,
+		reconnectMaxRetries:    defaultReconnectMaxAttempts,
+		connectTimeout:         defaultConnectTimeout,
+		scrips:                 scrips,
+		dispatchWorkers:        defaultDispatchWorkers,
+		dispatchQueueSize:      defaultDispatchQueueSize,
+		overflowPolicy:         OverflowBlock,
+		maxScripsPerRequest:    defaultMaxScripsPerRequest,
+		maxScripsPerConnection: defaultMaxScripsPerConnection,
+		metrics:                NoopMetrics{},
+		subscribed:             make(map[string]struct{}),
+		heartbeatInterval:      defaultHeartbeatInterval,
+		idleTimeout:            defaultIdleTimeout,
+		router:                 newRouter(),
+		marketOpen:             defaultMarketOpen,
+		marketClose:            defaultMarketClose,
+		staleness:              newStaleness(),
+		errs:                   make(chan error, errorBufferSize),
+		serveDone:              make(chan struct{}),
+		logger:                 noopLogger{},
+		tracer:                 NoopTracer{},
+	}
+	sc.state.Store(int32(Closed))
+
+	for _, opt := range opts {
+		opt(sc)
 	}
 
 	return sc
@@ -81,9 +182,55 @@ func (s *SocketClient) SetRootURL(u url.URL) {
 
 // SetAccessToken set access token.
 func (s *SocketClient) SetFeedToken(feedToken string) {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
 	s.feedToken = feedToken
 }
 
+// SetTLSConfig sets the TLS configuration used for the ticker connection.
+// By default the server certificate is verified against the system roots.
+func (s *SocketClient) SetTLSConfig(config *tls.Config) {
+	s.tlsConfig = config
+}
+
+// SetInsecureSkipVerify disables verification of the server certificate.
+// This should only be used for testing against servers with self-signed certificates.
+func (s *SocketClient) SetInsecureSkipVerify(val bool) {
+	if s.tlsConfig == nil {
+		s.tlsConfig = &tls.Config{}
+	} else {
+		s.tlsConfig = s.tlsConfig.Clone()
+	}
+	s.tlsConfig.InsecureSkipVerify = val
+}
+
+// SetDialer sets a custom dialer used to establish the ticker connection. This can
+// be used to route the connection through a proxy or to customise the network dialer.
+// HandshakeTimeout and TLSClientConfig of the dialer are only overridden when
+// they are unset on it and set on the client.
+func (s *SocketClient) SetDialer(d *websocket.Dialer) {
+	s.dialer = d
+}
+
+// SetCompression enables or disables permessage-deflate negotiation, it is disabled by default.
+// Compression saves bandwidth at the cost of CPU, disable it if the server rejects the extension.
+func (s *SocketClient) SetCompression(val bool) {
+	s.compression = val
+}
+
+// SetTCPKeepAlive sets the keep-alive period of the TCP connection, a negative value disables
+// keep-alives. It has no effect with a dialer which dials the connection itself.
+func (s *SocketClient) SetTCPKeepAlive(val time.Duration) {
+	s.tcpKeepAlive = val
+}
+
+// SetBufferSizes sets the sizes of the read and write buffers of the connection in bytes.
+// Zero keeps the default size of 4096 bytes.
+func (s *SocketClient) SetBufferSizes(read int, write int) {
+	s.readBufferSize = read
+	s.writeBufferSize = write
+}
+
 // SetConnectTimeout sets default timeout for initial connect handshake
 func (s *SocketClient) SetConnectTimeout(val time.Duration) {
 	s.connectTimeout = val
@@ -96,7 +243,7 @@ func (s *SocketClient) SetAutoReconnect(val bool) {
 
 // SetReconnectMaxDelay sets maximum auto reconnect delay.
 func (s *SocketClient) SetReconnectMaxDelay(val time.Duration) error {
-	if val > reconnectMinDelay {
+	if val < reconnectMinDelay {
 		return fmt.Errorf("ReconnectMaxDelay can't be less than %fms", reconnectMinDelay.Seconds()*1000)
 	}
 
@@ -104,6 +251,31 @@ func (s *SocketClient) SetReconnectMaxDelay(val time.Duration) error {
 	return nil
 }
 
+// SetHeartbeatInterval sets the interval in which the heartbeat is sent. Zero disables the heartbeat.
+func (s *SocketClient) SetHeartbeatInterval(val time.Duration) {
+	s.heartbeatInterval = val
+}
+
+// SetIdleTimeout sets the duration without any data or pong after which the
+// connection is considered dead and re-established. Zero disables the check.
+func (s *SocketClient) SetIdleTimeout(val time.Duration) {
+	s.idleTimeout = val
+}
+
+// SetReadTimeout sets the duration without any frame or pong after which a read fails
+// and the connection is re-established. The deadline is refreshed on every frame and pong,
+// it should be longer than the heartbeat interval. Zero disables the read deadline.
+func (s *SocketClient) SetReadTimeout(val time.Duration) {
+	s.readTimeout = val
+}
+
+// refreshReadDeadline moves the read deadline of the connection by the read timeout.
+func (s *SocketClient) refreshReadDeadline() {
+	if s.readTimeout > 0 {
+		_ = s.Conn.SetReadDeadline(time.Now().Add(s.readTimeout))
+	}
+}
+
 // SetReconnectMaxRetries sets maximum reconnect attempts.
 func (s *SocketClient) SetReconnectMaxRetries(val int) {
 	s.reconnectMaxRetries = val
@@ -139,144 +311,315 @@ func (s *SocketClient) OnNoReconnect(f func(attempt int)) {
 	s.callbacks.onNoReconnect = f
 }
 
+// SetOnRawMessage sets the callback invoked with the websocket message type and the
+// payload of every frame which can't be decoded, so that frames in a format not yet
+// supported by the library can still be captured and decoded by the application.
+func (s *SocketClient) SetOnRawMessage(f func(messageType int, payload []byte)) {
+	s.callbacks.onRawMessage = f
+}
+
+// OnFrame callback invoked with every frame read from the connection before it is decoded,
+// along with the time it was received. It can be used to record the raw feed.
+func (s *SocketClient) OnFrame(f func(messageType int, payload []byte, received time.Time)) {
+	s.callbacks.onFrame = f
+}
+
 // Serve starts the connection to ticker server. Since its blocking its recommended to use it in go routine.
 func (s *SocketClient) Serve() {
+	_ = s.ServeContext(context.Background())
+}
+
+// ServeContext is the same as Serve but stops when ctx is done, in which case ctx.Err() is returned.
+// It returns nil once Close is called, otherwise the terminal error which ended the connection loop.
+// Errors which are retried are only delivered through the error callback and Err.
+func (s *SocketClient) ServeContext(ctx context.Context) (err error) {
+	s.connMu.Lock()
+	s.closing = false
+	closed := make(chan struct{})
+	s.closed = closed
+	// Done of a previous run is already closed, start a new one.
+	select {
+	case <-s.serveDone:
+		s.serveDone = make(chan struct{})
+	default:
+	}
+	s.serving = true
+	done := s.serveDone
+	defer func() {
+		s.connMu.Lock()
+		s.serving = false
+		s.connMu.Unlock()
+		close(done)
+	}()
+	s.connMu.Unlock()
 
+	s.setState(Connecting)
+	defer s.setState(Closed)
+
+	ctx = s.startSpan(ctx)
+	defer func() { s.endSpan(err) }()
+
+	// Start the workers which invoke the message callback.
+	s.dispatcher = newDispatcher(s.dispatchWorkers, s.dispatchQueueSize, s.overflowPolicy, s.deliver, s.callbacks.onDrop, s.metrics)
+	defer s.dispatcher.stop()
+
+	// Close the connection once ctx is done.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			closeCtx, cancel := context.WithTimeout(context.Background(), s.connectTimeout)
+			defer cancel()
+			_ = s.Close(closeCtx)
+		case <-stop:
+		}
+	}()
+
+	var lastErr error
 	for {
+		if s.isClosing() {
+			return ctx.Err()
+		}
+
 		// If reconnect attempt exceeds max then close the loop
 		if s.reconnectAttempt > s.reconnectMaxRetries {
+			s.logger.Error("reconnect attempts exhausted", "attempts", s.reconnectAttempt, "error", lastErr)
 			s.triggerNoReconnect(s.reconnectAttempt)
-			return
+			return fmt.Errorf("%w: %v", ErrReconnectAttemptsExceeded, lastErr)
 		}
 		// If its a reconnect then wait exponentially based on reconnect attempt
 		if s.reconnectAttempt > 0 {
+			s.setState(Reconnecting)
 			nextDelay := time.Duration(math.Pow(2, float64(s.reconnectAttempt))) * time.Second
 			if nextDelay > s.reconnectMaxDelay {
 				nextDelay = s.reconnectMaxDelay
 			}
 
+			s.metrics.ReconnectAttempt()
+			s.logger.Warn("reconnecting", "attempt", s.reconnectAttempt, "delay", nextDelay)
 			s.triggerReconnect(s.reconnectAttempt, nextDelay)
 
 			// Close the previous connection if exists
 			if s.Conn != nil {
 				s.Conn.Close()
 			}
+
+			if !s.waitReconnect(ctx, closed, nextDelay) {
+				return ctx.Err()
+			}
 		}
-		// create a dialer
-		d := websocket.DefaultDialer
-		d.HandshakeTimeout = s.connectTimeout
-		d.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true,
-		}
-		conn, _, err := d.Dial(s.url.String(), nil)
-		if err != nil {
+		var streamErr *StreamError
+		if err := s.refreshCredentials(errors.As(lastErr, &streamErr)); err != nil {
 			s.triggerError(err)
-			// If auto reconnect is enabled then try reconneting else return error
-			if s.autoReconnect {
+			// A session which has ended can't be renewed by retrying.
+			if s.autoReconnect && !errors.Is(err, ErrNoSession) {
+				lastErr = err
 				s.reconnectAttempt++
 				continue
 			}
-			return
+			return err
 		}
 
-		err = conn.WriteMessage(websocket.TextMessage, []byte(`{"task":"cn","channel":"","token":"`+s.feedToken+`","user": "`+s.clientCode+`","acctid":"`+s.clientCode+`"}`))
+		conn, _, err := s.newDialer().DialContext(ctx, s.url.String(), nil)
 		if err != nil {
 			s.triggerError(err)
-			return
+			// If auto reconnect is enabled then try reconneting else return error
+			if s.autoReconnect {
+				lastErr = err
+				s.reconnectAttempt++
+				continue
+			}
+			return err
 		}
 
-		_, message, err := conn.ReadMessage()
-		if err != nil {
-			s.triggerError(err)
-			return
-		}
-		sDec, _ := base64.StdEncoding.DecodeString(string(message))
-		val, err := readSegment(sDec)
-		var result []map[string]interface{}
-		err = json.Unmarshal(val, &result)
+		result, err := s.handshake(conn)
 		if err != nil {
 			s.triggerError(err)
-			return
-		}
-		if len(result) == 0 {
-			s.triggerError(fmt.Errorf("Invalid Message"))
-			return
-		}
-
-		if _, ok := result[0]["ak"]; !ok {
-			s.triggerError(fmt.Errorf("Invalid Message"))
-			return
+			conn.Close()
+			return err
 		}
 
-		if val, ok := result[0]["ak"]; ok {
-			if val == "nk" {
-				s.triggerError(fmt.Errorf("Invalid feed token or client code"))
-				return
+		if streamErr := parseStreamError(result[0]); streamErr != nil {
+			s.triggerStreamError(streamErr)
+			conn.Close()
+			// Rejected credentials are fatal unless they can be refreshed.
+			if s.autoReconnect && s.tokenProvider != nil {
+				lastErr = streamErr
+				s.reconnectAttempt++
+				continue
 			}
+			return streamErr
 		}
 
-		// Close the connection when its done.
-		defer s.Conn.Close()
-
-		// Assign the current connection to the instance.
+		// Assign the current connection to the instance, unless the ticker was
+		// closed while connecting.
+		s.connMu.Lock()
+		if s.closing {
+			s.connMu.Unlock()
+			conn.Close()
+			return ctx.Err()
+		}
 		s.Conn = conn
+		s.connMu.Unlock()
+
+		// Resubscribe to stored scrips, each of them is sent exactly once.
+		_ = s.Resubscribe()
 
 		// Trigger connect callback.
+		s.logger.Info("connected", "url", s.url.String())
+		s.setState(Connected)
 		s.triggerConnect()
 
-		// Resubscribe to stored tokens
-		if s.reconnectAttempt > 0 {
-			_ = s.Resubscribe()
-		}
-
 		// Reset auto reconnect vars
 		s.reconnectAttempt = 0
 
 		// Set on close handler
 		s.Conn.SetCloseHandler(s.handleClose)
 
+		// Set on pong handler, pongs count as activity on the connection.
+		s.lastActivity.Store(time.Now().UnixNano())
+		s.Conn.SetPongHandler(s.handlePong)
+
 		var wg sync.WaitGroup
-		Restart := make(chan bool, 1)
+		done := make(chan struct{})
 		// Receive ticker data in a go routine.
-		wg.Add(1)
-		go s.readMessage(&wg, Restart)
+		wg.Add(4)
+		go s.readMessage(&wg, done)
 
-		// Run watcher to check last ping time and reconnect if required
-		if s.autoReconnect {
-			wg.Add(1)
-			go s.checkConnection(&wg, Restart)
-		}
+		// Watch subscribed scrips for missing ticks.
+		go s.watchStaleness(&wg, done)
+
+		// Send heartbeats to keep the connection alive.
+		go s.heartbeat(&wg, done)
+
+		// Run watcher to check last activity time and reconnect if required
+		go s.checkConnection(&wg, done)
 
 		// Wait for go routines to finish before doing next reconnect
 		wg.Wait()
+		s.Conn.Close()
+
+		if s.isClosing() {
+			return ctx.Err()
+		}
+		lastErr = s.readErr
+		if !s.autoReconnect {
+			return lastErr
+		}
+		s.setState(Reconnecting)
+	}
+}
+
+// waitReconnect waits for delay before the next reconnect attempt. It returns
+// false if ctx is done or the ticker is closed in the meantime.
+func (s *SocketClient) waitReconnect(ctx context.Context, closed chan struct{}, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-closed:
+		return false
+	case <-timer.C:
+		return !s.isClosing()
+	}
+}
+
+// handshake sends the connection request and returns the decoded acknowledgement.
+func (s *SocketClient) handshake(conn *websocket.Conn) ([]map[string]interface{}, error) {
+	if err := conn.WriteMessage(websocket.TextMessage, s.request("cn", "")); err != nil {
+		return nil, err
+	}
+
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	result, err := DecodeFrame(message)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("Invalid Message")
+	}
+	if _, ok := result[0]["ak"]; !ok {
+		return nil, fmt.Errorf("Invalid Message")
+	}
+	return result, nil
+}
+
+// newDialer returns a copy of the configured dialer so that the shared
+// websocket.DefaultDialer is never mutated.
+func (s *SocketClient) newDialer() *websocket.Dialer {
+	d := &websocket.Dialer{Proxy: http.ProxyFromEnvironment}
+	if s.dialer != nil {
+		*d = *s.dialer
+	}
+
+	if d.HandshakeTimeout == 0 {
+		d.HandshakeTimeout = s.connectTimeout
+	}
+	if d.TLSClientConfig == nil {
+		d.TLSClientConfig = s.tlsConfig
 	}
+	if s.compression {
+		d.EnableCompression = true
+	}
+	if s.tcpKeepAlive != 0 && d.NetDial == nil && d.NetDialContext == nil {
+		d.NetDialContext = (&net.Dialer{Timeout: s.connectTimeout, KeepAlive: s.tcpKeepAlive}).DialContext
+	}
+	if s.readBufferSize > 0 {
+		d.ReadBufferSize = s.readBufferSize
+	}
+	if s.writeBufferSize > 0 {
+		d.WriteBufferSize = s.writeBufferSize
+	}
+	return d
 }
 
 func (s *SocketClient) handleClose(code int, reason string) error {
 	s.triggerClose(code, reason)
+	s.triggerCloseEvent(CloseEvent{Code: code, Reason: reason})
 	return nil
 }
 
 // Trigger callback methods
 func (s *SocketClient) triggerError(err error) {
+	s.logger.Error("ticker error", "error", err)
 	if s.callbacks.onError != nil {
 		s.callbacks.onError(err)
 	}
+
+	// Drop the error rather than blocking when nobody drains the channel.
+	select {
+	case s.errs <- err:
+	default:
+	}
+}
+
+// Err returns a channel on which errors are delivered in addition to the error callback.
+// Errors are dropped while the channel is full.
+func (s *SocketClient) Err() <-chan error {
+	return s.errs
 }
 
 func (s *SocketClient) triggerClose(code int, reason string) {
+	s.logger.Info("connection closed", "code", code, "reason", reason)
 	if s.callbacks.onClose != nil {
 		s.callbacks.onClose(code, reason)
 	}
 }
 
 func (s *SocketClient) triggerConnect() {
+	s.currentSpan().Connect()
 	if s.callbacks.onConnect != nil {
 		s.callbacks.onConnect()
 	}
 }
 
 func (s *SocketClient) triggerReconnect(attempt int, delay time.Duration) {
+	s.currentSpan().Reconnect(attempt, delay)
 	if s.callbacks.onReconnect != nil {
 		s.callbacks.onReconnect(attempt, delay)
 	}
@@ -294,88 +637,407 @@ func (s *SocketClient) triggerMessage(message []map[string]interface{}) {
 	}
 }
 
-// Periodically check for last ping time and initiate reconnect if applicable.
-func (s *SocketClient) checkConnection(wg *sync.WaitGroup, Restart chan bool) {
+func (s *SocketClient) triggerRawMessage(messageType int, payload []byte) {
+	if s.callbacks.onRawMessage != nil {
+		s.callbacks.onRawMessage(messageType, payload)
+	}
+}
+
+func (s *SocketClient) triggerFrame(messageType int, payload []byte, received time.Time) {
+	if s.callbacks.onFrame != nil {
+		s.callbacks.onFrame(messageType, payload, received)
+	}
+}
+
+// Periodically check for last activity time and close the connection if it has been
+// idle for longer than the idle timeout so that it is re-established.
+func (s *SocketClient) checkConnection(wg *sync.WaitGroup, done chan struct{}) {
 	defer wg.Done()
-	switch {
-	case <-Restart:
+
+	interval := connectionCheckInterval
+	if s.idleTimeout > 0 && s.idleTimeout/2 < interval {
+		interval = s.idleTimeout / 2
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			idle := time.Since(time.Unix(0, s.lastActivity.Load()))
+			if s.idleTimeout > 0 && idle > s.idleTimeout {
+				s.triggerError(fmt.Errorf("No data received for %v, reconnecting", idle))
+				s.Conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// heartbeat sends the heartbeat request and a websocket ping every heartbeat interval.
+func (s *SocketClient) heartbeat(wg *sync.WaitGroup, done chan struct{}) {
+	defer wg.Done()
+	if s.heartbeatInterval <= 0 {
 		return
 	}
+
+	ticker := time.NewTicker(s.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			err := s.writeMessage(websocket.TextMessage, s.request("hb", ""))
+			if err == nil {
+				// The pong echoes the send time, which gives the round-trip time.
+				now := time.Now()
+				err = s.Conn.WriteControl(websocket.PingMessage, []byte(strconv.FormatInt(now.UnixNano(), 10)), now.Add(s.heartbeatInterval))
+			}
+			if err == nil && s.textPing {
+				s.lastTextPing.Store(time.Now().UnixNano())
+				err = s.writeMessage(websocket.TextMessage, []byte(textPing))
+			}
+			if err != nil {
+				s.triggerError(fmt.Errorf("Error sending heartbeat: %v", err))
+			}
+		}
+	}
+}
+
+func (s *SocketClient) handlePong(appData string) error {
+	now := time.Now()
+	s.lastActivity.Store(now.UnixNano())
+	s.lastPong.Store(now.UnixNano())
+	s.refreshReadDeadline()
+
+	if sent, err := strconv.ParseInt(appData, 10, 64); err == nil {
+		s.triggerPong(now.Sub(time.Unix(0, sent)))
+	}
+	return nil
+}
+
+// SetTextPing enables sending a text "ping" along with every heartbeat, which newer servers
+// expect in place of protocol level pings. Their text "pong" replies count as liveness.
+func (s *SocketClient) SetTextPing(val bool) {
+	s.textPing = val
+}
+
+// handleTextPong records the text pong reply to the last text ping.
+func (s *SocketClient) handleTextPong(received time.Time) {
+	s.lastPong.Store(received.UnixNano())
+	if sent := s.lastTextPing.Load(); sent != 0 {
+		s.triggerPong(received.Sub(time.Unix(0, sent)))
+	}
+}
+
+// SetOnPong sets the callback invoked with the round-trip time of every heartbeat ping.
+func (s *SocketClient) SetOnPong(f func(rtt time.Duration)) {
+	s.callbacks.onPong = f
+}
+
+// LastHeartbeat returns the time the last pong was received, zero if none was received yet.
+func (s *SocketClient) LastHeartbeat() time.Time {
+	last := s.lastPong.Load()
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+func (s *SocketClient) triggerPong(rtt time.Duration) {
+	if s.callbacks.onPong != nil {
+		s.callbacks.onPong(rtt)
+	}
 }
 
 // readMessage reads the data in a loop.
-func (s *SocketClient) readMessage(wg *sync.WaitGroup, Restart chan bool) {
+func (s *SocketClient) readMessage(wg *sync.WaitGroup, done chan struct{}) {
 	defer wg.Done()
+	defer close(done)
 	for {
-		_, msg, err := s.Conn.ReadMessage()
+		s.refreshReadDeadline()
+		messageType, msg, err := s.Conn.ReadMessage()
 		if err != nil {
-			s.triggerError(fmt.Errorf("Error reading data: %v", err))
-			Restart <- true
+			s.readErr = err
+			if !s.isClosing() {
+				s.triggerError(fmt.Errorf("Error reading data: %v", err))
+			}
 			return
 		}
+		received := time.Now()
+		s.lastActivity.Store(received.UnixNano())
+		s.metrics.BytesReceived(len(msg))
+		s.triggerFrame(messageType, msg, received)
 
-		sDec, _ := base64.StdEncoding.DecodeString(string(msg))
-		val, err := readSegment(sDec)
-		if err != nil {
-			s.triggerError(err)
-			return
+		// Application level pong, it isn't an encoded frame.
+		if messageType == websocket.TextMessage && string(msg) == textPong {
+			s.handleTextPong(received)
+			continue
 		}
 
-		var finalMessage []map[string]interface{}
-		err = json.Unmarshal(val, &finalMessage)
+		finalMessage, err := DecodeFrame(msg)
 		if err != nil {
+			s.logger.Warn("error decoding frame", "error", err, "bytes", len(msg))
+			s.metrics.ParseError()
 			s.triggerError(err)
-			return
+			s.triggerRawMessage(messageType, msg)
+			continue
+		}
+
+		for _, m := range finalMessage {
+			name, _ := m["name"].(string)
+			s.metrics.MessageReceived(name)
+			if _, ok := m["tk"]; ok {
+				s.staleness.seen(tickScrip(m), received)
+				s.waiters.confirm(tickScrip(m))
+				if s.tickMetrics != nil {
+					exchange, _ := m["e"].(string)
+					s.tickMetrics.TickReceived(exchange, received)
+				}
+			}
 		}
 
 		if len(finalMessage) == 0 {
 			continue
 		}
 
-		if val, ok := finalMessage[0]["ak"]; ok {
-			if val == "nk" {
-				s.triggerError(fmt.Errorf("Invalid feed token or client code"))
+		if _, ok := finalMessage[0]["ak"]; ok {
+			if streamErr := parseStreamError(finalMessage[0]); streamErr != nil {
+				s.waiters.fail(streamErr)
+				s.triggerStreamError(streamErr)
 			}
 			continue
 		}
 
-		// Trigger message.
-		s.triggerMessage(finalMessage)
+		// Drop the ticks while paused.
+		if s.paused.Load() {
+			continue
+		}
+
+		if finalMessage = s.filterTicks(finalMessage); len(finalMessage) == 0 {
+			continue
+		}
+
+		// Queue the message for the callback workers.
+		s.dispatcher.dispatch(finalMessage, received)
 
 	}
 }
 
-// Close tries to close the connection gracefully. If the server doesn't close it
-func (s *SocketClient) Close() error {
-	return s.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+// Pause stops delivering messages to the message callback while keeping the
+// connection and the subscriptions alive. Messages received while paused are
+// discarded. The feed has no unsubscribe request so pausing is client side only.
+func (s *SocketClient) Pause() {
+	s.paused.Store(true)
 }
 
-// Subscribe subscribes tick for the given list of tokens.
-func (s *SocketClient) Subscribe() error {
-	err := s.Conn.WriteMessage(websocket.TextMessage, []byte(`{"task":"mw","channel":"`+s.scrips+`","token":"`+s.feedToken+`","user": "`+s.clientCode+`","acctid":"`+s.clientCode+`"}`))
-	if err != nil {
-		s.triggerError(err)
+// Resume resumes delivering messages to the message callback after Pause.
+func (s *SocketClient) Resume() {
+	s.paused.Store(false)
+}
+
+// IsPaused returns true if message delivery is paused.
+func (s *SocketClient) IsPaused() bool {
+	return s.paused.Load()
+}
+
+// Close shuts the connection down gracefully. It forgets all the subscribed scrips,
+// since the feed has no unsubscribe request they end with the connection, writes a
+// close frame and waits for Serve to return. If ctx is done before the server closes
+// the connection, the underlying connection is closed forcefully and ctx.Err() is returned.
+// Close is safe to call several times and from several goroutines.
+func (s *SocketClient) Close(ctx context.Context) error {
+	s.connMu.Lock()
+	closing := s.closing
+	s.closing = true
+	if !closing && s.closed != nil {
+		close(s.closed)
+	}
+	conn := s.Conn
+	serving := s.serving
+	done := s.serveDone
+	s.connMu.Unlock()
+
+	s.subsMu.Lock()
+	s.subscriptions = nil
+	s.subscribed = make(map[string]struct{})
+	s.subsMu.Unlock()
+	s.staleness.reset()
+
+	if !serving {
+		return nil
+	}
+
+	// Only the first call sends the close frame, the others just wait. Without a
+	// connection yet, Serve stops once the connection attempt in progress ends.
+	var err error
+	if !closing && conn != nil {
+		s.writeMu.Lock()
+		err = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		s.writeMu.Unlock()
+		if err != nil {
+			conn.Close()
+		}
+	}
+
+	select {
+	case <-done:
 		return err
+	case <-ctx.Done():
+		if conn != nil {
+			conn.Close()
+		}
+		return ctx.Err()
 	}
+}
 
-	return nil
+// request builds the request frame of the task with the current credentials.
+func (s *SocketClient) request(task string, channel string) []byte {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	return []byte(`{"task":"` + task + `","channel":"` + channel + `","token":"` + s.feedToken + `","user": "` + s.clientCode + `","acctid":"` + s.clientCode + `"}`)
+}
+
+// writeMessage writes a frame on the current connection. The underlying connection
+// supports only one concurrent writer, so all the frames are written through here.
+func (s *SocketClient) writeMessage(messageType int, data []byte) error {
+	s.connMu.Lock()
+	conn := s.Conn
+	s.connMu.Unlock()
+	if conn == nil {
+		return ErrNotConnected
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return conn.WriteMessage(messageType, data)
+}
+
+// Done returns a channel which is closed once Serve has returned and all of its goroutines
+// have exited, including the workers invoking the message callback.
+func (s *SocketClient) Done() <-chan struct{} {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	return s.serveDone
 }
 
+func (s *SocketClient) isClosing() bool {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	return s.closing
+}
+
+// Subscribe subscribes ticks for the scrips the client was created with.
+func (s *SocketClient) Subscribe() error {
+	return s.SubscribeScrips(splitScrips(s.scrips)...)
+}
+
+// SubscribeScrips subscribes ticks for the given scrips in "exchange|token" format.
+// Scrips are sent in batches of at most the per request limit and
+// ErrSubscriptionLimitExceeded is returned when the connection limit would be exceeded.
+// Scrips which are already subscribed are skipped.
+func (s *SocketClient) SubscribeScrips(scrips ...string) error {
+	s.subsMu.Lock()
+	var added []string
+	seen := make(map[string]struct{}, len(scrips))
+	for _, scrip := range scrips {
+		if _, ok := s.subscribed[scrip]; ok {
+			continue
+		}
+		if _, ok := seen[scrip]; ok {
+			continue
+		}
+		seen[scrip] = struct{}{}
+		added = append(added, scrip)
+	}
+
+	if len(s.subscriptions)+len(added) > s.maxScripsPerConnection {
+		s.subsMu.Unlock()
+		return fmt.Errorf("%w: %d subscribed, %d requested, limit is %d", ErrSubscriptionLimitExceeded, len(s.subscriptions), len(added), s.maxScripsPerConnection)
+	}
+	for _, scrip := range added {
+		s.subscribed[scrip] = struct{}{}
+	}
+	s.subscriptions = append(s.subscriptions, added...)
+	total := len(s.subscriptions)
+	s.subsMu.Unlock()
+	s.staleness.track(added, time.Now())
+	if len(added) > 0 {
+		s.currentSpan().Subscribe(len(added), total)
+	}
+
+	return s.sendSubscription(added)
+}
+
+// Resubscribe subscribes all the previously subscribed scrips again.
+// It is called automatically whenever the connection is re-established.
 func (s *SocketClient) Resubscribe() error {
-	err := s.Subscribe()
-	return err
+	return s.sendSubscription(s.subscribedScrips())
 }
 
-func readSegment(data []byte) ([]byte, error) {
-	b := bytes.NewReader(data)
-	z, err := zlib.NewReader(b)
-	if err != nil {
-		return nil, err
+// subscribedScrips returns a copy of the subscribed scrips.
+func (s *SocketClient) subscribedScrips() []string {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+
+	scrips := make([]string, len(s.subscriptions))
+	copy(scrips, s.subscriptions)
+	return scrips
+}
+
+// SetSubscriptionLimits sets the maximum number of scrips sent in a single
+// subscription request and the maximum number of scrips per connection.
+func (s *SocketClient) SetSubscriptionLimits(perRequest int, perConnection int) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	s.maxScripsPerRequest = perRequest
+	s.maxScripsPerConnection = perConnection
+}
+
+// scripLimit returns the maximum number of scrips per connection.
+func (s *SocketClient) scripLimit() int {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	return s.maxScripsPerConnection
+}
+
+// sendSubscription writes the subscription requests for the scrips in batches.
+func (s *SocketClient) sendSubscription(scrips []string) error {
+	s.subsMu.Lock()
+	batchSize := s.maxScripsPerRequest
+	s.subsMu.Unlock()
+	if batchSize < 1 {
+		batchSize = len(scrips)
 	}
-	defer z.Close()
-	p, err := ioutil.ReadAll(z)
-	if err != nil {
-		return nil, err
+
+	for start := 0; start < len(scrips); start += batchSize {
+		end := start + batchSize
+		if end > len(scrips) {
+			end = len(scrips)
+		}
+
+		err := s.writeMessage(websocket.TextMessage, s.request("mw", strings.Join(scrips[start:end], "&")))
+		if err != nil {
+			s.triggerError(err)
+			return err
+		}
+		s.logger.Debug("subscribed", "scrips", end-start)
+	}
+
+	return nil
+}
+
+// splitScrips splits "&" separated scrips into a list.
+func splitScrips(scrips string) []string {
+	var list []string
+	for _, scrip := range strings.Split(scrips, "&") {
+		if scrip = strings.TrimSpace(scrip); scrip != "" {
+			list = append(list, scrip)
+		}
 	}
-	return p, nil
+	return list
 }
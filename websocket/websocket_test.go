@@ -0,0 +1,763 @@
+package websocket
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// encodeMessage encodes a JSON message the way the ticker server does.
+func encodeMessage(msg string) []byte {
+	var b bytes.Buffer
+	z := zlib.NewWriter(&b)
+	_, _ = z.Write([]byte(msg))
+	_ = z.Close()
+	return []byte(base64.StdEncoding.EncodeToString(b.Bytes()))
+}
+
+// newMockServer starts a ticker server which acknowledges the connection
+// and replies to every subscription with the given tick messages.
+func newMockServer(ticks ...string) (*httptest.Server, url.URL) {
+	server := httptest.NewServer(mockHandler(nil, ticks...))
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+	return server, *u
+}
+
+// newMockTLSServer is the same as newMockServer but serves over TLS.
+func newMockTLSServer(ticks ...string) (*httptest.Server, url.URL) {
+	server := httptest.NewTLSServer(mockHandler(nil, ticks...))
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "wss"
+	return server, *u
+}
+
+// newRecordingServer is the same as newMockServer but sends every
+// request received after the connection request to the channel.
+func newRecordingServer(received chan<- string, ticks ...string) (*httptest.Server, url.URL) {
+	server := httptest.NewServer(mockHandler(received, ticks...))
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+	return server, *u
+}
+
+func mockHandler(received chan<- string, ticks ...string) http.Handler {
+	upgrader := websocket.Upgrader{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Connection request
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if received != nil {
+				received <- string(msg)
+			}
+			for _, tick := range ticks {
+				_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(tick))
+			}
+		}
+	})
+}
+
+func TestConnectionState(t *testing.T) {
+	server, u := newMockServer()
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	if client.ConnectionState() != Closed || client.IsConnected() {
+		t.Errorf("Default connection state is not Closed. %v", client.ConnectionState())
+	}
+
+	states := make(chan ConnectionState, 10)
+	client.SetOnStateChange(func(from ConnectionState, to ConnectionState) {
+		states <- to
+	})
+
+	go client.Serve()
+
+	for _, expected := range []ConnectionState{Connecting, Connected} {
+		select {
+		case state := <-states:
+			if state != expected {
+				t.Errorf("Expected state %s, got %s.", expected, state)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for state %s.", expected)
+		}
+	}
+
+	if !client.IsConnected() {
+		t.Errorf("Client is not reported as connected.")
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	server, u := newMockTLSServer()
+	defer server.Close()
+
+	// Default client must verify the self-signed certificate and fail.
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	errs := make(chan error, 1)
+	client.OnError(func(err error) {
+		errs <- err
+	})
+	client.Serve()
+
+	select {
+	case <-errs:
+	default:
+		t.Errorf("Expected certificate verification error with default TLS config.")
+	}
+
+	// Trusting the server certificate must succeed.
+	client = New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetTLSConfig(&tls.Config{RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs})
+
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out connecting with custom TLS config.")
+	}
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	client := New("test", "test_token", "nse_cm|3045")
+	if client.tlsConfig != nil {
+		t.Errorf("Default TLS config is not nil.")
+	}
+
+	client.SetInsecureSkipVerify(true)
+	if client.tlsConfig == nil || !client.tlsConfig.InsecureSkipVerify {
+		t.Errorf("InsecureSkipVerify is not set properly.")
+	}
+}
+
+func TestSetDialer(t *testing.T) {
+	server, u := newMockServer()
+	defer server.Close()
+
+	dialed := make(chan struct{}, 1)
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetDialer(&websocket.Dialer{
+		NetDial: func(network, addr string) (net.Conn, error) {
+			dialed <- struct{}{}
+			return net.Dial(network, addr)
+		},
+	})
+
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out connecting with custom dialer.")
+	}
+
+	select {
+	case <-dialed:
+	default:
+		t.Errorf("Custom dialer is not used.")
+	}
+
+	if websocket.DefaultDialer.HandshakeTimeout != 45*time.Second || websocket.DefaultDialer.NetDial != nil {
+		t.Errorf("Default dialer is modified.")
+	}
+}
+
+func TestSocketTuning(t *testing.T) {
+	client := New("test", "test_token", "", WithTCPKeepAlive(time.Minute), WithBufferSizes(1<<16, 1<<12))
+	d := client.newDialer()
+	if d.NetDialContext == nil || d.ReadBufferSize != 1<<16 || d.WriteBufferSize != 1<<12 {
+		t.Errorf("Dialer is not tuned. %+v", d)
+	}
+
+	// A custom dialer which dials itself is left untouched.
+	netDial := func(network, addr string) (net.Conn, error) {
+		return net.Dial(network, addr)
+	}
+	client.SetDialer(&websocket.Dialer{NetDial: netDial})
+	if d := client.newDialer(); d.NetDialContext != nil {
+		t.Errorf("Keep-alive dialer overrides the custom dialer.")
+	}
+
+	server, u := newMockServer()
+	defer server.Close()
+	client = New("test", "test_token", "", WithURL(u), WithAutoReconnect(false), WithTCPKeepAlive(time.Minute), WithBufferSizes(1<<16, 1<<12))
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out connecting with the tuned dialer.")
+	}
+}
+
+func TestSubscriptionLimits(t *testing.T) {
+	received := make(chan string, 10)
+	server, u := newRecordingServer(received)
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|1&nse_cm|2&nse_cm|3&nse_cm|4&nse_cm|5")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetSubscriptionLimits(2, 6)
+
+	subscribed := make(chan error, 1)
+	client.OnConnect(func() {
+		subscribed <- client.Subscribe()
+	})
+	go client.Serve()
+
+	select {
+	case err := <-subscribed:
+		if err != nil {
+			t.Fatalf("Error while subscribing. %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for connection.")
+	}
+
+	for _, expected := range []string{"nse_cm|1&nse_cm|2", "nse_cm|3&nse_cm|4", "nse_cm|5"} {
+		select {
+		case msg := <-received:
+			if !strings.Contains(msg, `"channel":"`+expected+`"`) {
+				t.Errorf("Expected subscription for %s, got %s.", expected, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for subscription %s.", expected)
+		}
+	}
+
+	err := client.SubscribeScrips("nse_cm|6", "nse_cm|7")
+	if !errors.Is(err, ErrSubscriptionLimitExceeded) {
+		t.Errorf("Expected ErrSubscriptionLimitExceeded, got %v.", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	server, u := newMockServer()
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		_ = client.Subscribe()
+		close(connected)
+	})
+	served := make(chan struct{})
+	go func() {
+		client.Serve()
+		close(served)
+	}()
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for connection.")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Close(ctx); err != nil {
+		t.Errorf("Error while closing connection. %v", err)
+	}
+
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Errorf("Serve has not returned after Close.")
+	}
+
+	if client.ConnectionState() != Closed || len(client.subscriptions) != 0 {
+		t.Errorf("Connection is not closed properly.")
+	}
+}
+
+func TestCloseWhileConnecting(t *testing.T) {
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		time.Sleep(300 * time.Millisecond)
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		_, _, _ = conn.ReadMessage()
+	})
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|3045", WithURL(u))
+	connected := false
+	client.OnConnect(func() {
+		connected = true
+	})
+	go client.Serve()
+
+	// Close during the handshake waits for Serve to drop the connection.
+	time.Sleep(100 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Error while closing connection. %v", err)
+	}
+	select {
+	case <-client.Done():
+	default:
+		t.Fatalf("Close returned before Serve.")
+	}
+	if connected || client.Conn != nil {
+		t.Errorf("Connection established after Close.")
+	}
+}
+
+func TestReconnectDelay(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		dials []time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		dials = append(dials, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+
+	client := New("test", "test_token", "", WithURL(*u))
+	client.SetReconnectMaxRetries(2)
+	client.reconnectMaxDelay = 200 * time.Millisecond
+	if err := client.ServeContext(context.Background()); !errors.Is(err, ErrReconnectAttemptsExceeded) {
+		t.Fatalf("Expected ErrReconnectAttemptsExceeded, got %v", err)
+	}
+
+	mu.Lock()
+	times := append([]time.Time(nil), dials...)
+	mu.Unlock()
+	if len(times) != 3 {
+		t.Fatalf("Expected 3 dials, got %d", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < 200*time.Millisecond {
+			t.Errorf("Dial %d followed the previous one after %v, expected the reconnect delay", i+1, gap)
+		}
+	}
+
+	// Cancelling stops waiting for the next attempt.
+	client = New("test", "test_token", "", WithURL(*u))
+	client.reconnectMaxDelay = time.Minute
+	ctx, cancel := context.WithCancel(context.Background())
+	client.OnReconnect(func(int, time.Duration) {
+		cancel()
+	})
+	start := time.Now()
+	if err := client.ServeContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ServeContext returned %v after cancel", elapsed)
+	}
+}
+
+func TestRawMessage(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		_ = conn.WriteMessage(websocket.BinaryMessage, []byte{0x01, 0x02})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(*u)
+	client.SetAutoReconnect(false)
+
+	type rawMessage struct {
+		messageType int
+		payload     []byte
+	}
+	raw := make(chan rawMessage, 1)
+	client.SetOnRawMessage(func(messageType int, payload []byte) {
+		raw <- rawMessage{messageType, payload}
+	})
+	go client.Serve()
+
+	select {
+	case m := <-raw:
+		if m.messageType != websocket.BinaryMessage || !bytes.Equal(m.payload, []byte{0x01, 0x02}) {
+			t.Errorf("Unexpected raw message. %v", m)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for raw message.")
+	}
+}
+
+func TestResubscribeDeduplicates(t *testing.T) {
+	received := make(chan string, 10)
+	server, u := newRecordingServer(received)
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|1&nse_cm|2&nse_cm|1")
+	client.SetRootURL(u)
+
+	connects := make(chan struct{}, 2)
+	client.OnConnect(func() {
+		_ = client.Subscribe()
+		connects <- struct{}{}
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	expectSubscription := func() {
+		select {
+		case msg := <-received:
+			if !strings.Contains(msg, `"channel":"nse_cm|1&nse_cm|2"`) {
+				t.Errorf("Unexpected subscription. %s", msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for subscription.")
+		}
+	}
+
+	<-connects
+	expectSubscription()
+
+	if err := client.SubscribeScrips("nse_cm|2"); err != nil {
+		t.Errorf("Error while subscribing already subscribed scrip. %v", err)
+	}
+
+	// Drop the connection to force a reconnect.
+	client.Conn.Close()
+	<-connects
+	expectSubscription()
+
+	select {
+	case msg := <-received:
+		t.Errorf("Scrips are subscribed more than once. %s", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	server, u := newMockServer(`[{"name":"sf","tk":"3045"}]`)
+	defer server.Close()
+
+	client := New("test", "test_token", "nse_cm|3045")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	messages := make(chan []map[string]interface{}, 10)
+	client.OnMessage(func(message []map[string]interface{}) {
+		messages <- message
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+	<-connected
+
+	client.Pause()
+	if !client.IsPaused() {
+		t.Errorf("Client is not paused.")
+	}
+	_ = client.SubscribeScrips("nse_cm|1")
+
+	select {
+	case <-messages:
+		t.Errorf("Message delivered while paused.")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	client.Resume()
+	_ = client.SubscribeScrips("nse_cm|2")
+
+	select {
+	case <-messages:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Message not delivered after resume.")
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	received := make(chan string, 1000)
+	server, u := newRecordingServer(received)
+	defer server.Close()
+
+	client := New("test", "test_token", "")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetHeartbeatInterval(time.Millisecond)
+	client.SetSubscriptionLimits(1, 1000)
+
+	if err := client.SubscribeScrips("nse_cm|0"); err != ErrNotConnected {
+		t.Errorf("Expected ErrNotConnected before connecting, got %v", err)
+	}
+
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+	<-connected
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if err := client.SubscribeScrips(fmt.Sprintf("nse_cm|%d", i*10+j+1)); err != nil {
+					t.Errorf("Error while subscribing. %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	subscribed := 0
+	timeout := time.After(5 * time.Second)
+	for subscribed < 101 {
+		select {
+		case msg := <-received:
+			if strings.Contains(msg, `"task":"mw"`) {
+				subscribed++
+			}
+		case <-timeout:
+			t.Fatalf("Received %d of 101 subscription requests.", subscribed)
+		}
+	}
+}
+
+func TestOnPong(t *testing.T) {
+	server, u := newMockServer()
+	defer server.Close()
+
+	client := New("test", "test_token", "")
+	client.SetRootURL(u)
+	client.SetAutoReconnect(false)
+	client.SetHeartbeatInterval(50 * time.Millisecond)
+
+	if !client.LastHeartbeat().IsZero() {
+		t.Errorf("Unexpected heartbeat before connecting.")
+	}
+
+	rtts := make(chan time.Duration, 10)
+	client.SetOnPong(func(rtt time.Duration) {
+		select {
+		case rtts <- rtt:
+		default:
+		}
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	select {
+	case rtt := <-rtts:
+		if rtt < 0 || rtt > 5*time.Second {
+			t.Errorf("Unexpected round-trip time %v", rtt)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for pong.")
+	}
+	if client.LastHeartbeat().IsZero() {
+		t.Errorf("Last heartbeat is not recorded.")
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		// Go silent without closing the connection.
+		time.Sleep(2 * time.Second)
+	})
+	defer server.Close()
+
+	client := New("test", "test_token", "", WithURL(u), WithAutoReconnect(false), WithHeartbeat(0, time.Minute), WithReadTimeout(100*time.Millisecond))
+	errs := make(chan error, 1)
+	go func() {
+		errs <- client.ServeContext(context.Background())
+	}()
+
+	select {
+	case err := <-errs:
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Errorf("Expected timeout error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Read deadline did not end the silent connection.")
+	}
+}
+
+func TestTextPing(t *testing.T) {
+	server, u := newScriptedServer(func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, encodeMessage(`[{"ak":"ok","name":"cn"}]`))
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(msg) == "ping" {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte("pong"))
+			}
+		}
+	})
+	defer server.Close()
+
+	client := New("test", "test_token", "", WithURL(u), WithAutoReconnect(false), WithTextPing(true), WithHeartbeat(50*time.Millisecond, time.Minute))
+	rtts := make(chan time.Duration, 10)
+	client.SetOnPong(func(rtt time.Duration) {
+		select {
+		case rtts <- rtt:
+		default:
+		}
+	})
+	errs := make(chan error, 10)
+	client.OnError(func(err error) {
+		errs <- err
+	})
+	go client.Serve()
+	defer client.Close(context.Background())
+
+	// Protocol and text pongs both report the round-trip time.
+	for i := 0; i < 4; i++ {
+		select {
+		case <-rtts:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for pong.")
+		}
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("Text pong is reported as error. %v", err)
+	default:
+	}
+}
+
+func TestDoneAndIdempotentClose(t *testing.T) {
+	server, u := newMockServer()
+	defer server.Close()
+
+	client := New("test", "test_token", "", WithURL(u))
+	done := client.Done()
+	connected := make(chan struct{})
+	client.OnConnect(func() {
+		close(connected)
+	})
+	go client.Serve()
+	<-connected
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := client.Close(ctx); err != nil {
+				t.Errorf("Error while closing. %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Done is not closed after Close.")
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Errorf("Error while closing again. %v", err)
+	}
+
+	// Serving again starts a new run with its own done channel.
+	connected = make(chan struct{})
+	go client.Serve()
+	<-connected
+	select {
+	case <-client.Done():
+		t.Errorf("Done of the new run is closed.")
+	default:
+	}
+	client.Close(context.Background())
+	<-client.Done()
+}